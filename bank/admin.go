@@ -0,0 +1,349 @@
+package bank
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/types"
+)
+
+const (
+	defaultAccountsLimit     = 50
+	defaultAuditLimit        = 50
+	defaultHeldDepositsLimit = 50
+)
+
+// AdminRoutes serves the operator API. Every request must be signed by one of the configured operators, and
+// every change an operator makes is recorded in the audit log.
+func (s *Server) AdminRoutes(r chi.Router) {
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(s.AuthenticationCtx(), s.OperatorCtx())
+
+		r.Get("/accounts", s.handleAdminAccounts)
+		r.Get("/accounts/{address}/balance", s.handleAdminBalance)
+		r.Get("/accounts/{address}/transactions", s.handleAdminTransactions)
+		r.Post("/accounts/{address}/status", s.handleAdminAccountStatus)
+		r.Get("/balances", s.handleAdminTotals)
+		r.Get("/proxies/{address}/escrow", s.handleAdminProxyEscrow)
+		r.Post("/authorizations/{id}/expire", s.handleAdminExpireAuthorization)
+		r.Get("/deposits/held", s.handleAdminHeldDeposits)
+		r.Post("/deposits/held/{hash}/credit", s.handleAdminCreditHeldDeposit)
+		r.Post("/deposits/held/{hash}/return", s.handleAdminReturnHeldDeposit)
+		r.Get("/audit", s.handleAdminAudit)
+	})
+}
+
+func (s *Server) handleAdminAccounts(w http.ResponseWriter, r *http.Request) {
+	var params AccountsParams
+
+	if err := s.Decode(&params, r.URL.Query()); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	params.Type = strings.ToLower(params.Type)
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultAccountsLimit
+	}
+
+	accounts, err := s.BankService.Accounts(r.Context(), params)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	list := make([]envelope, 0, len(accounts))
+	for _, a := range accounts {
+		list = append(list, accountEnvelope(a))
+	}
+
+	res := envelope{"accounts": list}
+	if len(accounts) == params.Limit {
+		res["cursor"] = accounts[len(accounts)-1].ID
+	}
+
+	s.JSON(w, r, http.StatusOK, res)
+}
+
+func (s *Server) handleAdminAccountStatus(w http.ResponseWriter, r *http.Request) {
+	var params AccountStatusParams
+
+	operator, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.DecodeJSON(w, r, &params); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	params.Status = strings.ToLower(params.Status)
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	account, err := s.BankService.SetAccountStatus(r.Context(), operator.String(), chi.URLParam(r, "address"), params.Status, params.Reason)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, accountEnvelope(account))
+}
+
+func accountEnvelope(a Account) envelope {
+	return envelope{
+		"address": a.Address,
+		"type":    a.Type,
+		"status":  a.Status,
+		"fil":     a.Available,
+		"escrow":  a.Escrow,
+	}
+}
+
+func (s *Server) handleAdminBalance(w http.ResponseWriter, r *http.Request) {
+	fil, escrow, err := s.BankService.Balance(r.Context(), chi.URLParam(r, "address"))
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, envelope{"fil": fil, "escrow": escrow})
+}
+
+func (s *Server) handleAdminTransactions(w http.ResponseWriter, r *http.Request) {
+	s.writeTransactions(w, r, chi.URLParam(r, "address"))
+}
+
+func (s *Server) handleAdminTotals(w http.ResponseWriter, r *http.Request) {
+	totals, err := s.BankService.Totals(r.Context())
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, envelope{"accounts": totals.Accounts, "fil": totals.Available, "escrow": totals.Escrow})
+}
+
+func (s *Server) handleAdminProxyEscrow(w http.ResponseWriter, r *http.Request) {
+	escrow, err := s.BankService.ProxyEscrow(r.Context(), chi.URLParam(r, "address"))
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	list := make([]envelope, 0, len(escrow.Authorizations))
+	for _, a := range escrow.Authorizations {
+		list = append(list, authorizationEnvelope(a))
+	}
+
+	s.JSON(w, r, http.StatusOK, envelope{"proxy": escrow.Proxy, "escrow": escrow.Escrow, "authorizations": list})
+}
+
+func (s *Server) handleAdminExpireAuthorization(w http.ResponseWriter, r *http.Request) {
+	var params ExpireParams
+
+	operator, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.DecodeJSON(w, r, &params); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	auth, err := s.BankService.ExpireAuthorization(r.Context(), operator.String(), id, params.Reason)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, authorizationEnvelope(auth))
+}
+
+func (s *Server) handleAdminHeldDeposits(w http.ResponseWriter, r *http.Request) {
+	var params HeldDepositsParams
+
+	if err := s.Decode(&params, r.URL.Query()); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	params.Status = strings.ToLower(params.Status)
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultHeldDepositsLimit
+	}
+
+	deposits, err := s.BankService.HeldDeposits(r.Context(), params)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	list := make([]envelope, 0, len(deposits))
+	for _, d := range deposits {
+		list = append(list, heldDepositEnvelope(d))
+	}
+
+	res := envelope{"deposits": list}
+	if len(deposits) == params.Limit {
+		res["cursor"] = deposits[len(deposits)-1].ID
+	}
+
+	s.JSON(w, r, http.StatusOK, res)
+}
+
+func (s *Server) handleAdminCreditHeldDeposit(w http.ResponseWriter, r *http.Request) {
+	var params CreditHeldDepositParams
+
+	operator, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.DecodeJSON(w, r, &params); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	deposit, err := s.BankService.CreditHeldDeposit(r.Context(), operator.String(), chi.URLParam(r, "hash"), params.Address, params.Reason)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, heldDepositEnvelope(deposit))
+}
+
+func (s *Server) handleAdminReturnHeldDeposit(w http.ResponseWriter, r *http.Request) {
+	var params ReturnHeldDepositParams
+
+	operator, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.DecodeJSON(w, r, &params); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	deposit, err := s.BankService.ReturnHeldDeposit(r.Context(), operator.String(), chi.URLParam(r, "hash"), params.Hash, params.Reason)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, heldDepositEnvelope(deposit))
+}
+
+func heldDepositEnvelope(d HeldDeposit) envelope {
+	res := envelope{
+		"hash":       d.Hash,
+		"from":       d.Sender,
+		"address":    d.Address,
+		"fil":        d.Value,
+		"reason":     d.Reason,
+		"status":     d.Status,
+		"created_at": d.CreatedAt,
+	}
+
+	if d.CreditedTo != "" {
+		res["credited_to"] = d.CreditedTo
+	}
+
+	if d.ReturnHash != "" {
+		res["return_hash"] = d.ReturnHash
+	}
+
+	if !d.ResolvedAt.IsZero() {
+		res["resolved_at"] = d.ResolvedAt
+	}
+
+	return res
+}
+
+func (s *Server) handleAdminAudit(w http.ResponseWriter, r *http.Request) {
+	var params AuditParams
+
+	if err := s.Decode(&params, r.URL.Query()); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultAuditLimit
+	}
+
+	entries, err := s.BankService.AuditLog(r.Context(), params)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	list := make([]envelope, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, envelope{
+			"operator":   e.Operator,
+			"action":     e.Action,
+			"target":     e.Target,
+			"reason":     e.Reason,
+			"created_at": e.CreatedAt,
+		})
+	}
+
+	res := envelope{"entries": list}
+	if len(entries) == params.Limit {
+		res["cursor"] = entries[len(entries)-1].ID
+	}
+
+	s.JSON(w, r, http.StatusOK, res)
+}
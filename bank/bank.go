@@ -1,6 +1,7 @@
 package bank
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,8 +15,16 @@ type Server struct {
 
 	BankService       Service
 	BlockChainService blockchain.Service
+	WithdrawalWorker  *WithdrawalWorker
+	Solvency          *SolvencyAuditor
 
 	CustomReadTimeout time.Duration
+	RequestWindow     time.Duration
+	IdempotencyTTL    time.Duration
+	Operators         []types.Address
+	Blocklist         []types.Address
+	RateLimits        map[string]RouteLimits
+	RateLimiter       RateLimiter
 }
 
 type RegisterParams struct {
@@ -23,6 +32,10 @@ type RegisterParams struct {
 	Price types.FIL `validate:"required,is-valid-fil" json:"price"`
 }
 
+type DeregisterParams struct {
+	Destination string `validate:"required,is-valid-address" json:"dst"`
+}
+
 type DepositParams struct {
 	Amount          types.FIL `validate:"required,is-valid-fil" json:"amount"`
 	TransactionHash string    `validate:"required" json:"hash"`
@@ -34,7 +47,11 @@ type WithdrawParams struct {
 }
 
 type AuthorizeParams struct {
-	Proxy string `validate:"required,is-filecoin-address" json:"proxy"`
+	Proxy     string     `validate:"required,is-filecoin-address" json:"proxy"`
+	Amount    *types.FIL `validate:"omitempty,is-valid-fil" json:"amount"`
+	MaxPrice  *types.FIL `validate:"omitempty,is-valid-fil" json:"max_price"`
+	Mode      string     `validate:"omitempty,oneof=single multi" json:"mode"`
+	ExpiresAt *time.Time `json:"expires_at"`
 }
 
 type RedeemParams struct {
@@ -43,37 +60,377 @@ type RedeemParams struct {
 }
 
 type VerifyParams struct {
-	UUID   uuid.UUID `validate:"required" json:"id"`
+	UUID uuid.UUID `validate:"required" json:"id"`
+}
+
+type OpenChannelParams struct {
+	Proxy  string    `validate:"required,is-filecoin-address" json:"proxy"`
 	Amount types.FIL `validate:"required,is-valid-fil" json:"amount"`
 }
 
+type SettleChannelParams struct {
+	Voucher string `validate:"required" json:"voucher"`
+}
+
+type Channel struct {
+	UUID      uuid.UUID
+	Client    string
+	Proxy     string
+	Balance   types.FIL
+	Redeemed  types.FIL
+	Status    string
+	ClosesAt  time.Time
+	CreatedAt time.Time
+}
+
+type ChannelModel struct {
+	Channel
+	Available types.FIL
+}
+
+type WithdrawModel struct {
+	ID        uuid.UUID
+	Available types.FIL
+	Fee       types.FIL
+}
+
+type Withdrawal struct {
+	ID          uuid.UUID
+	Address     string
+	Destination string
+	Amount      types.FIL
+	Fee         types.FIL
+	Status      string
+	Hash        string
+	Raw         []byte
+	SubmittedAt time.Time
+	CreatedAt   time.Time
+}
+
 type RefundModel struct {
 	Available types.FIL
 	Escrow    types.FIL
 	Expired   types.FIL
 }
 
+type CancelModel struct {
+	Released  types.FIL
+	Available types.FIL
+	Escrow    types.FIL
+}
+
+type SweepReport struct {
+	Accounts int
+	Swept    types.FIL
+}
+
 type AuthModel struct {
 	UUID      uuid.UUID
+	Mode      string
 	Available types.FIL
 	Escrow    types.FIL
+	ExpiresAt time.Time
+}
+
+type LedgerParams struct {
+	At time.Time `schema:"at"`
+}
+
+type LedgerModel struct {
+	Available types.FIL
+	Escrow    types.FIL
+	At        time.Time
+}
+
+type TransactionsParams struct {
+	From   time.Time `schema:"from"`
+	To     time.Time `schema:"to"`
+	Type   string    `schema:"type" validate:"omitempty,oneof=deposit withdraw authorize redeem refund"`
+	Cursor int64     `schema:"cursor" validate:"gte=0"`
+	Limit  int       `schema:"limit" validate:"gte=0,lte=100"`
+}
+
+type AuthorizationsParams struct {
+	Status string    `schema:"status" validate:"omitempty,oneof=open locked redeemed expired refunded cancelled"`
+	Cursor uuid.UUID `schema:"cursor"`
+	Limit  int       `schema:"limit" validate:"gte=0,lte=100"`
+}
+
+type Authorization struct {
+	UUID      uuid.UUID
+	Client    string
+	Proxy     string
+	Mode      string
+	Status    string
+	Amount    types.FIL
+	Remaining types.FIL
+	Redeemed  types.FIL
+	Price     types.FIL
+	MaxPrice  *types.FIL
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Transaction struct {
+	ID            int64
+	TransactionID string
+	Type          string
+	Counterpart   string
+	Amount        types.FIL
+	Status        string
+	CreatedAt     time.Time
 }
 
 type RedeemModel struct {
-	Excess types.FIL
-	SP     types.FIL
-	CLI    types.FIL
+	Excess    types.FIL
+	Remaining types.FIL
+	Fee       types.FIL
+	SP        types.FIL
+	CLI       types.FIL
+}
+
+type IdempotentResponse struct {
+	Status int
+	Body   []byte
+}
+
+// IdempotentRequest is an idempotent request being handled, which IdempotencyCtx puts in the context under
+// CtxKeyIdempotentRequest. Respond, set by the handler, renders the response of the request from the result
+// of its service call, for the database services to store it in the transaction of that call.
+type IdempotentRequest struct {
+	Address string
+	Key     string
+	Respond func(result any) (IdempotentResponse, error)
+}
+
+type FeesParams struct {
+	From   time.Time `schema:"from"`
+	To     time.Time `schema:"to"`
+	Type   string    `schema:"type" validate:"omitempty,oneof=deposit withdraw redeem"`
+	Cursor int64     `schema:"cursor" validate:"gte=0"`
+	Limit  int       `schema:"limit" validate:"gte=0,lte=100"`
+}
+
+type CollectedFee struct {
+	ID            int64
+	TransactionID string
+	Type          string
+	Address       string
+	Amount        types.FIL
+	CreatedAt     time.Time
+}
+
+type FeesReport struct {
+	Total  types.FIL
+	ByType map[string]types.FIL
+	Fees   []CollectedFee
+}
+
+type AccountsParams struct {
+	Type   string `schema:"type" validate:"omitempty,oneof=client provider"`
+	Cursor int64  `schema:"cursor" validate:"gte=0"`
+	Limit  int    `schema:"limit" validate:"gte=0,lte=100"`
+}
+
+type Account struct {
+	ID        int64
+	Address   string
+	Type      string
+	Status    string
+	Available types.FIL
+	Escrow    types.FIL
+}
+
+type Totals struct {
+	Accounts  int
+	Available types.FIL
+	Escrow    types.FIL
+}
+
+type ProxyEscrow struct {
+	Proxy          string
+	Escrow         types.FIL
+	Authorizations []Authorization
+}
+
+type ExpireParams struct {
+	Reason string `validate:"required,max=255" json:"reason"`
+}
+
+type AccountStatusParams struct {
+	Status string `validate:"required,oneof=active frozen closed" json:"status"`
+	Reason string `validate:"required,max=255" json:"reason"`
+}
+
+type AuditParams struct {
+	Cursor int64 `schema:"cursor" validate:"gte=0"`
+	Limit  int   `schema:"limit" validate:"gte=0,lte=100"`
+}
+
+// Audit log actions, one for each change an operator can make.
+const (
+	AuditExpireAuthorization = "expire_authorization"
+	AuditActivateAccount     = "activate_account"
+	AuditFreezeAccount       = "freeze_account"
+	AuditCloseAccount        = "close_account"
+	AuditCreditHeldDeposit   = "credit_held_deposit"
+	AuditReturnHeldDeposit   = "return_held_deposit"
+)
+
+// AuditAccountStatus returns the audit log action of an operator setting an account to a status.
+func AuditAccountStatus(status string) string {
+	switch status {
+	case "frozen":
+		return AuditFreezeAccount
+	case "closed":
+		return AuditCloseAccount
+	default:
+		return AuditActivateAccount
+	}
+}
+
+type AuditEntry struct {
+	ID        int64
+	Operator  string
+	Action    string
+	Target    string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// Liability is what the bank owes a wallet: its balance and escrow, and its withdrawals not yet paid out.
+type Liability struct {
+	Address string
+	Amount  types.FIL
+}
+
+// TransactionType is what a chain entry moved: a deposit into the wallet of the bank, a withdrawal out of it,
+// or the return of a held deposit to its sender.
+type TransactionType string
+
+const (
+	TransactionDeposit  TransactionType = "Deposit"
+	TransactionWithdraw TransactionType = "Withdraw"
+	TransactionReturn   TransactionType = "Return"
+)
+
+// TransactionStatus is where a chain entry stands. A withdrawal is Submitted once signed, and Completed or
+// Reversed once its transfer is settled. Held deposits carry their own status instead.
+type TransactionStatus string
+
+const (
+	TransactionCompleted TransactionStatus = "Completed"
+	TransactionSubmitted TransactionStatus = "Submitted"
+	TransactionReversed  TransactionStatus = "Reversed"
+)
+
+// ChainEntry is a deposit or a withdrawal of the bank, which should match a transfer on chain. Counterpart
+// is who the FIL came from for a deposit, and who it was paid to for a withdrawal.
+type ChainEntry struct {
+	Type        TransactionType   `json:"type"`
+	ID          string            `json:"id"`
+	Hash        string            `json:"hash"`
+	Address     string            `json:"address"`
+	Counterpart string            `json:"counterpart"`
+	Value       types.FIL         `json:"value"`
+	Status      TransactionStatus `json:"status"`
+	At          time.Time         `json:"at"`
+}
+
+// ChainEntriesParams select the entries with one of the hashes, or recorded between From and To. Deposits
+// are recorded once their transfer is verified, and withdrawals when their transfer is signed.
+type ChainEntriesParams struct {
+	Hashes []string
+	From   time.Time
+	To     time.Time
+}
+
+// HeldDeposit is a transfer to the bank's wallet that could not be credited to its sender, because the
+// sender has no Filecoin address, is a storage provider or has a frozen or closed account. It is kept by
+// hash until an operator credits it to an account or returns it. Sender is the Ethereum address the
+// transfer came from, and Address its Filecoin address, if it has one.
+type HeldDeposit struct {
+	ID         int64
+	Hash       string
+	Sender     string
+	Address    string
+	Value      types.FIL
+	Reason     string
+	Status     string
+	CreditedTo string
+	ReturnHash string
+	CreatedAt  time.Time
+	ResolvedAt time.Time
+}
+
+type HeldDepositsParams struct {
+	Status string `schema:"status" validate:"omitempty,oneof=held credited returned"`
+	Cursor int64  `schema:"cursor" validate:"gte=0"`
+	Limit  int    `schema:"limit" validate:"gte=0,lte=100"`
+}
+
+type CreditHeldDepositParams struct {
+	Address string `validate:"required,is-filecoin-address" json:"address"`
+	Reason  string `validate:"required,max=255" json:"reason"`
+}
+
+// ReturnHeldDepositParams record the transfer an operator sent the held deposit back with.
+type ReturnHeldDepositParams struct {
+	Hash   string `validate:"required" json:"hash"`
+	Reason string `validate:"required,max=255" json:"reason"`
 }
 
 type Service interface {
-	RegisterProxy(spid string, source string, price types.FIL) error
-	ValidateBlockchainTransaction(hash string) (bool, error)
-	Deposit(address string, price types.FIL, transactionHash string) (types.FIL, error)
-	Withdraw(address string, destination string, amount types.FIL) (types.FIL, error)
-	RegisterWithdrawTransaction(address string, destination string, amount types.FIL, transactionHash string) error
-	Balance(address string) (types.FIL, types.FIL, error)
-	Authorize(address string, proxy string) (AuthModel, error)
-	Refund(address string) (RefundModel, error)
-	Verify(address string, uuid uuid.UUID, amount types.FIL) error
-	Redeem(address string, uuid uuid.UUID, amount types.FIL) (RedeemModel, error)
+	RegisterProxy(ctx context.Context, spid string, source string, price types.FIL) error
+	Deregister(ctx context.Context, address string, destination string) error
+	FinalizeDeregistrations(ctx context.Context, limit int) (int, error)
+	ValidateBlockchainTransaction(ctx context.Context, hash string) (bool, error)
+	Deposit(ctx context.Context, address string, price types.FIL, transactionHash string) (types.FIL, error)
+	Withdraw(ctx context.Context, address string, destination string, amount types.FIL) (WithdrawModel, error)
+	Withdrawal(ctx context.Context, address string, id uuid.UUID) (Withdrawal, error)
+	ClaimWithdrawals(ctx context.Context, limit int, lease time.Duration) ([]Withdrawal, error)
+	SubmitWithdrawal(ctx context.Context, id uuid.UUID, hash string, raw []byte) error
+	ResetWithdrawal(ctx context.Context, id uuid.UUID) error
+	CompleteWithdrawal(ctx context.Context, id uuid.UUID) error
+	ReverseWithdrawal(ctx context.Context, id uuid.UUID) error
+	Balance(ctx context.Context, address string) (types.FIL, types.FIL, error)
+	Authorize(ctx context.Context, address string, params AuthorizeParams) (AuthModel, error)
+	CancelAuthorization(ctx context.Context, address string, id uuid.UUID) (CancelModel, error)
+	Authorizations(ctx context.Context, address string, params AuthorizationsParams) ([]Authorization, error)
+	Authorization(ctx context.Context, address string, id uuid.UUID) (Authorization, error)
+	Refund(ctx context.Context, address string) (RefundModel, error)
+	SweepEscrow(ctx context.Context, limit int) (SweepReport, error)
+	Verify(ctx context.Context, address string, uuid uuid.UUID) (types.FIL, error)
+	Redeem(ctx context.Context, address string, uuid uuid.UUID, amount types.FIL) (RedeemModel, error)
+	RegisterNonce(ctx context.Context, address string, nonce string, expiresAt time.Time) error
+	PruneNonces(ctx context.Context, before time.Time) (int, error)
+	BeginIdempotentRequest(ctx context.Context, address string, key string, hash string, expiresAt time.Time) (*IdempotentResponse, error)
+	CompleteIdempotentRequest(ctx context.Context, address string, key string, response IdempotentResponse) error
+	ReleaseIdempotentRequest(ctx context.Context, address string, key string) error
+	PruneIdempotencyKeys(ctx context.Context, before time.Time) (int, error)
+	TakeToken(ctx context.Context, key string, limit RateLimit) (time.Duration, error)
+	PruneBuckets(ctx context.Context, before time.Time) (int, error)
+	Ledger(ctx context.Context, address string, at time.Time) (LedgerModel, error)
+	Transactions(ctx context.Context, address string, params TransactionsParams) ([]Transaction, error)
+	Fees(ctx context.Context, address string, params FeesParams) (FeesReport, error)
+	OpenChannel(ctx context.Context, address string, proxy string, amount types.FIL) (ChannelModel, error)
+	Channel(ctx context.Context, address string, id uuid.UUID) (Channel, error)
+	SettleChannel(ctx context.Context, address string, id uuid.UUID, amount types.FIL) (Channel, error)
+	CloseChannel(ctx context.Context, address string, id uuid.UUID) (Channel, error)
+	SweepChannels(ctx context.Context, limit int) (int, error)
+	Accounts(ctx context.Context, params AccountsParams) ([]Account, error)
+	Totals(ctx context.Context) (Totals, error)
+	ProxyEscrow(ctx context.Context, proxy string) (ProxyEscrow, error)
+	ExpireAuthorization(ctx context.Context, operator string, id uuid.UUID, reason string) (Authorization, error)
+	SetAccountStatus(ctx context.Context, operator string, address string, status string, reason string) (Account, error)
+	AuditLog(ctx context.Context, params AuditParams) ([]AuditEntry, error)
+	Liabilities(ctx context.Context) ([]Liability, error)
+	ChainEntries(ctx context.Context, params ChainEntriesParams) ([]ChainEntry, error)
+	HoldDeposit(ctx context.Context, deposit HeldDeposit) error
+	HeldDeposits(ctx context.Context, params HeldDepositsParams) ([]HeldDeposit, error)
+	CreditHeldDeposit(ctx context.Context, operator string, hash string, address string, reason string) (HeldDeposit, error)
+	ReturnHeldDeposit(ctx context.Context, operator string, hash string, returnHash string, reason string) (HeldDeposit, error)
+	ChainCursor(ctx context.Context, name string) (uint64, bool, error)
+	SetChainCursor(ctx context.Context, name string, block uint64) error
 }
@@ -0,0 +1,295 @@
+package banktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+func testDeposit(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	_, _, err := service.Balance(ctx, clientAddress)
+	require.Error(t, err, "an unknown account has no balance")
+
+	balance, err := service.Deposit(ctx, clientAddress, atto(100), "hash-1")
+	require.NoError(t, err)
+	requireFIL(t, 100, balance)
+
+	balance, err = service.Deposit(ctx, clientAddress, atto(50), "hash-2")
+	require.NoError(t, err)
+	requireFIL(t, 150, balance)
+	requireBalance(t, service, clientAddress, 150, 0)
+
+	ok, err := service.ValidateBlockchainTransaction(ctx, "hash-1")
+	require.ErrorIs(t, err, bank.ErrTransactionExists)
+	assert.False(t, ok)
+
+	_, err = service.Deposit(ctx, clientAddress, atto(100), "hash-1")
+	require.ErrorIs(t, err, bank.ErrTransactionExists, "a transaction is credited once")
+	requireBalance(t, service, clientAddress, 150, 0)
+
+	ok, err = service.ValidateBlockchainTransaction(ctx, "hash-3")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	register(t, service, proxyAddress, 10)
+
+	_, err = service.Deposit(ctx, proxyAddress, atto(100), "hash-3")
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed)
+}
+
+func testRegisterProxy(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+
+	err := service.RegisterProxy(ctx, "sp", clientAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed, "a client cannot register as a storage provider")
+
+	register(t, service, proxyAddress, 10)
+	requireBalance(t, service, proxyAddress, 0, 0)
+
+	// Registering again updates the price new authorizations are made at.
+	register(t, service, proxyAddress, 20)
+
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+	requireFIL(t, 20, model.Escrow)
+
+	auth, err := service.Authorization(ctx, clientAddress, model.UUID)
+	require.NoError(t, err)
+	requireFIL(t, 20, auth.Price)
+}
+
+func testWithdraw(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	_, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(10))
+	require.Error(t, err, "an unknown account cannot withdraw")
+
+	deposit(t, service, clientAddress, 100)
+
+	_, err = service.Withdraw(ctx, clientAddress, walletAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed, "the bank's wallet is not a destination")
+
+	_, err = service.Withdraw(ctx, clientAddress, payoutAddress, atto(101))
+	require.ErrorIs(t, err, bank.ErrInsufficientFunds)
+
+	model, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(40))
+	require.NoError(t, err)
+	requireFIL(t, 60, model.Available)
+	requireFIL(t, 0, model.Fee)
+
+	withdrawal, err := service.Withdrawal(ctx, clientAddress, model.ID)
+	require.NoError(t, err)
+	assert.Equal(t, payoutAddress, withdrawal.Destination)
+	assert.Equal(t, "Pending", withdrawal.Status)
+	requireFIL(t, 40, withdrawal.Amount)
+
+	_, err = service.Withdrawal(ctx, otherClient, model.ID)
+	require.ErrorIs(t, err, bank.ErrWithdrawalNotFound)
+
+	// Withdrawing everything closes the account of a client without authorizations.
+	_, err = service.Withdraw(ctx, clientAddress, payoutAddress, atto(60))
+	require.NoError(t, err)
+
+	_, _, err = service.Balance(ctx, clientAddress)
+	require.Error(t, err)
+
+	// A client with authorizations is kept, as they are history.
+	register(t, service, proxyAddress, 10)
+	deposit(t, service, otherClient, 10)
+
+	auth, err := service.Authorize(ctx, otherClient, bank.AuthorizeParams{Proxy: proxyAddress})
+	require.NoError(t, err)
+
+	_, err = service.CancelAuthorization(ctx, otherClient, auth.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Withdraw(ctx, otherClient, payoutAddress, atto(10))
+	require.NoError(t, err)
+	requireBalance(t, service, otherClient, 0, 0)
+}
+
+func testWithdrawalLifecycle(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+
+	first, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(30))
+	require.NoError(t, err)
+
+	second, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(20))
+	require.NoError(t, err)
+
+	claimed, err := service.ClaimWithdrawals(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, first.ID, claimed[0].ID, "withdrawals are claimed oldest first")
+	assert.Equal(t, second.ID, claimed[1].ID)
+
+	claimed, err = service.ClaimWithdrawals(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "leased withdrawals are not claimed again")
+
+	require.Error(t, service.CompleteWithdrawal(ctx, first.ID), "a pending withdrawal cannot complete")
+	require.Error(t, service.ResetWithdrawal(ctx, first.ID), "a pending withdrawal cannot be reset")
+
+	require.NoError(t, service.SubmitWithdrawal(ctx, first.ID, "0xhash", []byte("raw")))
+	require.Error(t, service.SubmitWithdrawal(ctx, first.ID, "0xhash", []byte("raw")), "a withdrawal is submitted once")
+
+	withdrawal, err := service.Withdrawal(ctx, clientAddress, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Submitted", withdrawal.Status)
+	assert.Equal(t, "0xhash", withdrawal.Hash)
+	assert.Equal(t, []byte("raw"), withdrawal.Raw)
+
+	require.NoError(t, service.ResetWithdrawal(ctx, first.ID))
+
+	withdrawal, err = service.Withdrawal(ctx, clientAddress, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Pending", withdrawal.Status)
+	assert.Empty(t, withdrawal.Hash)
+
+	claimed, err = service.ClaimWithdrawals(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "a reset withdrawal is claimed again")
+	assert.Equal(t, first.ID, claimed[0].ID)
+
+	require.NoError(t, service.SubmitWithdrawal(ctx, first.ID, "0xhash", []byte("raw")))
+	require.NoError(t, service.CompleteWithdrawal(ctx, first.ID))
+	require.Error(t, service.ReverseWithdrawal(ctx, first.ID), "a completed withdrawal cannot be reversed")
+
+	require.NoError(t, service.ReverseWithdrawal(ctx, second.ID))
+	requireBalance(t, service, clientAddress, 70, 0)
+
+	withdrawal, err = service.Withdrawal(ctx, clientAddress, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Reversed", withdrawal.Status)
+
+	// Reversing the withdrawal of a closed account opens it again.
+	last, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(70))
+	require.NoError(t, err)
+
+	_, _, err = service.Balance(ctx, clientAddress)
+	require.Error(t, err)
+
+	require.NoError(t, service.ReverseWithdrawal(ctx, last.ID))
+	requireBalance(t, service, clientAddress, 70, 0)
+}
+
+func testDeregister(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	require.ErrorIs(t, service.Deregister(ctx, clientAddress, payoutAddress), bank.ErrOperationNotAllowed)
+	require.ErrorIs(t, service.Deregister(ctx, proxyAddress, walletAddress), bank.ErrOperationNotAllowed)
+
+	auth := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+
+	channel, err := service.OpenChannel(ctx, clientAddress, proxyAddress, atto(20))
+	require.NoError(t, err)
+
+	require.NoError(t, service.Deregister(ctx, proxyAddress, payoutAddress))
+	require.ErrorIs(t, service.Deregister(ctx, proxyAddress, payoutAddress), bank.ErrProxyNotActive)
+
+	err = service.RegisterProxy(ctx, "sp", proxyAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrProxyNotActive, "a storage provider is not reactivated while its channels are closing")
+
+	_, err = service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress})
+	require.ErrorIs(t, err, bank.ErrProxyNotActive)
+
+	_, err = service.OpenChannel(ctx, clientAddress, proxyAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrProxyNotActive)
+
+	c, err := service.Channel(ctx, proxyAddress, channel.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Closing", c.Status, "open channels start closing")
+
+	// Outstanding authorizations and channels can still be redeemed.
+	finalized, err := service.FinalizeDeregistrations(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, finalized)
+
+	_, err = service.Verify(ctx, proxyAddress, auth.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, auth.UUID, atto(10))
+	require.NoError(t, err)
+
+	_, err = service.SettleChannel(ctx, proxyAddress, channel.UUID, atto(5))
+	require.NoError(t, err)
+
+	time.Sleep(settleWindow)
+
+	finalized, err = service.FinalizeDeregistrations(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, finalized)
+
+	requireBalance(t, service, proxyAddress, 0, 0)
+
+	claimed, err := service.ClaimWithdrawals(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, proxyAddress, claimed[0].Address)
+	assert.Equal(t, payoutAddress, claimed[0].Destination)
+	requireFIL(t, 15, claimed[0].Amount)
+
+	finalized, err = service.FinalizeDeregistrations(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, finalized, "a storage provider is finalized once")
+
+	// Registering again reactivates the storage provider.
+	register(t, service, proxyAddress, 10)
+	authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+}
+
+func testDeregisterDust(t *testing.T, factory Factory) {
+	fees, err := bank.ParseFeeSchedule(bank.Fees{Withdraw: bank.FeeConfig{Flat: atto(5)}})
+	require.NoError(t, err)
+
+	cfg := defaultConfig()
+	cfg.Fees = fees
+	service := factory(t, cfg)
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	auth := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+
+	_, err = service.Verify(ctx, proxyAddress, auth.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, auth.UUID, atto(3))
+	require.NoError(t, err)
+	requireBalance(t, service, proxyAddress, 3, 0)
+
+	require.NoError(t, service.Deregister(ctx, proxyAddress, payoutAddress))
+
+	finalized, err := service.FinalizeDeregistrations(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, finalized)
+
+	// A balance that does not cover the withdrawal fee is kept as the fee instead of being stranded.
+	requireBalance(t, service, proxyAddress, 0, 0)
+
+	claimed, err := service.ClaimWithdrawals(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	report, err := service.Fees(ctx, walletAddress, bank.FeesParams{Limit: 10})
+	require.NoError(t, err)
+	requireFIL(t, 3, report.ByType["Withdraw"])
+}
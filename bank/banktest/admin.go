@@ -0,0 +1,229 @@
+package banktest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+const operatorAddress = "f1operator"
+
+func testAdmin(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	register(t, service, proxyAddress, 10)
+	deposit(t, service, clientAddress, 100)
+	deposit(t, service, otherClient, 50)
+
+	accounts, err := service.Accounts(ctx, bank.AccountsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+	assert.Equal(t, otherClient, accounts[0].Address, "the newest account comes first")
+	assert.Equal(t, proxyAddress, accounts[2].Address)
+	assert.Equal(t, "Storage Provider", accounts[2].Type)
+
+	page, err := service.Accounts(ctx, bank.AccountsParams{Type: "client", Cursor: accounts[0].ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, clientAddress, page[0].Address)
+	assert.Equal(t, "Client", page[0].Type)
+	assert.Equal(t, "Active", page[0].Status)
+	requireFIL(t, 100, page[0].Available)
+
+	single := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+	locked := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(30)})
+
+	_, err = service.Verify(ctx, proxyAddress, locked.UUID)
+	require.NoError(t, err)
+
+	totals, err := service.Totals(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, totals.Accounts)
+	requireFIL(t, 110, totals.Available)
+	requireFIL(t, 40, totals.Escrow)
+
+	escrow, err := service.ProxyEscrow(ctx, proxyAddress)
+	require.NoError(t, err)
+	requireFIL(t, 40, escrow.Escrow)
+	require.Len(t, escrow.Authorizations, 2)
+	assert.Equal(t, locked.UUID, escrow.Authorizations[0].UUID)
+
+	_, err = service.ExpireAuthorization(ctx, operatorAddress, uuid.New(), "unknown")
+	require.ErrorIs(t, err, bank.ErrAuthNotFound)
+
+	auth, err := service.ExpireAuthorization(ctx, operatorAddress, locked.UUID, "proxy stopped responding")
+	require.NoError(t, err)
+	assert.Equal(t, "Expired", auth.Status)
+	requireFIL(t, 30, auth.Remaining)
+
+	_, err = service.ExpireAuthorization(ctx, operatorAddress, locked.UUID, "again")
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "an expired authorization cannot be expired again")
+
+	_, err = service.Redeem(ctx, proxyAddress, locked.UUID, atto(10))
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "an expired authorization cannot be redeemed")
+
+	refund, err := service.Refund(ctx, clientAddress)
+	require.NoError(t, err)
+	requireFIL(t, 30, refund.Expired)
+
+	escrow, err = service.ProxyEscrow(ctx, proxyAddress)
+	require.NoError(t, err)
+	requireFIL(t, 10, escrow.Escrow)
+	require.Len(t, escrow.Authorizations, 1)
+	assert.Equal(t, single.UUID, escrow.Authorizations[0].UUID)
+
+	log, err := service.AuditLog(ctx, bank.AuditParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, log, 1)
+	assert.Equal(t, operatorAddress, log[0].Operator)
+	assert.Equal(t, bank.AuditExpireAuthorization, log[0].Action)
+	assert.Equal(t, locked.UUID.String(), log[0].Target)
+	assert.Equal(t, "proxy stopped responding", log[0].Reason)
+}
+
+func testAccountStatus(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	register(t, service, proxyAddress, 10)
+	deposit(t, service, clientAddress, 100)
+
+	auth := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+
+	_, err := service.SetAccountStatus(ctx, operatorAddress, otherClient, "frozen", "unknown")
+	require.ErrorIs(t, err, bank.ErrAccountNotFound)
+
+	account, err := service.SetAccountStatus(ctx, operatorAddress, clientAddress, "frozen", "stolen keys")
+	require.NoError(t, err)
+	assert.Equal(t, "Frozen", account.Status)
+	requireFIL(t, 90, account.Available)
+	requireFIL(t, 10, account.Escrow)
+
+	_, err = service.Deposit(ctx, clientAddress, atto(10), uuid.NewString())
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	_, err = service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress})
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	_, err = service.OpenChannel(ctx, clientAddress, proxyAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	_, err = service.Withdraw(ctx, clientAddress, payoutAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	requireBalance(t, service, clientAddress, 90, 10)
+
+	// The storage provider is still paid for the authorizations a frozen client made.
+	_, err = service.Verify(ctx, proxyAddress, auth.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, auth.UUID, atto(10))
+	require.NoError(t, err)
+
+	account, err = service.SetAccountStatus(ctx, operatorAddress, clientAddress, "active", "keys rotated")
+	require.NoError(t, err)
+	assert.Equal(t, "Active", account.Status)
+
+	_, err = service.Withdraw(ctx, clientAddress, payoutAddress, atto(10))
+	require.NoError(t, err)
+
+	auth = authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+
+	_, err = service.Verify(ctx, proxyAddress, auth.UUID)
+	require.NoError(t, err)
+
+	_, err = service.SetAccountStatus(ctx, operatorAddress, proxyAddress, "closed", "abuse")
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, auth.UUID, atto(10))
+	require.ErrorIs(t, err, bank.ErrAccountClosed)
+
+	err = service.Deregister(ctx, proxyAddress, payoutAddress)
+	require.ErrorIs(t, err, bank.ErrAccountClosed)
+
+	_, err = service.SetAccountStatus(ctx, operatorAddress, proxyAddress, "active", "mistake")
+	require.ErrorIs(t, err, bank.ErrAccountClosed, "a closed account cannot be reopened")
+
+	log, err := service.AuditLog(ctx, bank.AuditParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, log, 3)
+	assert.Equal(t, bank.AuditCloseAccount, log[0].Action)
+	assert.Equal(t, proxyAddress, log[0].Target)
+	assert.Equal(t, bank.AuditActivateAccount, log[1].Action)
+	assert.Equal(t, bank.AuditFreezeAccount, log[2].Action)
+	assert.Equal(t, clientAddress, log[2].Target)
+	assert.Equal(t, "stolen keys", log[2].Reason)
+}
+
+func testFrozenOperations(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	// Nothing is allowed to frozen accounts unless configured.
+	service := factory(t, defaultConfig())
+
+	register(t, service, proxyAddress, 10)
+	deposit(t, service, clientAddress, 100)
+
+	auth := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+	channel, err := service.OpenChannel(ctx, clientAddress, proxyAddress, atto(10))
+	require.NoError(t, err)
+
+	_, err = service.SetAccountStatus(ctx, operatorAddress, clientAddress, "frozen", "stolen keys")
+	require.NoError(t, err)
+
+	_, err = service.CancelAuthorization(ctx, clientAddress, auth.UUID)
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	_, err = service.Refund(ctx, clientAddress)
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	_, err = service.CloseChannel(ctx, clientAddress, channel.UUID)
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	_, err = service.SetAccountStatus(ctx, operatorAddress, proxyAddress, "frozen", "abuse")
+	require.NoError(t, err)
+
+	_, err = service.Verify(ctx, proxyAddress, auth.UUID)
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	err = service.RegisterProxy(ctx, "sp", proxyAddress, atto(1))
+	require.ErrorIs(t, err, bank.ErrAccountFrozen, "a frozen storage provider cannot change its price")
+
+	// The configured operations are allowed, and only those.
+	cfg := defaultConfig()
+	cfg.FrozenAllowed = []bank.Operation{bank.OperationCancel, bank.OperationCloseChannel}
+	service = factory(t, cfg)
+
+	register(t, service, proxyAddress, 10)
+	deposit(t, service, clientAddress, 100)
+
+	auth = authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+	channel, err = service.OpenChannel(ctx, clientAddress, proxyAddress, atto(10))
+	require.NoError(t, err)
+
+	_, err = service.SetAccountStatus(ctx, operatorAddress, clientAddress, "frozen", "stolen keys")
+	require.NoError(t, err)
+
+	_, err = service.CancelAuthorization(ctx, clientAddress, auth.UUID)
+	require.NoError(t, err)
+
+	_, err = service.CloseChannel(ctx, clientAddress, channel.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Withdraw(ctx, clientAddress, payoutAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrAccountFrozen)
+
+	// The channel only starts closing, and its funds stay held until its settle window is over.
+	requireBalance(t, service, clientAddress, 90, 10)
+
+	_, err = service.SetAccountStatus(ctx, operatorAddress, clientAddress, "closed", "abuse")
+	require.NoError(t, err)
+
+	_, err = service.Refund(ctx, clientAddress)
+	require.ErrorIs(t, err, bank.ErrAccountClosed, "nothing is allowed to closed accounts")
+}
@@ -0,0 +1,338 @@
+package banktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+func testAuthorize(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+
+	_, err := service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress})
+	require.Error(t, err, "an unknown storage provider cannot be authorized")
+
+	register(t, service, proxyAddress, 10)
+
+	_, err = service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress, MaxPrice: amount(9)})
+	require.ErrorIs(t, err, bank.ErrPriceAboveMax)
+
+	_, err = service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(9)})
+	require.ErrorIs(t, err, bank.ErrAmountBelowPrice)
+
+	_, err = service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(101)})
+	require.ErrorIs(t, err, bank.ErrInsufficientFunds)
+
+	tooSoon := time.Now().Add(expiry / 4)
+	_, err = service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress, ExpiresAt: &tooSoon})
+	require.ErrorIs(t, err, bank.ErrExpiryOutOfBounds)
+
+	tooLate := time.Now().Add(48 * time.Hour)
+	_, err = service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress, ExpiresAt: &tooLate})
+	require.ErrorIs(t, err, bank.ErrExpiryOutOfBounds)
+
+	requireBalance(t, service, clientAddress, 100, 0)
+
+	before := time.Now()
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+	assert.Equal(t, "Single", model.Mode)
+	requireFIL(t, 90, model.Available)
+	requireFIL(t, 10, model.Escrow)
+	assert.WithinDuration(t, before.Add(time.Hour), model.ExpiresAt, time.Minute, "the expiry defaults to the escrow deadline")
+
+	requested := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	model = authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(30), MaxPrice: amount(10), Mode: "multi", ExpiresAt: &requested})
+	assert.Equal(t, "Multi", model.Mode)
+	requireFIL(t, 60, model.Available)
+	requireFIL(t, 30, model.Escrow)
+	assert.True(t, requested.Equal(model.ExpiresAt))
+
+	requireBalance(t, service, clientAddress, 60, 40)
+
+	auth, err := service.Authorization(ctx, proxyAddress, model.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, clientAddress, auth.Client)
+	assert.Equal(t, proxyAddress, auth.Proxy)
+	assert.Equal(t, "Open", auth.Status)
+	requireFIL(t, 30, auth.Amount)
+	requireFIL(t, 30, auth.Remaining)
+	requireFIL(t, 10, auth.Price)
+	require.NotNil(t, auth.MaxPrice)
+	requireFIL(t, 10, *auth.MaxPrice)
+
+	_, err = service.Authorization(ctx, otherClient, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotFound)
+}
+
+func testRedeemSingle(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(25)})
+
+	// The price the authorization was made at applies, even when the storage provider changes it.
+	register(t, service, proxyAddress, 20)
+
+	_, err := service.Redeem(ctx, proxyAddress, model.UUID, atto(10))
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "an authorization is verified before it is redeemed")
+
+	_, err = service.Verify(ctx, clientAddress, model.UUID)
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed)
+
+	_, err = service.Verify(ctx, proxyAddress, uuid.New())
+	require.ErrorIs(t, err, bank.ErrAuthNotFound)
+
+	price, err := service.Verify(ctx, proxyAddress, model.UUID)
+	require.NoError(t, err)
+	requireFIL(t, 10, price)
+
+	_, err = service.Verify(ctx, proxyAddress, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthLocked)
+
+	_, err = service.CancelAuthorization(ctx, clientAddress, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotCancellable)
+
+	_, err = service.Redeem(ctx, proxyAddress, model.UUID, atto(11))
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "a retrieval is not charged above its price")
+
+	redeem, err := service.Redeem(ctx, proxyAddress, model.UUID, atto(10))
+	require.NoError(t, err)
+	requireFIL(t, 15, redeem.Excess)
+	requireFIL(t, 0, redeem.Remaining)
+	requireFIL(t, 0, redeem.Fee)
+	requireFIL(t, 10, redeem.SP)
+	requireFIL(t, 90, redeem.CLI)
+
+	requireBalance(t, service, clientAddress, 90, 0)
+	requireBalance(t, service, proxyAddress, 10, 0)
+
+	auth, err := service.Authorization(ctx, clientAddress, model.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Redeemed", auth.Status)
+	requireFIL(t, 10, auth.Redeemed)
+	requireFIL(t, 0, auth.Remaining)
+
+	_, err = service.Redeem(ctx, proxyAddress, model.UUID, atto(10))
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "an authorization is redeemed once")
+
+	_, err = service.Verify(ctx, proxyAddress, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotFound)
+}
+
+func testRedeemMulti(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(25), Mode: "multi"})
+
+	for _, remaining := range []int64{15, 5} {
+		_, err := service.Verify(ctx, proxyAddress, model.UUID)
+		require.NoError(t, err)
+
+		redeem, err := service.Redeem(ctx, proxyAddress, model.UUID, atto(10))
+		require.NoError(t, err)
+		requireFIL(t, 0, redeem.Excess)
+		requireFIL(t, remaining, redeem.Remaining)
+
+		auth, err := service.Authorization(ctx, clientAddress, model.UUID)
+		require.NoError(t, err)
+		assert.Equal(t, "Open", auth.Status, "a multi authorization is unlocked for the next retrieval")
+		requireFIL(t, remaining, auth.Remaining)
+	}
+
+	requireBalance(t, service, clientAddress, 75, 5)
+	requireBalance(t, service, proxyAddress, 20, 0)
+
+	_, err := service.Verify(ctx, proxyAddress, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "the remaining amount does not cover the price")
+
+	// An authorization used up exactly is redeemed.
+	model = authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(10), Mode: "multi"})
+
+	_, err = service.Verify(ctx, proxyAddress, model.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, model.UUID, atto(10))
+	require.NoError(t, err)
+
+	auth, err := service.Authorization(ctx, clientAddress, model.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Redeemed", auth.Status)
+	requireFIL(t, 10, auth.Redeemed)
+}
+
+func testCancel(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	deposit(t, service, otherClient, 100)
+	register(t, service, proxyAddress, 10)
+
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(30)})
+
+	_, err := service.CancelAuthorization(ctx, otherClient, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "only the client cancels its authorizations")
+
+	cancel, err := service.CancelAuthorization(ctx, clientAddress, model.UUID)
+	require.NoError(t, err)
+	requireFIL(t, 30, cancel.Released)
+	requireFIL(t, 100, cancel.Available)
+	requireFIL(t, 0, cancel.Escrow)
+
+	_, err = service.CancelAuthorization(ctx, clientAddress, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotFound)
+
+	auth, err := service.Authorization(ctx, clientAddress, model.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Cancelled", auth.Status)
+
+	_, err = service.Verify(ctx, proxyAddress, model.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotFound)
+}
+
+func testRefund(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	_, err := service.Refund(ctx, clientAddress)
+	require.ErrorIs(t, err, bank.ErrNothingToRefund)
+
+	expiresAt := time.Now().Add(expiry)
+	expiring := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(20), ExpiresAt: &expiresAt})
+	locked := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(30), ExpiresAt: &expiresAt})
+	authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+
+	_, err = service.Verify(ctx, proxyAddress, locked.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Refund(ctx, clientAddress)
+	require.ErrorIs(t, err, bank.ErrNothingToRefund, "authorizations are refunded once they expire")
+
+	time.Sleep(time.Until(expiresAt))
+
+	auth, err := service.Authorization(ctx, clientAddress, expiring.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Expired", auth.Status)
+	requireFIL(t, 20, auth.Remaining)
+
+	_, err = service.Verify(ctx, proxyAddress, expiring.UUID)
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "an expired authorization cannot be verified")
+
+	_, err = service.Redeem(ctx, proxyAddress, locked.UUID, atto(10))
+	require.ErrorIs(t, err, bank.ErrAuthNotFound, "an expired authorization cannot be redeemed")
+
+	refund, err := service.Refund(ctx, clientAddress)
+	require.NoError(t, err)
+	requireFIL(t, 50, refund.Expired)
+	requireFIL(t, 90, refund.Available)
+	requireFIL(t, 10, refund.Escrow)
+
+	_, err = service.Refund(ctx, clientAddress)
+	require.ErrorIs(t, err, bank.ErrNothingToRefund)
+
+	auth, err = service.Authorization(ctx, clientAddress, locked.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Refunded", auth.Status)
+	requireFIL(t, 0, auth.Remaining)
+}
+
+func testSweep(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	register(t, service, proxyAddress, 10)
+
+	expiresAt := time.Now().Add(expiry)
+	for _, address := range []string{clientAddress, otherClient} {
+		deposit(t, service, address, 100)
+
+		_, err := service.Authorize(ctx, address, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(20), ExpiresAt: &expiresAt})
+		require.NoError(t, err)
+
+		_, err = service.Authorize(ctx, address, bank.AuthorizeParams{Proxy: proxyAddress})
+		require.NoError(t, err)
+	}
+
+	report, err := service.SweepEscrow(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, report.Accounts)
+
+	time.Sleep(time.Until(expiresAt))
+
+	report, err = service.SweepEscrow(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Accounts)
+	requireFIL(t, 40, report.Swept)
+
+	requireBalance(t, service, clientAddress, 90, 10)
+	requireBalance(t, service, otherClient, 90, 10)
+
+	report, err = service.SweepEscrow(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, report.Accounts, "expired escrow is swept once")
+}
+
+func testAuthorizations(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	deposit(t, service, otherClient, 100)
+	register(t, service, proxyAddress, 10)
+
+	var ids []uuid.UUID
+	for range 3 {
+		ids = append(ids, authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress}).UUID)
+	}
+
+	_, err := service.Authorize(ctx, otherClient, bank.AuthorizeParams{Proxy: proxyAddress})
+	require.NoError(t, err)
+
+	_, err = service.CancelAuthorization(ctx, clientAddress, ids[1])
+	require.NoError(t, err)
+
+	list, err := service.Authorizations(ctx, clientAddress, bank.AuthorizationsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, []uuid.UUID{ids[2], ids[1], ids[0]}, []uuid.UUID{list[0].UUID, list[1].UUID, list[2].UUID}, "newest first")
+
+	list, err = service.Authorizations(ctx, proxyAddress, bank.AuthorizationsParams{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, list, 4, "a storage provider sees the authorizations of every client")
+
+	list, err = service.Authorizations(ctx, clientAddress, bank.AuthorizationsParams{Status: "cancelled", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, ids[1], list[0].UUID)
+
+	list, err = service.Authorizations(ctx, clientAddress, bank.AuthorizationsParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	list, err = service.Authorizations(ctx, clientAddress, bank.AuthorizationsParams{Cursor: list[1].UUID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, ids[0], list[0].UUID)
+
+	list, err = service.Authorizations(ctx, otherClient, bank.AuthorizationsParams{Status: "open", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
@@ -0,0 +1,157 @@
+// Package banktest is a conformance suite for implementations of bank.Service. Every backend runs it, so
+// that they keep the same semantics.
+package banktest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+const (
+	walletAddress = "f1bankwallet"
+	escrowAddress = "f1bankescrow"
+
+	clientAddress = "f1client"
+	otherClient   = "f1otherclient"
+	proxyAddress  = "f1proxy"
+	payoutAddress = "f1payout"
+)
+
+// Config is what the services under test are built with.
+type Config struct {
+	WalletAddress       string
+	EscrowAddress       string
+	EscrowDeadline      time.Duration
+	EscrowMinDeadline   time.Duration
+	EscrowMaxDeadline   time.Duration
+	ChannelSettleWindow string
+	ChannelLifetime     time.Duration
+	FrozenAllowed       []bank.Operation
+	Fees                bank.FeeSchedule
+}
+
+// Factory builds an empty service for a test. Tests do not run in parallel, so a backend may reset a
+// shared store.
+type Factory func(t *testing.T, cfg Config) bank.Service
+
+// expiry is how long the authorizations that tests let expire last, and settleWindow how long channels
+// settle for.
+const (
+	expiry       = 200 * time.Millisecond
+	settleWindow = 200 * time.Millisecond
+)
+
+func defaultConfig() Config {
+	return Config{
+		WalletAddress:       walletAddress,
+		EscrowAddress:       escrowAddress,
+		EscrowDeadline:      time.Hour,
+		EscrowMinDeadline:   expiry / 2,
+		EscrowMaxDeadline:   24 * time.Hour,
+		ChannelSettleWindow: settleWindow.String(),
+	}
+}
+
+// Run runs the conformance suite against the services built by the factory.
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, factory Factory)
+	}{
+		{"Deposit", testDeposit},
+		{"RegisterProxy", testRegisterProxy},
+		{"Authorize", testAuthorize},
+		{"RedeemSingle", testRedeemSingle},
+		{"RedeemMulti", testRedeemMulti},
+		{"Cancel", testCancel},
+		{"Refund", testRefund},
+		{"Sweep", testSweep},
+		{"Authorizations", testAuthorizations},
+		{"Withdraw", testWithdraw},
+		{"WithdrawalLifecycle", testWithdrawalLifecycle},
+		{"Fees", testFees},
+		{"Liabilities", testLiabilities},
+		{"ChainEntries", testChainEntries},
+		{"HeldDeposits", testHeldDeposits},
+		{"ChainCursor", testChainCursor},
+		{"Channels", testChannels},
+		{"SweepChannels", testSweepChannels},
+		{"Deregister", testDeregister},
+		{"DeregisterDust", testDeregisterDust},
+		{"Nonces", testNonces},
+		{"Idempotency", testIdempotency},
+		{"IdempotentResponse", testIdempotentResponse},
+		{"PruneIdempotencyKeys", testPruneIdempotencyKeys},
+		{"RateLimits", testRateLimits},
+		{"Ledger", testLedger},
+		{"Transactions", testTransactions},
+		{"Admin", testAdmin},
+		{"AccountStatus", testAccountStatus},
+		{"FrozenOperations", testFrozenOperations},
+		{"ConcurrentSpending", testConcurrentSpending},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) { // nolint:paralleltest
+			test.run(t, factory)
+		})
+	}
+}
+
+func atto(amount int64) types.FIL {
+	return types.NewFIL(big.NewInt(amount))
+}
+
+func requireFIL(t *testing.T, expected int64, actual types.FIL) {
+	t.Helper()
+
+	require.NotNil(t, actual.Int)
+	assert.Equal(t, big.NewInt(expected).String(), actual.Int.String())
+}
+
+func requireBalance(t *testing.T, service bank.Service, address string, available int64, escrow int64) {
+	t.Helper()
+
+	balance, held, err := service.Balance(context.Background(), address)
+	require.NoError(t, err)
+	requireFIL(t, available, balance)
+	requireFIL(t, escrow, held)
+}
+
+func deposit(t *testing.T, service bank.Service, address string, amount int64) {
+	t.Helper()
+
+	_, err := service.Deposit(context.Background(), address, atto(amount), uuid.NewString())
+	require.NoError(t, err)
+}
+
+func register(t *testing.T, service bank.Service, address string, price int64) {
+	t.Helper()
+
+	require.NoError(t, service.RegisterProxy(context.Background(), "sp-"+address, address, atto(price)))
+}
+
+func authorize(t *testing.T, service bank.Service, params bank.AuthorizeParams) bank.AuthModel {
+	t.Helper()
+
+	model, err := service.Authorize(context.Background(), clientAddress, params)
+	require.NoError(t, err)
+
+	return model
+}
+
+func amount(value int64) *types.FIL {
+	fil := atto(value)
+
+	return &fil
+}
@@ -0,0 +1,156 @@
+package banktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+func testChannels(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	_, err := service.OpenChannel(ctx, proxyAddress, proxyAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed, "a storage provider does not open channels")
+
+	_, err = service.OpenChannel(ctx, clientAddress, clientAddress, atto(10))
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed, "channels are opened with storage providers")
+
+	_, err = service.OpenChannel(ctx, clientAddress, proxyAddress, atto(101))
+	require.ErrorIs(t, err, bank.ErrInsufficientFunds)
+
+	model, err := service.OpenChannel(ctx, clientAddress, proxyAddress, atto(50))
+	require.NoError(t, err)
+	requireFIL(t, 50, model.Available)
+	assert.Equal(t, "Open", model.Status)
+	requireFIL(t, 50, model.Balance)
+	requireBalance(t, service, clientAddress, 50, 50)
+
+	id := model.UUID
+
+	_, err = service.Channel(ctx, otherClient, id)
+	require.ErrorIs(t, err, bank.ErrChannelNotFound)
+
+	_, err = service.SettleChannel(ctx, clientAddress, id, atto(10))
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed, "only the storage provider settles")
+
+	_, err = service.SettleChannel(ctx, proxyAddress, id, atto(51))
+	require.ErrorIs(t, err, bank.ErrInvalidVoucher)
+
+	channel, err := service.SettleChannel(ctx, proxyAddress, id, atto(20))
+	require.NoError(t, err)
+	requireFIL(t, 20, channel.Redeemed)
+
+	// An older voucher pays nothing more.
+	channel, err = service.SettleChannel(ctx, proxyAddress, id, atto(15))
+	require.NoError(t, err)
+	requireFIL(t, 20, channel.Redeemed)
+
+	requireBalance(t, service, clientAddress, 50, 30)
+	requireBalance(t, service, proxyAddress, 20, 0)
+
+	// The client starts the settle window, during which the storage provider can still settle.
+	channel, err = service.CloseChannel(ctx, clientAddress, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Closing", channel.Status)
+
+	channel, err = service.CloseChannel(ctx, clientAddress, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Closing", channel.Status, "the client cannot close a channel before the window is over")
+
+	_, err = service.SettleChannel(ctx, proxyAddress, id, atto(30))
+	require.NoError(t, err)
+
+	time.Sleep(time.Until(channel.ClosesAt))
+
+	_, err = service.SettleChannel(ctx, proxyAddress, id, atto(40))
+	require.ErrorIs(t, err, bank.ErrChannelClosed)
+
+	channel, err = service.CloseChannel(ctx, clientAddress, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Closed", channel.Status)
+
+	requireBalance(t, service, clientAddress, 70, 0)
+	requireBalance(t, service, proxyAddress, 30, 0)
+
+	_, err = service.CloseChannel(ctx, clientAddress, id)
+	require.ErrorIs(t, err, bank.ErrChannelClosed)
+
+	// The storage provider closes a channel right away.
+	model, err = service.OpenChannel(ctx, clientAddress, proxyAddress, atto(40))
+	require.NoError(t, err)
+
+	_, err = service.SettleChannel(ctx, proxyAddress, model.UUID, atto(10))
+	require.NoError(t, err)
+
+	channel, err = service.CloseChannel(ctx, proxyAddress, model.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "Closed", channel.Status)
+
+	requireBalance(t, service, clientAddress, 60, 0)
+	requireBalance(t, service, proxyAddress, 40, 0)
+
+	_, err = service.Channel(ctx, clientAddress, uuid.New())
+	require.ErrorIs(t, err, bank.ErrChannelNotFound)
+}
+
+func testSweepChannels(t *testing.T, factory Factory) {
+	cfg := defaultConfig()
+	cfg.ChannelLifetime = expiry
+	service := factory(t, cfg)
+	ctx := context.Background()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	model, err := service.OpenChannel(ctx, clientAddress, proxyAddress, atto(50))
+	require.NoError(t, err)
+
+	id := model.UUID
+
+	_, err = service.SettleChannel(ctx, proxyAddress, id, atto(20))
+	require.NoError(t, err)
+
+	swept, err := service.SweepChannels(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, swept, "a channel stays open for its lifetime")
+
+	// Some backends store timestamps to the second.
+	time.Sleep(expiry + time.Second)
+
+	swept, err = service.SweepChannels(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+
+	channel, err := service.Channel(ctx, clientAddress, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Closing", channel.Status, "a channel that outlived its lifetime starts closing")
+
+	_, err = service.SettleChannel(ctx, proxyAddress, id, atto(30))
+	require.NoError(t, err, "the storage provider can still settle during the window")
+
+	time.Sleep(time.Until(channel.ClosesAt))
+
+	swept, err = service.SweepChannels(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, swept)
+
+	channel, err = service.Channel(ctx, clientAddress, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Closed", channel.Status, "a channel is closed once its window is over")
+
+	requireBalance(t, service, clientAddress, 70, 0)
+	requireBalance(t, service, proxyAddress, 30, 0)
+
+	swept, err = service.SweepChannels(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, swept)
+}
@@ -0,0 +1,112 @@
+package banktest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+// workers is how many requests race against each other in the concurrency tests.
+const workers = 20
+
+// concurrently calls fn from workers goroutines at once and returns what each call returned.
+func concurrently(fn func(i int) error) []error {
+	errs := make([]error, workers)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(i)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+// requireOutcomes checks that exactly succeeded calls went through and that the others failed with expected.
+func requireOutcomes(t *testing.T, errs []error, succeeded int, expected error) {
+	t.Helper()
+
+	var ok int
+	for _, err := range errs {
+		if err == nil {
+			ok++
+
+			continue
+		}
+
+		require.ErrorIs(t, err, expected)
+	}
+
+	assert.Equal(t, succeeded, ok)
+}
+
+func testConcurrentSpending(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	register(t, service, proxyAddress, 10)
+	deposit(t, service, clientAddress, 100)
+
+	// Twice as many authorizations as the balance covers race for it.
+	ids := make([]uuid.UUID, workers)
+	errs := concurrently(func(i int) error {
+		model, err := service.Authorize(ctx, clientAddress, bank.AuthorizeParams{Proxy: proxyAddress})
+		ids[i] = model.UUID
+
+		return err
+	})
+
+	requireOutcomes(t, errs, 10, bank.ErrInsufficientFunds)
+	requireBalance(t, service, clientAddress, 0, 100)
+
+	var id uuid.UUID
+	for i, err := range errs {
+		if err == nil {
+			id = ids[i]
+
+			break
+		}
+	}
+
+	_, err := service.Verify(ctx, proxyAddress, id)
+	require.NoError(t, err)
+
+	// An authorization is only paid once, however many redeems of it race.
+	errs = concurrently(func(int) error {
+		_, err := service.Redeem(ctx, proxyAddress, id, atto(10))
+
+		return err
+	})
+
+	requireOutcomes(t, errs, 1, bank.ErrAuthNotFound)
+	requireBalance(t, service, clientAddress, 0, 90)
+	requireBalance(t, service, proxyAddress, 10, 0)
+
+	errs = concurrently(func(int) error {
+		_, err := service.Withdraw(ctx, proxyAddress, payoutAddress, atto(1))
+
+		return err
+	})
+
+	requireOutcomes(t, errs, 10, bank.ErrInsufficientFunds)
+	requireBalance(t, service, proxyAddress, 0, 0)
+
+	ledger, err := service.Ledger(ctx, proxyAddress, time.Now())
+	require.NoError(t, err)
+	requireFIL(t, 0, ledger.Available)
+}
@@ -0,0 +1,180 @@
+package banktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+func testLedger(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	before := time.Now()
+
+	deposit(t, service, clientAddress, 100)
+	register(t, service, proxyAddress, 10)
+
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(30)})
+
+	_, err := service.Verify(ctx, proxyAddress, model.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, model.UUID, atto(10))
+	require.NoError(t, err)
+
+	_, err = service.Withdraw(ctx, clientAddress, payoutAddress, atto(20))
+	require.NoError(t, err)
+
+	// Ledger timestamps may come from another clock, such as the database's.
+	time.Sleep(10 * time.Millisecond)
+
+	for _, address := range []string{clientAddress, proxyAddress} {
+		balance, escrow, err := service.Balance(ctx, address)
+		require.NoError(t, err)
+
+		ledger, err := service.Ledger(ctx, address, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, balance.Int.String(), ledger.Available.Int.String(), "the ledger of %s matches its balance", address)
+		assert.Equal(t, escrow.Int.String(), ledger.Escrow.Int.String(), "the ledger of %s matches its escrow", address)
+	}
+
+	ledger, err := service.Ledger(ctx, clientAddress, before.Add(-time.Second))
+	require.NoError(t, err)
+	requireFIL(t, 0, ledger.Available)
+	requireFIL(t, 0, ledger.Escrow)
+}
+
+func testTransactions(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	_, err := service.Deposit(ctx, clientAddress, atto(100), "deposit-hash")
+	require.NoError(t, err)
+
+	register(t, service, proxyAddress, 10)
+
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(30)})
+
+	_, err = service.Verify(ctx, proxyAddress, model.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, model.UUID, atto(10))
+	require.NoError(t, err)
+
+	list, err := service.Transactions(ctx, clientAddress, bank.TransactionsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+
+	kinds := make([]string, 0, len(list))
+	for _, tr := range list {
+		kinds = append(kinds, tr.Type)
+	}
+
+	assert.Equal(t, []string{"Refund", "Redeem", "Authorize", "Deposit"}, kinds, "newest first")
+	assert.Equal(t, "deposit-hash", list[3].TransactionID)
+	assert.Equal(t, walletAddress, list[3].Counterpart)
+	assert.Equal(t, proxyAddress, list[1].Counterpart)
+	assert.Equal(t, "Completed", list[1].Status)
+	requireFIL(t, 10, list[1].Amount)
+
+	list, err = service.Transactions(ctx, proxyAddress, bank.TransactionsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 3, "a storage provider sees the transactions it is the counterpart of")
+	assert.Equal(t, clientAddress, list[0].Counterpart)
+
+	list, err = service.Transactions(ctx, clientAddress, bank.TransactionsParams{Type: "redeem", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Redeem", list[0].Type)
+
+	page, err := service.Transactions(ctx, clientAddress, bank.TransactionsParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+
+	page, err = service.Transactions(ctx, clientAddress, bank.TransactionsParams{Cursor: page[1].ID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "Authorize", page[0].Type)
+
+	list, err = service.Transactions(ctx, clientAddress, bank.TransactionsParams{From: time.Now().Add(time.Hour), Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func testFees(t *testing.T, factory Factory) {
+	fees, err := bank.ParseFeeSchedule(bank.Fees{
+		Redeem:   bank.FeeConfig{Percent: "10", Flat: atto(0)},
+		Withdraw: bank.FeeConfig{Flat: atto(2)},
+		Deposit:  bank.FeeConfig{Percent: "1", Flat: atto(0)},
+	})
+	require.NoError(t, err)
+
+	cfg := defaultConfig()
+	cfg.Fees = fees
+	service := factory(t, cfg)
+	ctx := context.Background()
+
+	balance, err := service.Deposit(ctx, clientAddress, atto(200), "deposit-hash")
+	require.NoError(t, err)
+	requireFIL(t, 198, balance)
+
+	register(t, service, proxyAddress, 100)
+
+	model := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+
+	_, err = service.Verify(ctx, proxyAddress, model.UUID)
+	require.NoError(t, err)
+
+	redeem, err := service.Redeem(ctx, proxyAddress, model.UUID, atto(100))
+	require.NoError(t, err)
+	requireFIL(t, 10, redeem.Fee)
+	requireFIL(t, 90, redeem.SP)
+
+	_, err = service.Withdraw(ctx, clientAddress, payoutAddress, atto(2))
+	require.ErrorIs(t, err, bank.ErrAmountBelowFee)
+
+	withdraw, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(50))
+	require.NoError(t, err)
+	requireFIL(t, 2, withdraw.Fee)
+	requireFIL(t, 48, withdraw.Available)
+
+	withdrawal, err := service.Withdrawal(ctx, clientAddress, withdraw.ID)
+	require.NoError(t, err)
+	requireFIL(t, 48, withdrawal.Amount)
+	requireFIL(t, 2, withdrawal.Fee)
+
+	reversed, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(10))
+	require.NoError(t, err)
+	require.NoError(t, service.ReverseWithdrawal(ctx, reversed.ID))
+	requireBalance(t, service, clientAddress, 48, 0)
+
+	_, err = service.Fees(ctx, clientAddress, bank.FeesParams{Limit: 10})
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed, "only the bank's wallet sees the fees")
+
+	report, err := service.Fees(ctx, walletAddress, bank.FeesParams{Limit: 10})
+	require.NoError(t, err)
+	requireFIL(t, 14, report.Total)
+	requireFIL(t, 2, report.ByType["Deposit"])
+	requireFIL(t, 10, report.ByType["Redeem"])
+	requireFIL(t, 2, report.ByType["Withdraw"])
+	require.Len(t, report.Fees, 3, "the fee of a reversed withdrawal is given back")
+	assert.Equal(t, "Withdraw", report.Fees[0].Type)
+	assert.Equal(t, clientAddress, report.Fees[0].Address)
+
+	report, err = service.Fees(ctx, walletAddress, bank.FeesParams{Type: "redeem", Limit: 10})
+	require.NoError(t, err)
+	requireFIL(t, 10, report.Total)
+	require.Len(t, report.Fees, 1)
+	assert.Equal(t, proxyAddress, report.Fees[0].Address)
+
+	page, err := service.Fees(ctx, walletAddress, bank.FeesParams{Cursor: report.Fees[0].ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Fees, 1)
+	assert.Equal(t, "Deposit", page.Fees[0].Type)
+	requireFIL(t, 14, page.Total)
+}
@@ -0,0 +1,190 @@
+package banktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+func testChainEntries(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(-time.Minute)
+
+	_, err := service.Deposit(ctx, clientAddress, atto(100), "0xABC1")
+	require.NoError(t, err)
+
+	// Authorizations are not transfers on chain.
+	register(t, service, proxyAddress, 10)
+	authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress})
+
+	withdrawal, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(30))
+	require.NoError(t, err)
+
+	pending, err := service.Withdraw(ctx, clientAddress, payoutAddress, atto(20))
+	require.NoError(t, err)
+
+	_, err = service.ClaimWithdrawals(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, service.SubmitWithdrawal(ctx, withdrawal.ID, "0xdef2", []byte("raw")))
+
+	end := time.Now().UTC().Add(time.Minute)
+
+	entries, err := service.ChainEntries(ctx, bank.ChainEntriesParams{From: start, To: end})
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "only deposits and signed withdrawals are listed")
+
+	deposit, payout := entries[0], entries[1]
+
+	assert.Equal(t, bank.TransactionDeposit, deposit.Type)
+	assert.Equal(t, "0xABC1", deposit.Hash)
+	assert.Equal(t, clientAddress, deposit.Address)
+	assert.Equal(t, clientAddress, deposit.Counterpart)
+	assert.Equal(t, bank.TransactionCompleted, deposit.Status)
+	requireFIL(t, 100, deposit.Value)
+
+	assert.Equal(t, bank.TransactionWithdraw, payout.Type)
+	assert.Equal(t, withdrawal.ID.String(), payout.ID)
+	assert.Equal(t, "0xdef2", payout.Hash)
+	assert.Equal(t, clientAddress, payout.Address)
+	assert.Equal(t, payoutAddress, payout.Counterpart)
+	assert.Equal(t, bank.TransactionSubmitted, payout.Status)
+	requireFIL(t, 30, payout.Value)
+	assert.NotEqual(t, pending.ID.String(), payout.ID)
+
+	past := start.Add(-time.Hour)
+
+	entries, err = service.ChainEntries(ctx, bank.ChainEntriesParams{From: past, To: start})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	entries, err = service.ChainEntries(ctx, bank.ChainEntriesParams{Hashes: []string{"0xabc1", "0xDEF2", "0xmissing"}, From: past, To: start})
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "entries are found by hash whatever their case")
+}
+
+func testChainCursor(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	_, ok, err := service.ChainCursor(ctx, "deposits")
+	require.NoError(t, err)
+	assert.False(t, ok, "a follower that never ran has no cursor")
+
+	require.NoError(t, service.SetChainCursor(ctx, "deposits", 100))
+	require.NoError(t, service.SetChainCursor(ctx, "deposits", 120))
+
+	block, ok, err := service.ChainCursor(ctx, "deposits")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(120), block)
+
+	require.NoError(t, service.SetChainCursor(ctx, "deposits", 110))
+
+	block, _, err = service.ChainCursor(ctx, "deposits")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(120), block, "a lagging follower does not move the cursor back")
+
+	_, ok, err = service.ChainCursor(ctx, "other")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func testHeldDeposits(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(-time.Minute)
+	sender := "0x3333333333333333333333333333333333333333"
+
+	require.NoError(t, service.HoldDeposit(ctx, bank.HeldDeposit{Hash: "0xAAA1", Sender: sender, Value: atto(100), Reason: "sender has no filecoin address"}))
+	require.NoError(t, service.HoldDeposit(ctx, bank.HeldDeposit{Hash: "0xaaa1", Sender: sender, Value: atto(100), Reason: "again"}), "holding a deposit again does nothing")
+	require.NoError(t, service.HoldDeposit(ctx, bank.HeldDeposit{Hash: "0xbbb2", Sender: sender, Address: clientAddress, Value: atto(50), Reason: "account is frozen"}))
+
+	_, err := service.ValidateBlockchainTransaction(ctx, "0xaaa1")
+	require.ErrorIs(t, err, bank.ErrTransactionExists, "a held deposit cannot be credited with /deposit")
+
+	deposits, err := service.HeldDeposits(ctx, bank.HeldDepositsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deposits, 2)
+	assert.Equal(t, "0xbbb2", deposits[0].Hash, "newest first")
+	assert.Equal(t, clientAddress, deposits[0].Address)
+	assert.Equal(t, "0xaaa1", deposits[1].Hash)
+	assert.Equal(t, sender, deposits[1].Sender)
+	assert.Equal(t, "sender has no filecoin address", deposits[1].Reason)
+	assert.Equal(t, "Held", deposits[1].Status)
+	requireFIL(t, 100, deposits[1].Value)
+
+	deposits, err = service.HeldDeposits(ctx, bank.HeldDepositsParams{Cursor: deposits[0].ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deposits, 1)
+	assert.Equal(t, "0xaaa1", deposits[0].Hash)
+
+	credited, err := service.CreditHeldDeposit(ctx, operatorAddress, "0xAAA1", otherClient, "owner proved the transfer")
+	require.NoError(t, err)
+	assert.Equal(t, "Credited", credited.Status)
+	assert.Equal(t, otherClient, credited.CreditedTo)
+	assert.False(t, credited.ResolvedAt.IsZero())
+	requireBalance(t, service, otherClient, 100, 0)
+
+	_, err = service.CreditHeldDeposit(ctx, operatorAddress, "0xaaa1", otherClient, "again")
+	require.ErrorIs(t, err, bank.ErrHeldDepositResolved)
+
+	_, err = service.CreditHeldDeposit(ctx, operatorAddress, "0xmissing", otherClient, "unknown")
+	require.ErrorIs(t, err, bank.ErrHeldDepositNotFound)
+
+	register(t, service, proxyAddress, 10)
+
+	_, err = service.CreditHeldDeposit(ctx, operatorAddress, "0xbbb2", proxyAddress, "storage provider")
+	require.ErrorIs(t, err, bank.ErrOperationNotAllowed)
+
+	returned, err := service.ReturnHeldDeposit(ctx, operatorAddress, "0xbbb2", "0xCCC3", "sent back")
+	require.NoError(t, err)
+	assert.Equal(t, "Returned", returned.Status)
+	assert.Equal(t, "0xccc3", returned.ReturnHash)
+
+	_, err = service.ReturnHeldDeposit(ctx, operatorAddress, "0xaaa1", "0xddd4", "credited already")
+	require.ErrorIs(t, err, bank.ErrHeldDepositResolved)
+
+	deposits, err = service.HeldDeposits(ctx, bank.HeldDepositsParams{Status: "held", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, deposits)
+
+	end := time.Now().UTC().Add(time.Minute)
+
+	entries, err := service.ChainEntries(ctx, bank.ChainEntriesParams{From: start, To: end})
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "a credited held deposit is listed once")
+
+	byHash := make(map[string]bank.ChainEntry, len(entries))
+	for _, e := range entries {
+		byHash[e.Hash] = e
+	}
+
+	assert.Equal(t, bank.TransactionDeposit, byHash["0xaaa1"].Type)
+	assert.Equal(t, bank.TransactionStatus("Credited"), byHash["0xaaa1"].Status)
+	assert.Equal(t, otherClient, byHash["0xaaa1"].Address)
+	assert.Equal(t, sender, byHash["0xaaa1"].Counterpart)
+	assert.Equal(t, bank.TransactionDeposit, byHash["0xbbb2"].Type)
+	assert.Equal(t, bank.TransactionStatus("Returned"), byHash["0xbbb2"].Status)
+	assert.Equal(t, bank.TransactionReturn, byHash["0xccc3"].Type)
+	assert.Equal(t, sender, byHash["0xccc3"].Counterpart)
+	requireFIL(t, 50, byHash["0xccc3"].Value)
+
+	entries, err = service.ChainEntries(ctx, bank.ChainEntriesParams{Hashes: []string{"0xCCC3"}, From: start.Add(-time.Hour), To: start})
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "a return is found by its hash")
+	assert.Equal(t, bank.TransactionReturn, entries[0].Type)
+
+	log, err := service.AuditLog(ctx, bank.AuditParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, log, 2)
+	assert.Equal(t, bank.AuditReturnHeldDeposit, log[0].Action)
+	assert.Equal(t, "0xbbb2", log[0].Target)
+	assert.Equal(t, bank.AuditCreditHeldDeposit, log[1].Action)
+}
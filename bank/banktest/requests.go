@@ -0,0 +1,205 @@
+package banktest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+func testNonces(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Minute)
+
+	require.NoError(t, service.RegisterNonce(ctx, clientAddress, "nonce", expiresAt))
+	require.ErrorIs(t, service.RegisterNonce(ctx, clientAddress, "nonce", expiresAt), bank.ErrNonceReused)
+	require.NoError(t, service.RegisterNonce(ctx, otherClient, "nonce", expiresAt), "nonces are scoped to an account")
+
+	// Expired nonces are forgotten once pruned.
+	require.NoError(t, service.RegisterNonce(ctx, clientAddress, "expired", time.Now().Add(-time.Second)))
+	require.ErrorIs(t, service.RegisterNonce(ctx, clientAddress, "expired", expiresAt), bank.ErrNonceReused)
+
+	pruned, err := service.PruneNonces(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	require.NoError(t, service.RegisterNonce(ctx, clientAddress, "expired", expiresAt))
+}
+
+func testIdempotency(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Minute)
+
+	stored, err := service.BeginIdempotentRequest(ctx, clientAddress, "key", "hash", expiresAt)
+	require.NoError(t, err)
+	assert.Nil(t, stored, "the first request is processed")
+
+	_, err = service.BeginIdempotentRequest(ctx, clientAddress, "key", "hash", expiresAt)
+	require.ErrorIs(t, err, bank.ErrIdempotencyKeyInProgress)
+
+	_, err = service.BeginIdempotentRequest(ctx, clientAddress, "key", "other", expiresAt)
+	require.ErrorIs(t, err, bank.ErrIdempotencyKeyReused)
+
+	stored, err = service.BeginIdempotentRequest(ctx, otherClient, "key", "other", expiresAt)
+	require.NoError(t, err)
+	assert.Nil(t, stored, "keys are scoped to an account")
+
+	response := bank.IdempotentResponse{Status: http.StatusOK, Body: []byte(`{"status":"success"}`)}
+	require.NoError(t, service.CompleteIdempotentRequest(ctx, clientAddress, "key", response))
+
+	stored, err = service.BeginIdempotentRequest(ctx, clientAddress, "key", "hash", expiresAt)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, response, *stored)
+
+	// A completed request is not released.
+	require.NoError(t, service.ReleaseIdempotentRequest(ctx, clientAddress, "key"))
+
+	stored, err = service.BeginIdempotentRequest(ctx, clientAddress, "key", "hash", expiresAt)
+	require.NoError(t, err)
+	assert.NotNil(t, stored)
+
+	// A released request can be retried.
+	require.NoError(t, service.ReleaseIdempotentRequest(ctx, otherClient, "key"))
+
+	stored, err = service.BeginIdempotentRequest(ctx, otherClient, "key", "hash", expiresAt)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func testIdempotentResponse(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Minute)
+	deposit(t, service, clientAddress, 10)
+
+	var results []any
+	request := func(address string, key string) context.Context {
+		_, err := service.BeginIdempotentRequest(ctx, address, key, "hash", expiresAt)
+		require.NoError(t, err)
+
+		req := &bank.IdempotentRequest{
+			Address: address,
+			Key:     key,
+			Respond: func(result any) (bank.IdempotentResponse, error) {
+				results = append(results, result)
+				return bank.IdempotentResponse{Status: http.StatusOK, Body: []byte(key)}, nil
+			},
+		}
+
+		return context.WithValue(ctx, bank.CtxKeyIdempotentRequest, req)
+	}
+
+	requireStored := func(address string, key string) {
+		t.Helper()
+
+		stored, err := service.BeginIdempotentRequest(ctx, address, key, "hash", expiresAt)
+		require.NoError(t, err)
+		require.NotNil(t, stored, "the response is stored by the service call")
+		assert.Equal(t, []byte(key), stored.Body)
+	}
+
+	// The response is rendered from the result of the service call, and stored with its changes.
+	balance, err := service.Deposit(request(clientAddress, "deposit"), clientAddress, atto(100), uuid.NewString())
+	require.NoError(t, err)
+	requireStored(clientAddress, "deposit")
+	assert.Equal(t, balance, results[len(results)-1])
+
+	withdrawal, err := service.Withdraw(request(clientAddress, "withdraw"), clientAddress, payoutAddress, atto(10))
+	require.NoError(t, err)
+	requireStored(clientAddress, "withdraw")
+	assert.Equal(t, withdrawal, results[len(results)-1])
+
+	require.NoError(t, service.RegisterProxy(request(proxyAddress, "register"), "sp", proxyAddress, atto(1)))
+	requireStored(proxyAddress, "register")
+	assert.Nil(t, results[len(results)-1])
+
+	// Completing the request keeps the response stored by the service call.
+	other := bank.IdempotentResponse{Status: http.StatusOK, Body: []byte("other")}
+	require.NoError(t, service.CompleteIdempotentRequest(ctx, clientAddress, "deposit", other))
+	requireStored(clientAddress, "deposit")
+
+	// A failing service call stores nothing, and its request can be retried once released.
+	_, err = service.Withdraw(request(clientAddress, "failed"), clientAddress, payoutAddress, atto(1000))
+	require.ErrorIs(t, err, bank.ErrInsufficientFunds)
+	require.NoError(t, service.ReleaseIdempotentRequest(ctx, clientAddress, "failed"))
+
+	stored, err := service.BeginIdempotentRequest(ctx, clientAddress, "failed", "hash", expiresAt)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	requireBalance(t, service, clientAddress, 100, 0)
+}
+
+func testPruneIdempotencyKeys(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	_, err := service.BeginIdempotentRequest(ctx, clientAddress, "expired", "hash", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, err = service.BeginIdempotentRequest(ctx, clientAddress, "live", "hash", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	pruned, err := service.PruneIdempotencyKeys(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	stored, err := service.BeginIdempotentRequest(ctx, clientAddress, "expired", "other", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, stored, "a pruned key can be used again")
+
+	_, err = service.BeginIdempotentRequest(ctx, clientAddress, "live", "hash", time.Now().Add(time.Minute))
+	require.ErrorIs(t, err, bank.ErrIdempotencyKeyInProgress)
+}
+
+func testRateLimits(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	limit := bank.RateLimit{Requests: 2, Per: 400 * time.Millisecond}
+
+	for range limit.Requests {
+		wait, err := service.TakeToken(ctx, "address:deposit:"+clientAddress, limit)
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+	}
+
+	wait, err := service.TakeToken(ctx, "address:deposit:"+clientAddress, limit)
+	require.NoError(t, err)
+	assert.Positive(t, wait, "an empty bucket makes the caller wait")
+	assert.LessOrEqual(t, wait, 200*time.Millisecond, "a token comes back every Per / Requests")
+
+	again, err := service.TakeToken(ctx, "address:deposit:"+clientAddress, limit)
+	require.NoError(t, err)
+	assert.Positive(t, again)
+	assert.LessOrEqual(t, again, wait, "a refused request leaves the bucket as it is")
+
+	wait, err = service.TakeToken(ctx, "address:deposit:"+otherClient, limit)
+	require.NoError(t, err)
+	assert.Zero(t, wait, "every key has its own bucket")
+
+	time.Sleep(200 * time.Millisecond)
+
+	wait, err = service.TakeToken(ctx, "address:deposit:"+clientAddress, limit)
+	require.NoError(t, err)
+	assert.Zero(t, wait, "the bucket refills over time")
+
+	pruned, err := service.PruneBuckets(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, pruned, "buckets are kept until they are full again")
+
+	pruned, err = service.PruneBuckets(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+}
@@ -0,0 +1,79 @@
+package banktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/subvisual/fidl/bank"
+)
+
+func testLiabilities(t *testing.T, factory Factory) {
+	service := factory(t, defaultConfig())
+	ctx := context.Background()
+
+	liabilities, err := service.Liabilities(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, liabilities)
+
+	deposit(t, service, clientAddress, 100)
+	deposit(t, service, otherClient, 50)
+	register(t, service, proxyAddress, 10)
+
+	auth := authorize(t, service, bank.AuthorizeParams{Proxy: proxyAddress, Amount: amount(30)})
+
+	_, err = service.Verify(ctx, proxyAddress, auth.UUID)
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, proxyAddress, auth.UUID, atto(10))
+	require.NoError(t, err)
+
+	withdrawal, err := service.Withdraw(ctx, otherClient, payoutAddress, atto(20))
+	require.NoError(t, err)
+
+	requireLiabilities := func(expected map[string]int64) {
+		t.Helper()
+
+		liabilities, err := service.Liabilities(ctx)
+		require.NoError(t, err)
+		require.Len(t, liabilities, len(expected))
+
+		for i, l := range liabilities {
+			if i > 0 {
+				assert.Less(t, liabilities[i-1].Address, l.Address, "liabilities are ordered by address")
+			}
+
+			requireFIL(t, expected[l.Address], l.Amount)
+		}
+	}
+
+	// The client got the excess of its single authorization back, and the withdrawal is owed until paid.
+	requireLiabilities(map[string]int64{clientAddress: 90, otherClient: 50, proxyAddress: 10})
+
+	claimed, err := service.ClaimWithdrawals(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	require.NoError(t, service.SubmitWithdrawal(ctx, withdrawal.ID, "0xhash", []byte("raw")))
+	requireLiabilities(map[string]int64{clientAddress: 90, otherClient: 50, proxyAddress: 10})
+
+	require.NoError(t, service.CompleteWithdrawal(ctx, withdrawal.ID))
+	requireLiabilities(map[string]int64{clientAddress: 90, otherClient: 30, proxyAddress: 10})
+
+	// A held deposit is owed to its sender until it is credited or returned.
+	sender := "0x3333333333333333333333333333333333333333"
+
+	require.NoError(t, service.HoldDeposit(ctx, bank.HeldDeposit{Hash: "0xheld1", Sender: sender, Value: atto(40), Reason: "unknown sender"}))
+	require.NoError(t, service.HoldDeposit(ctx, bank.HeldDeposit{Hash: "0xheld2", Sender: sender, Value: atto(5), Reason: "unknown sender"}))
+	requireLiabilities(map[string]int64{clientAddress: 90, otherClient: 30, proxyAddress: 10, sender: 45})
+
+	_, err = service.CreditHeldDeposit(ctx, operatorAddress, "0xheld1", otherClient, "owner proved the transfer")
+	require.NoError(t, err)
+	requireLiabilities(map[string]int64{clientAddress: 90, otherClient: 70, proxyAddress: 10, sender: 5})
+
+	_, err = service.ReturnHeldDeposit(ctx, operatorAddress, "0xheld2", "0xreturn", "sent back")
+	require.NoError(t, err)
+	requireLiabilities(map[string]int64{clientAddress: 90, otherClient: 70, proxyAddress: 10})
+}
@@ -11,6 +11,7 @@ import (
 )
 
 type Db struct {
+	Driver       string `toml:"driver"`
 	Dsn          string `toml:"dsn"`
 	MaxOpenConns int    `toml:"max-open-connections"`
 	MaxIdleConns int    `toml:"max-idle-connections"`
@@ -18,18 +19,101 @@ type Db struct {
 }
 
 type Escrow struct {
-	Address  types.Address `toml:"address"`
-	Deadline string        `toml:"deadline"`
+	Address       types.Address `toml:"address"`
+	Deadline      string        `toml:"deadline"`
+	MinDeadline   string        `toml:"min-deadline"`
+	MaxDeadline   string        `toml:"max-deadline"`
+	SweepInterval string        `toml:"sweep-interval"`
+}
+
+type Channels struct {
+	SettleWindow  string `toml:"settle-window"`
+	Lifetime      string `toml:"lifetime"`
+	SweepInterval string `toml:"sweep-interval"`
+}
+
+type Withdraw struct {
+	Interval string `toml:"interval"`
+	Lease    string `toml:"lease"`
+}
+
+type Deregistration struct {
+	Interval string `toml:"interval"`
+}
+
+type Deposits struct {
+	Watch         bool   `toml:"watch"`
+	Interval      string `toml:"interval"`
+	Confirmations uint64 `toml:"confirmations"`
+	MaxBlocks     uint64 `toml:"max-blocks"`
+}
+
+type Solvency struct {
+	Interval string `toml:"interval"`
+}
+
+type FeeConfig struct {
+	Percent string    `toml:"percent"`
+	Flat    types.FIL `toml:"flat"`
+}
+
+type Fees struct {
+	Redeem   FeeConfig `toml:"redeem"`
+	Withdraw FeeConfig `toml:"withdraw"`
+	Deposit  FeeConfig `toml:"deposit"`
+}
+
+type Auth struct {
+	Window    string          `toml:"window"`
+	Blocklist []types.Address `toml:"blocklist"`
+}
+
+type Accounts struct {
+	FrozenAllowed []string `toml:"frozen-allowed"`
+}
+
+type Admin struct {
+	Operators []types.Address `toml:"operators"`
+}
+
+type RateLimitConfig struct {
+	Address string `toml:"address"`
+	IP      string `toml:"ip"`
+}
+
+type RateLimits struct {
+	Store  string                     `toml:"store"`
+	Routes map[string]RateLimitConfig `toml:"routes"`
+}
+
+type Prune struct {
+	Interval string `toml:"interval"`
+}
+
+type Idempotency struct {
+	TTL string `toml:"ttl"`
 }
 
 type Config struct {
-	Env        string            `toml:"env"`
-	Logger     http.Logger       `toml:"logger"`
-	Db         Db                `toml:"database"`
-	HTTP       http.HTTP         `toml:"http"`
-	Wallet     types.Wallet      `toml:"wallet"`
-	Escrow     Escrow            `toml:"escrow"`
-	Blockchain blockchain.Config `toml:"blockchain"`
+	Env            string            `toml:"env"`
+	Logger         http.Logger       `toml:"logger"`
+	Db             Db                `toml:"database"`
+	HTTP           http.HTTP         `toml:"http"`
+	Wallet         types.Wallet      `toml:"wallet"`
+	Escrow         Escrow            `toml:"escrow"`
+	Auth           Auth              `toml:"auth"`
+	Idempotency    Idempotency       `toml:"idempotency"`
+	Prune          Prune             `toml:"prune"`
+	Admin          Admin             `toml:"admin"`
+	Accounts       Accounts          `toml:"accounts"`
+	RateLimits     RateLimits        `toml:"ratelimit"`
+	Withdraw       Withdraw          `toml:"withdraw"`
+	Channels       Channels          `toml:"channels"`
+	Deregistration Deregistration    `toml:"deregistration"`
+	Fees           Fees              `toml:"fees"`
+	Solvency       Solvency          `toml:"solvency"`
+	Deposits       Deposits          `toml:"deposits"`
+	Blockchain     blockchain.Config `toml:"blockchain"`
 }
 
 func LoadConfiguration(cfgFilePath string) Config {
@@ -0,0 +1,86 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/types"
+	"go.uber.org/zap"
+)
+
+const depositCursor = "deposits"
+
+// DepositCrediter credits the deposits found by a blockchain.Watcher to their senders, and keeps the
+// watcher's cursor in the bank's database. A transfer is credited once whether the client also sends its
+// hash to /deposit or not, as both go through the same check on the transaction hash.
+type DepositCrediter struct {
+	BankService Service
+	Log         *zap.Logger
+}
+
+func NewDepositCrediter(bankService Service, log *zap.Logger) *DepositCrediter {
+	return &DepositCrediter{
+		BankService: bankService,
+		Log:         log,
+	}
+}
+
+func (d *DepositCrediter) Cursor(ctx context.Context) (uint64, bool, error) {
+	return d.BankService.ChainCursor(ctx, depositCursor)
+}
+
+func (d *DepositCrediter) SetCursor(ctx context.Context, block uint64) error {
+	return d.BankService.SetChainCursor(ctx, depositCursor, block)
+}
+
+// Credit deposits a transfer into the account of its sender. Transfers that cannot be credited, from a
+// storage provider, a frozen or closed account or an address with no Filecoin equivalent, are held by hash
+// until an operator credits or returns them.
+func (d *DepositCrediter) Credit(ctx context.Context, transfer blockchain.Transfer) error {
+	hash := strings.ToLower(transfer.Hash)
+
+	if _, err := d.BankService.ValidateBlockchainTransaction(ctx, hash); err != nil {
+		if errors.Is(err, ErrTransactionExists) {
+			return nil
+		}
+
+		return err
+	}
+
+	held := HeldDeposit{Hash: hash, Sender: strings.ToLower(transfer.From), Value: transfer.Value}
+
+	address, err := types.AddressFromEth(transfer.From)
+	if err != nil {
+		held.Reason = "sender has no filecoin address"
+		return d.hold(ctx, held)
+	}
+
+	held.Address = address.String()
+
+	_, err = d.BankService.Deposit(ctx, address.String(), transfer.Value, hash)
+
+	switch {
+	case err == nil:
+		d.Log.Info("credited deposit", zap.String("hash", hash), zap.String("address", address.String()), zap.String("fil", transfer.Value.String()))
+		return nil
+	case errors.Is(err, ErrTransactionExists):
+		return nil
+	case errors.Is(err, ErrOperationNotAllowed), errors.Is(err, ErrAccountFrozen), errors.Is(err, ErrAccountClosed):
+		held.Reason = err.Error()
+		return d.hold(ctx, held)
+	default:
+		return err
+	}
+}
+
+func (d *DepositCrediter) hold(ctx context.Context, deposit HeldDeposit) error {
+	if err := d.BankService.HoldDeposit(ctx, deposit); err != nil {
+		return err
+	}
+
+	d.Log.Warn("held deposit", zap.String("hash", deposit.Hash), zap.String("from", deposit.Sender), zap.String("reason", deposit.Reason))
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package bank
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const deregistrationBatchSize = 100
+
+// DeregistrationWorker periodically pays out the final balance of the deregistering storage providers that
+// can no longer be paid, and marks them inactive.
+type DeregistrationWorker struct {
+	BankService Service
+	Interval    time.Duration
+	Log         *zap.Logger
+}
+
+func NewDeregistrationWorker(bankService Service, interval time.Duration, log *zap.Logger) *DeregistrationWorker {
+	return &DeregistrationWorker{
+		BankService: bankService,
+		Interval:    interval,
+		Log:         log,
+	}
+}
+
+func (d *DeregistrationWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Finalize(ctx)
+		}
+	}
+}
+
+// Finalize finalizes deregistrations in batches until none is left, and returns how many it finalized.
+func (d *DeregistrationWorker) Finalize(ctx context.Context) int {
+	var total int
+
+	for {
+		finalized, err := d.BankService.FinalizeDeregistrations(ctx, deregistrationBatchSize)
+		total += finalized
+
+		if err != nil {
+			d.Log.Error("failed to finalize deregistrations", zap.Error(err))
+			break
+		}
+
+		if finalized < deregistrationBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		d.Log.Info("finalized deregistrations", zap.Int("storage providers", total))
+	}
+
+	return total
+}
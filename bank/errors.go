@@ -8,4 +8,26 @@ var (
 	ErrNothingToRefund     = errors.New("nothing to refund")
 	ErrAuthNotFound        = errors.New("authorization not found")
 	ErrAuthLocked          = errors.New("authorization is locked")
+	ErrAuthNotCancellable  = errors.New("authorization is locked by a proxy and cannot be cancelled")
+	ErrNonceReused         = errors.New("nonce already used")
+	ErrLedgerMismatch      = errors.New("balances do not match the ledger")
+	ErrWithdrawalNotFound  = errors.New("withdrawal not found")
+	ErrPriceAboveMax       = errors.New("storage provider price is above the maximum price")
+	ErrAmountBelowPrice    = errors.New("amount is below the storage provider price")
+	ErrChannelNotFound     = errors.New("channel not found")
+	ErrChannelClosed       = errors.New("channel is closed")
+	ErrInvalidVoucher      = errors.New("invalid voucher")
+	ErrExpiryOutOfBounds   = errors.New("expiry is outside the allowed bounds")
+	ErrProxyNotActive      = errors.New("storage provider is not active")
+	ErrAmountBelowFee      = errors.New("amount does not cover the fee")
+	ErrAccountNotFound     = errors.New("account not found")
+	ErrAccountFrozen       = errors.New("account is frozen")
+	ErrAccountClosed       = errors.New("account is closed")
+	ErrNoAttestation       = errors.New("no solvency attestation yet")
+	ErrTransactionExists   = errors.New("transaction already registered")
+	ErrHeldDepositNotFound = errors.New("held deposit not found")
+	ErrHeldDepositResolved = errors.New("held deposit was already credited or returned")
+
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is in progress")
 )
@@ -0,0 +1,82 @@
+package bank
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Fee is charged on an operation as a percentage of its amount plus a flat amount.
+type Fee struct {
+	Percent *big.Rat
+	Flat    *big.Int
+}
+
+type FeeSchedule struct {
+	Redeem   Fee
+	Withdraw Fee
+	Deposit  Fee
+}
+
+// Charge returns the fee on an amount, rounded down and never more than the amount itself.
+func (f Fee) Charge(amount *big.Int) *big.Int {
+	fee := new(big.Int)
+
+	if f.Percent != nil {
+		rat := new(big.Rat).Mul(new(big.Rat).SetInt(amount), f.Percent)
+		rat.Quo(rat, big.NewRat(100, 1)) // nolint:gomnd
+		fee.Quo(rat.Num(), rat.Denom())
+	}
+
+	if f.Flat != nil {
+		fee.Add(fee, f.Flat)
+	}
+
+	if fee.Cmp(amount) == 1 {
+		fee.Set(amount)
+	}
+
+	return fee
+}
+
+func ParseFee(cfg FeeConfig) (Fee, error) {
+	fee := Fee{Percent: new(big.Rat), Flat: new(big.Int)}
+
+	if cfg.Percent != "" {
+		if _, ok := fee.Percent.SetString(cfg.Percent); !ok {
+			return Fee{}, fmt.Errorf("invalid fee percentage: %s", cfg.Percent)
+		}
+	}
+
+	if fee.Percent.Sign() == -1 || fee.Percent.Cmp(big.NewRat(100, 1)) == 1 { // nolint:gomnd
+		return Fee{}, fmt.Errorf("fee percentage must be between 0 and 100: %s", cfg.Percent)
+	}
+
+	if cfg.Flat.Int != nil {
+		if cfg.Flat.Sign() == -1 {
+			return Fee{}, fmt.Errorf("flat fee must not be negative: %s", cfg.Flat)
+		}
+
+		fee.Flat.Set(cfg.Flat.Int)
+	}
+
+	return fee, nil
+}
+
+func ParseFeeSchedule(cfg Fees) (FeeSchedule, error) {
+	redeem, err := ParseFee(cfg.Redeem)
+	if err != nil {
+		return FeeSchedule{}, fmt.Errorf("redeem: %w", err)
+	}
+
+	withdraw, err := ParseFee(cfg.Withdraw)
+	if err != nil {
+		return FeeSchedule{}, fmt.Errorf("withdraw: %w", err)
+	}
+
+	deposit, err := ParseFee(cfg.Deposit)
+	if err != nil {
+		return FeeSchedule{}, fmt.Errorf("deposit: %w", err)
+	}
+
+	return FeeSchedule{Redeem: redeem, Withdraw: withdraw, Deposit: deposit}, nil
+}
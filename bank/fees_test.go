@@ -0,0 +1,53 @@
+package bank
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/subvisual/fidl/types"
+)
+
+func TestFeeCharge(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		percent  string
+		flat     int64
+		amount   int64
+		expected int64
+	}{
+		{"", 0, 1000, 0},
+		{"1", 0, 1000, 10},
+		{"1.5", 0, 1000, 15},
+		{"0.33", 0, 1000, 3},
+		{"", 25, 1000, 25},
+		{"2", 5, 1000, 25},
+		{"50", 600, 1000, 1000},
+		{"100", 0, 1000, 1000},
+	}
+
+	for _, test := range tests {
+		fee, err := ParseFee(FeeConfig{Percent: test.percent, Flat: types.NewFIL(big.NewInt(test.flat))})
+		if err != nil {
+			t.Fatalf("failed to parse fee: %v", err)
+		}
+
+		if got := fee.Charge(big.NewInt(test.amount)); got.Int64() != test.expected {
+			t.Errorf("fee of %s%% + %d on %d: expected %d, got %s", test.percent, test.flat, test.amount, test.expected, got)
+		}
+	}
+}
+
+func TestParseFeeBounds(t *testing.T) {
+	t.Parallel()
+
+	for _, percent := range []string{"-1", "100.5", "abc"} {
+		if _, err := ParseFee(FeeConfig{Percent: percent}); err == nil {
+			t.Errorf("expected percentage %s to be rejected", percent)
+		}
+	}
+
+	if _, err := ParseFee(FeeConfig{Flat: types.NewFIL(big.NewInt(-1))}); err == nil {
+		t.Errorf("expected a negative flat fee to be rejected")
+	}
+}
@@ -1,26 +1,56 @@
 package bank
 
 import (
-	"context"
+	"encoding/hex"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/crypto"
 	"github.com/subvisual/fidl/types"
 )
 
 type envelope map[string]any
 
+const (
+	defaultTransactionsLimit   = 50
+	defaultAuthorizationsLimit = 50
+)
+
+// routeNames are the names the routes are rate limited under in Routes, and configured with.
+var routeNames = []string{
+	"register", "deregister", "deposit", "withdraw", "withdrawal", "balance", "authorize", "authorizations",
+	"authorization", "cancel", "refund", "redeem", "verify", "ledger", "transactions", "fees", "open-channel",
+	"channel", "settle-channel", "close-channel", "solvency", "solvency-proof",
+}
+
 func (s *Server) Routes(r chi.Router) {
 	r.Route("/", func(r chi.Router) {
-		r.With(AuthenticationCtx()).Post("/register", s.handleRegisterProxy)
-		r.With(AuthenticationCtx()).With(ReadTimeoutCtx(s.CustomReadTimeout)).Post("/deposit", s.handleDeposit)
-		r.With(AuthenticationCtx()).Post("/withdraw", s.handleWithdraw)
-		r.With(AuthenticationCtx()).Get("/balance", s.handleBalance)
-		r.With(AuthenticationCtx()).Post("/authorize", s.handleAuthorize)
-		r.With(AuthenticationCtx()).Get("/refund", s.handleRefund)
-		r.With(AuthenticationCtx()).Post("/redeem", s.handleRedeem)
-		r.With(AuthenticationCtx()).Post("/verify", s.handleVerify)
+		r.With(s.IPRateLimitCtx("register"), s.AuthenticationCtx(), s.AddressRateLimitCtx("register"), s.IdempotencyCtx()).Post("/register", s.handleRegisterProxy)
+		r.With(s.IPRateLimitCtx("deregister"), s.AuthenticationCtx(), s.AddressRateLimitCtx("deregister"), s.IdempotencyCtx()).Post("/deregister", s.handleDeregisterProxy)
+		r.With(s.IPRateLimitCtx("deposit"), s.AuthenticationCtx(), s.AddressRateLimitCtx("deposit"), s.IdempotencyCtx()).With(ReadTimeoutCtx(s.CustomReadTimeout)).Post("/deposit", s.handleDeposit)
+		r.With(s.IPRateLimitCtx("withdraw"), s.AuthenticationCtx(), s.AddressRateLimitCtx("withdraw"), s.IdempotencyCtx()).Post("/withdraw", s.handleWithdraw)
+		r.With(s.IPRateLimitCtx("withdrawal"), s.AuthenticationCtx(), s.AddressRateLimitCtx("withdrawal")).Get("/withdrawals/{id}", s.handleWithdrawal)
+		r.With(s.IPRateLimitCtx("balance"), s.AuthenticationCtx(), s.AddressRateLimitCtx("balance")).Get("/balance", s.handleBalance)
+		r.With(s.IPRateLimitCtx("authorize"), s.AuthenticationCtx(), s.AddressRateLimitCtx("authorize"), s.IdempotencyCtx()).Post("/authorize", s.handleAuthorize)
+		r.With(s.IPRateLimitCtx("authorizations"), s.AuthenticationCtx(), s.AddressRateLimitCtx("authorizations")).Get("/authorizations", s.handleAuthorizations)
+		r.With(s.IPRateLimitCtx("authorization"), s.AuthenticationCtx(), s.AddressRateLimitCtx("authorization")).Get("/authorizations/{id}", s.handleAuthorization)
+		r.With(s.IPRateLimitCtx("cancel"), s.AuthenticationCtx(), s.AddressRateLimitCtx("cancel")).Delete("/authorizations/{id}", s.handleCancelAuthorization)
+		r.With(s.IPRateLimitCtx("refund"), s.AuthenticationCtx(), s.AddressRateLimitCtx("refund")).Get("/refund", s.handleRefund)
+		r.With(s.IPRateLimitCtx("redeem"), s.AuthenticationCtx(), s.AddressRateLimitCtx("redeem"), s.IdempotencyCtx()).Post("/redeem", s.handleRedeem)
+		r.With(s.IPRateLimitCtx("verify"), s.AuthenticationCtx(), s.AddressRateLimitCtx("verify"), s.IdempotencyCtx()).Post("/verify", s.handleVerify)
+		r.With(s.IPRateLimitCtx("ledger"), s.AuthenticationCtx(), s.AddressRateLimitCtx("ledger")).Get("/ledger", s.handleLedger)
+		r.With(s.IPRateLimitCtx("transactions"), s.AuthenticationCtx(), s.AddressRateLimitCtx("transactions")).Get("/transactions", s.handleTransactions)
+		r.With(s.IPRateLimitCtx("fees"), s.AuthenticationCtx(), s.AddressRateLimitCtx("fees")).Get("/fees", s.handleFees)
+		r.With(s.IPRateLimitCtx("open-channel"), s.AuthenticationCtx(), s.AddressRateLimitCtx("open-channel"), s.IdempotencyCtx()).Post("/channels", s.handleOpenChannel)
+		r.With(s.IPRateLimitCtx("channel"), s.AuthenticationCtx(), s.AddressRateLimitCtx("channel")).Get("/channels/{id}", s.handleChannel)
+		r.With(s.IPRateLimitCtx("settle-channel"), s.AuthenticationCtx(), s.AddressRateLimitCtx("settle-channel"), s.IdempotencyCtx()).Post("/channels/{id}/settle", s.handleSettleChannel)
+		r.With(s.IPRateLimitCtx("close-channel"), s.AuthenticationCtx(), s.AddressRateLimitCtx("close-channel"), s.IdempotencyCtx()).Post("/channels/{id}/close", s.handleCloseChannel)
+		r.With(s.IPRateLimitCtx("solvency"), s.AuthenticationCtx(), s.AddressRateLimitCtx("solvency")).Get("/solvency", s.handleSolvency)
+		r.With(s.IPRateLimitCtx("solvency-proof"), s.AuthenticationCtx(), s.AddressRateLimitCtx("solvency-proof")).Get("/solvency/proof", s.handleSolvencyProof)
 	})
 }
 
@@ -43,12 +73,45 @@ func (s *Server) handleRegisterProxy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.BankService.RegisterProxy(params.ID, address.String(), params.Price); err != nil {
+	render := func(any) envelope { return envelope{"bank": "proxy registered"} }
+	respondWith(s, r, http.StatusOK, render)
+
+	if err := s.BankService.RegisterProxy(r.Context(), params.ID, address.String(), params.Price); err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.JSON(w, r, http.StatusOK, envelope{"bank": "proxy registered"})
+	s.JSON(w, r, http.StatusOK, render(nil))
+}
+
+func (s *Server) handleDeregisterProxy(w http.ResponseWriter, r *http.Request) {
+	var params DeregisterParams
+
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.DecodeJSON(w, r, &params); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	render := func(any) envelope { return envelope{"bank": "proxy deregistering"} }
+	respondWith(s, r, http.StatusOK, render)
+
+	if err := s.BankService.Deregister(r.Context(), address.String(), params.Destination); err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, render(nil))
 }
 
 func (s *Server) handleDeposit(w http.ResponseWriter, r *http.Request) {
@@ -70,14 +133,17 @@ func (s *Server) handleDeposit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	valid, err := s.BankService.ValidateBlockchainTransaction(params.TransactionHash)
+	// Hashes are kept in lower case, as the deposit watcher finds them, so that a deposit is credited once.
+	params.TransactionHash = strings.ToLower(params.TransactionHash)
+
+	valid, err := s.BankService.ValidateBlockchainTransaction(r.Context(), params.TransactionHash)
 	if !valid || err != nil {
 		s.JSON(w, r, http.StatusConflict, envelope{"message": err.Error()})
 		return
 	}
 
 	// nolint:contextcheck
-	err = s.BlockChainService.VerifyTransaction(context.Background(), blockchain.VerifyTransactionOptions{
+	err = s.BlockChainService.VerifyTransaction(r.Context(), blockchain.VerifyTransactionOptions{
 		Hash:  params.TransactionHash,
 		From:  address.String(),
 		Value: params.Amount,
@@ -87,13 +153,16 @@ func (s *Server) handleDeposit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fil, err := s.BankService.Deposit(address.String(), params.Amount, params.TransactionHash)
+	render := func(fil types.FIL) envelope { return envelope{"fil": fil} }
+	respondWith(s, r, http.StatusOK, render)
+
+	fil, err := s.BankService.Deposit(r.Context(), address.String(), params.Amount, params.TransactionHash)
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.JSON(w, r, http.StatusOK, envelope{"fil": fil})
+	s.JSON(w, r, http.StatusOK, render(fil))
 }
 
 func (s *Server) handleWithdraw(w http.ResponseWriter, r *http.Request) {
@@ -115,31 +184,56 @@ func (s *Server) handleWithdraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ethAddr, _, err := types.ParseAddress(params.Destination)
-	if err != nil {
+	if _, _, err := types.ParseAddress(params.Destination); err != nil {
 		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
 		return
 	}
 
-	fil, err := s.BankService.Withdraw(address.String(), params.Destination, params.Amount)
+	render := func(withdraw WithdrawModel) envelope {
+		return envelope{"fil": withdraw.Available, "fee": withdraw.Fee, "id": withdraw.ID, "status": "Pending"}
+	}
+	respondWith(s, r, http.StatusOK, render)
+
+	withdraw, err := s.BankService.Withdraw(r.Context(), address.String(), params.Destination, params.Amount)
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	hash, err := s.BlockChainService.Transfer(r.Context(), ethAddr, params.Amount)
+	if s.WithdrawalWorker != nil {
+		s.WithdrawalWorker.Notify()
+	}
+
+	s.JSON(w, r, http.StatusOK, render(withdraw))
+}
+
+func (s *Server) handleWithdrawal(w http.ResponseWriter, r *http.Request) {
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
-		s.JSON(w, r, http.StatusInternalServerError, err)
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
 		return
 	}
 
-	err = s.BankService.RegisterWithdrawTransaction(address.String(), params.Destination, params.Amount, hash)
+	withdrawal, err := s.BankService.Withdrawal(r.Context(), address.String(), id)
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.JSON(w, r, http.StatusOK, envelope{"fil": fil, "hash": hash})
+	s.JSON(w, r, http.StatusOK, envelope{
+		"id":     withdrawal.ID,
+		"fil":    withdrawal.Amount,
+		"fee":    withdrawal.Fee,
+		"dst":    withdrawal.Destination,
+		"status": withdrawal.Status,
+		"hash":   withdrawal.Hash,
+	})
 }
 
 func (s *Server) handleBalance(w http.ResponseWriter, r *http.Request) {
@@ -149,7 +243,7 @@ func (s *Server) handleBalance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fil, escrow, err := s.BankService.Balance(address.String())
+	fil, escrow, err := s.BankService.Balance(r.Context(), address.String())
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
@@ -177,13 +271,124 @@ func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	auth, err := s.BankService.Authorize(address.String(), params.Proxy)
+	render := func(auth AuthModel) envelope {
+		return envelope{"fil": auth.Available, "escrow": auth.Escrow, "id": auth.UUID, "mode": auth.Mode, "expires_at": auth.ExpiresAt}
+	}
+	respondWith(s, r, http.StatusOK, render)
+
+	auth, err := s.BankService.Authorize(r.Context(), address.String(), params)
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.JSON(w, r, http.StatusOK, envelope{"fil": auth.Available, "escrow": auth.Escrow, "id": auth.UUID})
+	s.JSON(w, r, http.StatusOK, render(auth))
+}
+
+func authorizationEnvelope(auth Authorization) envelope {
+	return envelope{
+		"id":         auth.UUID,
+		"client":     auth.Client,
+		"proxy":      auth.Proxy,
+		"mode":       auth.Mode,
+		"status":     auth.Status,
+		"amount":     auth.Amount,
+		"remaining":  auth.Remaining,
+		"redeemed":   auth.Redeemed,
+		"price":      auth.Price,
+		"max_price":  auth.MaxPrice,
+		"expires_at": auth.ExpiresAt,
+		"created_at": auth.CreatedAt,
+		"updated_at": auth.UpdatedAt,
+	}
+}
+
+func (s *Server) handleAuthorizations(w http.ResponseWriter, r *http.Request) {
+	var params AuthorizationsParams
+
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.Decode(&params, r.URL.Query()); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	params.Status = strings.ToLower(params.Status)
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultAuthorizationsLimit
+	}
+
+	authorizations, err := s.BankService.Authorizations(r.Context(), address.String(), params)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	list := make([]envelope, 0, len(authorizations))
+	for _, a := range authorizations {
+		list = append(list, authorizationEnvelope(a))
+	}
+
+	res := envelope{"authorizations": list}
+	if len(authorizations) == params.Limit {
+		res["cursor"] = authorizations[len(authorizations)-1].UUID
+	}
+
+	s.JSON(w, r, http.StatusOK, res)
+}
+
+func (s *Server) handleAuthorization(w http.ResponseWriter, r *http.Request) {
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	auth, err := s.BankService.Authorization(r.Context(), address.String(), id)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, authorizationEnvelope(auth))
+}
+
+func (s *Server) handleCancelAuthorization(w http.ResponseWriter, r *http.Request) {
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	cancel, err := s.BankService.CancelAuthorization(r.Context(), address.String(), id)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, envelope{"fil": cancel.Available, "escrow": cancel.Escrow, "released": cancel.Released})
 }
 
 func (s *Server) handleRefund(w http.ResponseWriter, r *http.Request) {
@@ -193,7 +398,7 @@ func (s *Server) handleRefund(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	balances, err := s.BankService.Refund(address.String())
+	balances, err := s.BankService.Refund(r.Context(), address.String())
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
@@ -221,13 +426,18 @@ func (s *Server) handleRedeem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	balances, err := s.BankService.Redeem(address.String(), params.UUID, params.Amount)
+	render := func(balances RedeemModel) envelope {
+		return envelope{"excess": balances.Excess, "remaining": balances.Remaining, "fee": balances.Fee, "sp": balances.SP, "cli": balances.CLI}
+	}
+	respondWith(s, r, http.StatusOK, render)
+
+	balances, err := s.BankService.Redeem(r.Context(), address.String(), params.UUID, params.Amount)
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.JSON(w, r, http.StatusOK, envelope{"excess": balances.Excess, "sp": balances.SP, "cli": balances.CLI})
+	s.JSON(w, r, http.StatusOK, render(balances))
 }
 
 func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
@@ -249,11 +459,354 @@ func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.BankService.Verify(address.String(), params.UUID, params.Amount)
+	render := func(price types.FIL) envelope { return envelope{"authorization": "valid", "price": price} }
+	respondWith(s, r, http.StatusOK, render)
+
+	price, err := s.BankService.Verify(r.Context(), address.String(), params.UUID)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, render(price))
+}
+
+func (s *Server) handleLedger(w http.ResponseWriter, r *http.Request) {
+	var params LedgerParams
+
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.Decode(&params, r.URL.Query()); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if params.At.IsZero() {
+		params.At = time.Now().UTC()
+	}
+
+	ledger, err := s.BankService.Ledger(r.Context(), address.String(), params.At)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, envelope{"fil": ledger.Available, "escrow": ledger.Escrow, "at": ledger.At})
+}
+
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	s.writeTransactions(w, r, address.String())
+}
+
+// writeTransactions responds with a page of the transaction history of an account.
+func (s *Server) writeTransactions(w http.ResponseWriter, r *http.Request, address string) {
+	var params TransactionsParams
+
+	if err := s.Decode(&params, r.URL.Query()); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	params.Type = strings.ToLower(params.Type)
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultTransactionsLimit
+	}
+
+	transactions, err := s.BankService.Transactions(r.Context(), address, params)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	history := make([]envelope, 0, len(transactions))
+	for _, t := range transactions {
+		history = append(history, envelope{
+			"id":          t.TransactionID,
+			"type":        t.Type,
+			"counterpart": t.Counterpart,
+			"fil":         t.Amount,
+			"status":      t.Status,
+			"created_at":  t.CreatedAt,
+		})
+	}
+
+	res := envelope{"transactions": history}
+	if len(transactions) == params.Limit {
+		res["cursor"] = transactions[len(transactions)-1].ID
+	}
+
+	s.JSON(w, r, http.StatusOK, res)
+}
+
+func (s *Server) handleFees(w http.ResponseWriter, r *http.Request) {
+	var params FeesParams
+
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.Decode(&params, r.URL.Query()); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	params.Type = strings.ToLower(params.Type)
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultTransactionsLimit
+	}
+
+	report, err := s.BankService.Fees(r.Context(), address.String(), params)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	fees := make([]envelope, 0, len(report.Fees))
+	for _, f := range report.Fees {
+		fees = append(fees, envelope{
+			"id":         f.TransactionID,
+			"type":       f.Type,
+			"payer":      f.Address,
+			"fil":        f.Amount,
+			"created_at": f.CreatedAt,
+		})
+	}
+
+	res := envelope{"total": report.Total, "by_type": report.ByType, "fees": fees}
+	if len(report.Fees) == params.Limit {
+		res["cursor"] = report.Fees[len(report.Fees)-1].ID
+	}
+
+	s.JSON(w, r, http.StatusOK, res)
+}
+
+func channelEnvelope(channel Channel) envelope {
+	return envelope{
+		"id":        channel.UUID,
+		"client":    channel.Client,
+		"proxy":     channel.Proxy,
+		"escrow":    channel.Balance,
+		"redeemed":  channel.Redeemed,
+		"status":    channel.Status,
+		"closes_at": channel.ClosesAt,
+	}
+}
+
+func (s *Server) handleOpenChannel(w http.ResponseWriter, r *http.Request) {
+	var params OpenChannelParams
+
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	if err := s.DecodeJSON(w, r, &params); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	render := func(channel ChannelModel) envelope {
+		res := channelEnvelope(channel.Channel)
+		res["fil"] = channel.Available
+
+		return res
+	}
+	respondWith(s, r, http.StatusOK, render)
+
+	channel, err := s.BankService.OpenChannel(r.Context(), address.String(), params.Proxy, params.Amount)
 	if err != nil {
 		s.JSON(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.JSON(w, r, http.StatusOK, envelope{"authorization": "valid"})
+	s.JSON(w, r, http.StatusOK, render(channel))
+}
+
+func (s *Server) handleChannel(w http.ResponseWriter, r *http.Request) {
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	channel, err := s.BankService.Channel(r.Context(), address.String(), id)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, channelEnvelope(channel))
+}
+
+func (s *Server) handleSettleChannel(w http.ResponseWriter, r *http.Request) {
+	var params SettleChannelParams
+
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.DecodeJSON(w, r, &params); err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	if err := s.Validate.Struct(params); err != nil {
+		s.JSON(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	voucher, err := types.DecodeVoucher(params.Voucher)
+	if err != nil || voucher.Channel != id {
+		s.JSON(w, r, http.StatusInternalServerError, ErrInvalidVoucher)
+		return
+	}
+
+	channel, err := s.BankService.Channel(r.Context(), address.String(), id)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	client, err := types.NewAddressFromString(channel.Client)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := crypto.VerifyVoucher(voucher, *client.Address); err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, ErrInvalidVoucher)
+		return
+	}
+
+	respondWith(s, r, http.StatusOK, channelEnvelope)
+
+	channel, err = s.BankService.SettleChannel(r.Context(), address.String(), id, voucher.Amount)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, channelEnvelope(channel))
+}
+
+func (s *Server) handleCloseChannel(w http.ResponseWriter, r *http.Request) {
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+		return
+	}
+
+	respondWith(s, r, http.StatusOK, channelEnvelope)
+
+	channel, err := s.BankService.CloseChannel(r.Context(), address.String(), id)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, channelEnvelope(channel))
+}
+
+func (s *Server) attestationEnvelope(attestation SignedAttestation) envelope {
+	return envelope{
+		"root":        hex.EncodeToString(attestation.Root),
+		"liabilities": attestation.Liabilities,
+		"reserves":    attestation.Reserves,
+		"accounts":    attestation.Accounts,
+		"solvent":     attestation.Solvent(),
+		"created_at":  attestation.CreatedAt,
+		"signer":      s.Solvency.Signer.String(),
+		"signature":   hex.EncodeToString(attestation.Signature),
+	}
+}
+
+func (s *Server) handleSolvency(w http.ResponseWriter, r *http.Request) {
+	attestation, err := s.Solvency.Latest()
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.JSON(w, r, http.StatusOK, s.attestationEnvelope(attestation))
+}
+
+func (s *Server) handleSolvencyProof(w http.ResponseWriter, r *http.Request) {
+	address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+	if !ok {
+		s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+		return
+	}
+
+	proof, err := s.Solvency.Proof(address.String())
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	steps := make([]envelope, 0, len(proof.Steps))
+	for _, step := range proof.Steps {
+		steps = append(steps, envelope{
+			"hash": hex.EncodeToString(step.Node.Hash),
+			"sum":  types.NewFIL(step.Node.Sum),
+			"left": step.Left,
+		})
+	}
+
+	s.JSON(w, r, http.StatusOK, envelope{
+		"attestation": s.attestationEnvelope(proof.Attestation),
+		"address":     proof.Liability.Address,
+		"amount":      proof.Liability.Amount,
+		"salt":        hex.EncodeToString(proof.Salt),
+		"proof":       steps,
+	})
 }
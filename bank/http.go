@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/subvisual/fidl/http/jsend"
+	"github.com/subvisual/fidl/request"
 	"github.com/subvisual/fidl/types"
 )
 
@@ -33,6 +35,44 @@ func (s *Server) JSON(w http.ResponseWriter, r *http.Request, code int, value an
 			status, body = http.StatusNotFound, envelope{"bank": "no valid authorization"}
 		case errors.Is(err, ErrAuthLocked):
 			status, body = http.StatusNotFound, envelope{"bank": "authorization is locked"}
+		case errors.Is(err, ErrAuthNotCancellable):
+			status, body = http.StatusConflict, envelope{"bank": "authorization is locked by a proxy and cannot be cancelled"}
+		case errors.Is(err, ErrWithdrawalNotFound):
+			status, body = http.StatusNotFound, envelope{"bank": "withdrawal not found"}
+		case errors.Is(err, ErrPriceAboveMax):
+			status, body = http.StatusConflict, envelope{"bank": "storage provider price is above the maximum price"}
+		case errors.Is(err, ErrAmountBelowPrice):
+			status, body = http.StatusUnprocessableEntity, envelope{"bank": "amount is below the storage provider price"}
+		case errors.Is(err, ErrExpiryOutOfBounds):
+			status, body = http.StatusUnprocessableEntity, envelope{"bank": "expiry is outside the allowed bounds"}
+		case errors.Is(err, ErrProxyNotActive):
+			status, body = http.StatusConflict, envelope{"bank": "storage provider is not active"}
+		case errors.Is(err, ErrAmountBelowFee):
+			status, body = http.StatusUnprocessableEntity, envelope{"bank": "amount does not cover the fee"}
+		case errors.Is(err, ErrAccountNotFound):
+			status, body = http.StatusNotFound, envelope{"bank": "account not found"}
+		case errors.Is(err, ErrAccountFrozen):
+			status, body = http.StatusForbidden, envelope{"bank": "account is frozen"}
+		case errors.Is(err, ErrAccountClosed):
+			status, body = http.StatusForbidden, envelope{"bank": "account is closed"}
+		case errors.Is(err, ErrTransactionExists):
+			status, body = http.StatusConflict, envelope{"bank": "transaction already registered"}
+		case errors.Is(err, ErrHeldDepositNotFound):
+			status, body = http.StatusNotFound, envelope{"bank": "held deposit not found"}
+		case errors.Is(err, ErrHeldDepositResolved):
+			status, body = http.StatusConflict, envelope{"bank": "held deposit was already credited or returned"}
+		case errors.Is(err, ErrNoAttestation):
+			status, body = http.StatusServiceUnavailable, envelope{"bank": "no solvency attestation yet"}
+		case errors.Is(err, ErrIdempotencyKeyReused):
+			status, body = http.StatusUnprocessableEntity, envelope{"bank": "idempotency key was used with a different request"}
+		case errors.Is(err, ErrIdempotencyKeyInProgress):
+			status, body = http.StatusConflict, envelope{"bank": "a request with this idempotency key is in progress"}
+		case errors.Is(err, ErrChannelNotFound):
+			status, body = http.StatusNotFound, envelope{"bank": "channel not found"}
+		case errors.Is(err, ErrChannelClosed):
+			status, body = http.StatusConflict, envelope{"bank": "channel is closed"}
+		case errors.Is(err, ErrInvalidVoucher):
+			status, body = http.StatusUnprocessableEntity, envelope{"bank": "invalid voucher"}
 		default:
 			s.Server.JSON(w, r, code, value)
 			return
@@ -52,30 +92,47 @@ func (s *Server) JSON(w http.ResponseWriter, r *http.Request, code int, value an
 	}
 }
 
-func ParseHeader(r *http.Request) (*types.Signature, types.Address, []byte, error) {
-	dataSig := r.Header.Get("sig")
-	dataPub := r.Header.Get("pub")
-	dataMsg := r.Header.Get("msg")
+type SignedHeader struct {
+	Signature *types.Signature
+	Address   types.Address
+	Timestamp int64
+	Nonce     string
+}
 
-	binSig, err := hex.DecodeString(dataSig)
-	if err != nil {
-		return nil, types.Address{}, nil, fmt.Errorf("failed to decode signature string: %w", err)
-	}
+func ParseHeader(r *http.Request) (SignedHeader, error) {
+	dataSig := r.Header.Get(request.HeaderSignature)
+	dataPub := r.Header.Get(request.HeaderPublicKey)
+	dataTimestamp := r.Header.Get(request.HeaderTimestamp)
+	nonce := r.Header.Get(request.HeaderNonce)
 
-	binMsg, err := hex.DecodeString(dataMsg)
+	binSig, err := hex.DecodeString(dataSig)
 	if err != nil {
-		return nil, types.Address{}, nil, fmt.Errorf("failed to decode message string: %w", err)
+		return SignedHeader{}, fmt.Errorf("failed to decode signature string: %w", err)
 	}
 
 	var sig types.Signature
 	if err = sig.UnmarshalBinary(binSig); err != nil {
-		return nil, types.Address{}, nil, fmt.Errorf("failed to unmarshal binary signature: %w", err)
+		return SignedHeader{}, fmt.Errorf("failed to unmarshal binary signature: %w", err)
 	}
 
 	addr, err := types.NewAddressFromString(dataPub)
 	if err != nil {
-		return nil, types.Address{}, nil, fmt.Errorf("failed to parse address from header: %w", err)
+		return SignedHeader{}, fmt.Errorf("failed to parse address from header: %w", err)
+	}
+
+	timestamp, err := strconv.ParseInt(dataTimestamp, 10, 64)
+	if err != nil {
+		return SignedHeader{}, fmt.Errorf("failed to parse timestamp from header: %w", err)
+	}
+
+	if nonce == "" {
+		return SignedHeader{}, fmt.Errorf("missing nonce header")
 	}
 
-	return &sig, addr, binMsg, nil
+	return SignedHeader{
+		Signature: &sig,
+		Address:   addr,
+		Timestamp: timestamp,
+		Nonce:     nonce,
+	}, nil
 }
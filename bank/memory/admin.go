@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+)
+
+// Accounts lists the accounts of the bank with their balances, newest first.
+func (s *BankService) Accounts(_ context.Context, params bank.AccountsParams) ([]bank.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accounts := make([]bank.Account, 0)
+	for _, acc := range s.accounts {
+		if params.Type == "client" && acc.kind != client || params.Type == "provider" && acc.kind != storageProvider {
+			continue
+		}
+
+		if params.Cursor > 0 && acc.id >= params.Cursor {
+			continue
+		}
+
+		accounts = append(accounts, acc.model())
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID > accounts[j].ID
+	})
+
+	if len(accounts) > params.Limit {
+		accounts = accounts[:params.Limit]
+	}
+
+	return accounts, nil
+}
+
+// Totals adds up the balances and escrow held by all the accounts of the bank.
+func (s *BankService) Totals(_ context.Context) (bank.Totals, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	available, escrow := new(big.Int), new(big.Int)
+	for _, acc := range s.accounts {
+		available.Add(available, acc.balance)
+		escrow.Add(escrow, acc.escrow)
+	}
+
+	return bank.Totals{Accounts: len(s.accounts), Available: fil(available), Escrow: fil(escrow)}, nil
+}
+
+// ProxyEscrow lists the authorizations against a storage provider that still hold escrow, expired ones
+// included until they are refunded.
+func (s *BankService) ProxyEscrow(_ context.Context, proxy string) (bank.ProxyEscrow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	total := new(big.Int)
+
+	authorizations := make([]bank.Authorization, 0)
+	for _, auth := range s.authorizations {
+		if auth.proxy != proxy || !auth.pending() {
+			continue
+		}
+
+		total.Add(total, auth.balance)
+		authorizations = append(authorizations, auth.model(now))
+	}
+
+	sort.Slice(authorizations, func(i, j int) bool {
+		return bytes.Compare(authorizations[i].UUID[:], authorizations[j].UUID[:]) == 1
+	})
+
+	return bank.ProxyEscrow{Proxy: proxy, Escrow: fil(total), Authorizations: authorizations}, nil
+}
+
+// ExpireAuthorization expires an open or locked authorization right away, so that its escrow is refunded to
+// the client. The operator and reason are recorded in the audit log.
+func (s *BankService) ExpireAuthorization(_ context.Context, operator string, id uuid.UUID, reason string) (bank.Authorization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	auth, ok := s.authorizations[id]
+	if !ok || !auth.pending() || !auth.expiresAt.After(now) {
+		return bank.Authorization{}, bank.ErrAuthNotFound
+	}
+
+	auth.expiresAt = now
+	auth.updatedAt = now
+
+	s.audit(operator, bank.AuditExpireAuthorization, id.String(), reason)
+
+	return auth.model(now), nil
+}
+
+// SetAccountStatus freezes, closes or reactivates an account. Closing an account is final. The operator and
+// reason are recorded in the audit log.
+func (s *BankService) SetAccountStatus(_ context.Context, operator string, address string, status string, reason string) (bank.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, ok := parseAccountStatus(status)
+	if !ok {
+		return bank.Account{}, fmt.Errorf("unknown account status: %s", status)
+	}
+
+	acc, ok := s.accounts[address]
+	if !ok {
+		return bank.Account{}, bank.ErrAccountNotFound
+	}
+
+	if acc.status == accountClosed {
+		return bank.Account{}, bank.ErrAccountClosed
+	}
+
+	acc.status = next
+	s.audit(operator, bank.AuditAccountStatus(status), address, reason)
+
+	return acc.model(), nil
+}
+
+// AuditLog lists the changes made by operators, newest first.
+func (s *BankService) AuditLog(_ context.Context, params bank.AuditParams) ([]bank.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]bank.AuditEntry, 0, params.Limit)
+	for i := len(s.auditLog) - 1; i >= 0 && len(entries) < params.Limit; i-- {
+		if params.Cursor > 0 && s.auditLog[i].ID >= params.Cursor {
+			continue
+		}
+
+		entries = append(entries, s.auditLog[i])
+	}
+
+	return entries, nil
+}
+
+func (a *account) model() bank.Account {
+	return bank.Account{
+		ID:        a.id,
+		Address:   a.address,
+		Type:      a.kind.String(),
+		Status:    a.status.String(),
+		Available: fil(a.balance),
+		Escrow:    fil(a.escrow),
+	}
+}
+
+// audit records a change made by an operator.
+func (s *BankService) audit(operator string, action string, target string, reason string) {
+	s.lastAuditID++
+	s.auditLog = append(s.auditLog, bank.AuditEntry{
+		ID:        s.lastAuditID,
+		Operator:  operator,
+		Action:    action,
+		Target:    target,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,269 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type authorizationStatus string
+
+const (
+	authorizationOpen      authorizationStatus = "Open"
+	authorizationLocked    authorizationStatus = "Locked"
+	authorizationRedeemed  authorizationStatus = "Redeemed"
+	authorizationRefunded  authorizationStatus = "Refunded"
+	authorizationCancelled authorizationStatus = "Cancelled"
+)
+
+type authorizationMode string
+
+const (
+	authorizationSingle authorizationMode = "Single"
+	authorizationMulti  authorizationMode = "Multi"
+)
+
+type authorization struct {
+	uuid      uuid.UUID
+	client    string
+	proxy     string
+	mode      authorizationMode
+	status    authorizationStatus
+	amount    *big.Int
+	balance   *big.Int
+	redeemed  *big.Int
+	price     *big.Int
+	maxPrice  *big.Int
+	expiresAt time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// pending reports whether the authorization still holds escrow, that is until it is redeemed or given back.
+func (a authorization) pending() bool {
+	return a.status == authorizationOpen || a.status == authorizationLocked
+}
+
+// model reports open and locked authorizations past their expiry as expired until they are refunded,
+// and only those still hold escrow.
+func (a authorization) model(now time.Time) bank.Authorization {
+	status := string(a.status)
+	remaining := new(big.Int)
+
+	if a.pending() {
+		remaining = a.balance
+
+		if !a.expiresAt.After(now) {
+			status = "Expired"
+		}
+	}
+
+	var maxPrice *types.FIL
+	if a.maxPrice != nil {
+		price := fil(a.maxPrice)
+		maxPrice = &price
+	}
+
+	return bank.Authorization{
+		UUID:      a.uuid,
+		Client:    a.client,
+		Proxy:     a.proxy,
+		Mode:      string(a.mode),
+		Status:    status,
+		Amount:    fil(a.amount),
+		Remaining: fil(remaining),
+		Redeemed:  fil(a.redeemed),
+		Price:     fil(a.price),
+		MaxPrice:  maxPrice,
+		ExpiresAt: a.expiresAt,
+		CreatedAt: a.createdAt,
+		UpdatedAt: a.updatedAt,
+	}
+}
+
+// Authorize escrows an amount for a storage provider, defaulting to its price. The amount must cover at
+// least one retrieval at the provider's price, and the price must not be above the client's maximum.
+// A multi authorization can be redeemed several times, until its amount is used up or it expires.
+// The expiry defaults to the escrow deadline, and a client asking for its own must stay within the bank's bounds.
+// The provider's price is kept with the authorization, so that later price changes do not apply to it.
+func (s *BankService) Authorize(ctx context.Context, address string, params bank.AuthorizeParams) (bank.AuthModel, error) {
+	mode := authorizationSingle
+	if params.Mode == "multi" {
+		mode = authorizationMulti
+	}
+
+	expiry, err := s.authorizationExpiry(params.ExpiresAt)
+	if err != nil {
+		return bank.AuthModel{}, err
+	}
+
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.AuthModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return bank.AuthModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return bank.AuthModel{}, fmt.Errorf("failed to fetch cli account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationAuthorize); err != nil {
+		return bank.AuthModel{}, err
+	}
+
+	if _, err := s.account(params.Proxy); err != nil {
+		return bank.AuthModel{}, fmt.Errorf("failed to fetch sp account: %w", err)
+	}
+
+	sp, err := s.activeProvider(params.Proxy)
+	if err != nil {
+		return bank.AuthModel{}, err
+	}
+
+	if params.MaxPrice != nil && sp.price.Cmp(params.MaxPrice.Int) == 1 {
+		return bank.AuthModel{}, bank.ErrPriceAboveMax
+	}
+
+	cost := sp.price
+	if params.Amount != nil {
+		if params.Amount.Cmp(sp.price) == -1 {
+			return bank.AuthModel{}, bank.ErrAmountBelowPrice
+		}
+
+		cost = params.Amount.Int
+	}
+
+	if cost.Cmp(acc.balance) == 1 {
+		return bank.AuthModel{}, bank.ErrInsufficientFunds
+	}
+
+	acc.balance.Sub(acc.balance, cost)
+	acc.escrow.Add(acc.escrow, cost)
+
+	auth := &authorization{
+		uuid:      id,
+		client:    address,
+		proxy:     params.Proxy,
+		mode:      mode,
+		status:    authorizationOpen,
+		amount:    new(big.Int).Set(cost),
+		balance:   new(big.Int).Set(cost),
+		redeemed:  new(big.Int),
+		price:     new(big.Int).Set(sp.price),
+		expiresAt: expiry,
+		createdAt: time.Now().UTC(),
+	}
+	auth.updatedAt = auth.createdAt
+
+	if params.MaxPrice != nil {
+		auth.maxPrice = new(big.Int).Set(params.MaxPrice.Int)
+	}
+
+	s.authorizations[id] = auth
+
+	s.recordTransaction(transaction{
+		transactionID: transactionID.String(),
+		source:        s.cfg.WalletAddress,
+		destination:   s.cfg.EscrowAddress,
+		value:         cost,
+		status:        transactionCompleted,
+		address:       address,
+		counterpart:   params.Proxy,
+		kind:          transactionAuthorize,
+	})
+
+	s.postJournal(transactionID.String(),
+		debit(address, ledgerBalance, cost),
+		credit(address, ledgerEscrow, cost),
+	)
+
+	return respond(ctx, s, bank.AuthModel{
+		UUID:      id,
+		Mode:      string(mode),
+		Available: fil(acc.balance),
+		Escrow:    fil(auth.balance),
+		ExpiresAt: auth.expiresAt,
+	})
+}
+
+// authorizationExpiry returns when a new authorization expires, checking a requested expiry against the
+// escrow deadline bounds.
+func (s *BankService) authorizationExpiry(requested *time.Time) (time.Time, error) {
+	now := time.Now().UTC()
+
+	if requested == nil {
+		return now.Add(s.cfg.EscrowDeadline), nil
+	}
+
+	lifetime := requested.Sub(now)
+	if lifetime < s.cfg.EscrowMinDeadline || lifetime > s.cfg.EscrowMaxDeadline {
+		return time.Time{}, bank.ErrExpiryOutOfBounds
+	}
+
+	return requested.UTC(), nil
+}
+
+// Authorizations lists the authorizations the given address is the client or storage provider of, newest first.
+func (s *BankService) Authorizations(_ context.Context, address string, params bank.AuthorizationsParams) ([]bank.Authorization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	authorizations := make([]bank.Authorization, 0)
+	for _, auth := range s.authorizations {
+		if auth.client != address && auth.proxy != address {
+			continue
+		}
+
+		if params.Cursor != uuid.Nil && bytes.Compare(auth.uuid[:], params.Cursor[:]) >= 0 {
+			continue
+		}
+
+		model := auth.model(now)
+		if params.Status != "" && strings.ToLower(model.Status) != params.Status {
+			continue
+		}
+
+		authorizations = append(authorizations, model)
+	}
+
+	sort.Slice(authorizations, func(i, j int) bool {
+		return bytes.Compare(authorizations[i].UUID[:], authorizations[j].UUID[:]) == 1
+	})
+
+	if len(authorizations) > params.Limit {
+		authorizations = authorizations[:params.Limit]
+	}
+
+	return authorizations, nil
+}
+
+// Authorization returns an authorization of which the given address is the client or storage provider.
+func (s *BankService) Authorization(_ context.Context, address string, id uuid.UUID) (bank.Authorization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auth, ok := s.authorizations[id]
+	if !ok || (auth.client != address && auth.proxy != address) {
+		return bank.Authorization{}, bank.ErrAuthNotFound
+	}
+
+	return auth.model(time.Now().UTC()), nil
+}
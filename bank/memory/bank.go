@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+var (
+	errAccountNotFound  = errors.New("account not found")
+	errProviderNotFound = errors.New("storage provider not found")
+)
+
+type BankConfig struct {
+	WalletAddress       string
+	EscrowAddress       string
+	EscrowDeadline      time.Duration
+	EscrowMinDeadline   time.Duration
+	EscrowMaxDeadline   time.Duration
+	ChannelSettleWindow string
+	ChannelLifetime     time.Duration
+	FrozenAllowed       []bank.Operation
+	Fees                bank.FeeSchedule
+}
+
+// BankService is a bank.Service that keeps its state in memory, for development and tests. Every method
+// holds the service lock for its whole run, so operations apply one at a time as the transactions of
+// the postgres service do. Nothing is changed before an operation is known to succeed.
+type BankService struct {
+	cfg *BankConfig
+
+	mu                sync.Mutex
+	lastAccountID     int64
+	lastTransactionID int64
+	lastFeeID         int64
+	lastAuditID       int64
+	lastHeldID        int64
+	accounts          map[string]*account
+	providers         map[string]*provider
+	authorizations    map[uuid.UUID]*authorization
+	channels          map[uuid.UUID]*channel
+	withdrawals       map[uuid.UUID]*withdrawal
+	heldDeposits      []*heldDeposit
+	transactions      []*transaction
+	ledger            []ledgerEntry
+	fees              []*fee
+	auditLog          []bank.AuditEntry
+	nonces            map[requestKey]time.Time
+	idempotencyKeys   map[requestKey]*idempotencyKey
+	limiter           *RateLimiter
+	cursors           map[string]uint64
+}
+
+func NewBankService(cfg *BankConfig) *BankService {
+	return &BankService{
+		cfg:             cfg,
+		accounts:        make(map[string]*account),
+		providers:       make(map[string]*provider),
+		authorizations:  make(map[uuid.UUID]*authorization),
+		channels:        make(map[uuid.UUID]*channel),
+		withdrawals:     make(map[uuid.UUID]*withdrawal),
+		nonces:          make(map[requestKey]time.Time),
+		idempotencyKeys: make(map[requestKey]*idempotencyKey),
+		limiter:         NewRateLimiter(),
+		cursors:         make(map[string]uint64),
+	}
+}
+
+type accountType int8
+
+const (
+	storageProvider accountType = iota + 1
+	client
+)
+
+func (a accountType) String() string {
+	switch a {
+	case storageProvider:
+		return "Storage Provider"
+	case client:
+		return "Client"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type accountStatus int8
+
+const (
+	accountActive accountStatus = iota + 1
+	accountFrozen
+	accountClosed
+)
+
+func (a accountStatus) String() string {
+	switch a {
+	case accountActive:
+		return "Active"
+	case accountFrozen:
+		return "Frozen"
+	case accountClosed:
+		return "Closed"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+func parseAccountStatus(name string) (accountStatus, bool) {
+	for status := accountActive; status <= accountClosed; status++ {
+		if strings.EqualFold(status.String(), name) {
+			return status, true
+		}
+	}
+
+	return 0, false
+}
+
+type account struct {
+	id      int64
+	address string
+	kind    accountType
+	status  accountStatus
+	balance *big.Int
+	escrow  *big.Int
+}
+
+// usable refuses the operations of closed accounts, and those of frozen accounts that are not listed in
+// [accounts] frozen-allowed.
+func (s *BankService) usable(a *account, op bank.Operation) error {
+	switch {
+	case a.status == accountClosed:
+		return bank.ErrAccountClosed
+	case a.status == accountFrozen && !slices.Contains(s.cfg.FrozenAllowed, op):
+		return bank.ErrAccountFrozen
+	default:
+		return nil
+	}
+}
+
+func (s *BankService) account(address string) (*account, error) {
+	acc, ok := s.accounts[address]
+	if !ok {
+		return nil, fmt.Errorf("failed to fetch account by wallet address: %w", errAccountNotFound)
+	}
+
+	return acc, nil
+}
+
+func (s *BankService) createAccount(address string, kind accountType) *account {
+	s.lastAccountID++
+
+	acc := &account{
+		id:      s.lastAccountID,
+		address: address,
+		kind:    kind,
+		status:  accountActive,
+		balance: new(big.Int),
+		escrow:  new(big.Int),
+	}
+	s.accounts[address] = acc
+
+	return acc
+}
+
+// deleteEmptyClient removes a client account that holds no funds. Clients with authorizations are kept,
+// as their authorizations are history.
+func (s *BankService) deleteEmptyClient(acc *account) {
+	if acc.kind != client || acc.balance.Sign() != 0 || acc.escrow.Sign() != 0 {
+		return
+	}
+
+	for _, auth := range s.authorizations {
+		if auth.client == acc.address {
+			return
+		}
+	}
+
+	delete(s.accounts, acc.address)
+}
+
+// fil copies an amount, so that the returned value does not alias the service state.
+func fil(amount *big.Int) types.FIL {
+	return types.NewFIL(new(big.Int).Set(amount))
+}
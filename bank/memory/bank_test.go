@@ -0,0 +1,27 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/bank/banktest"
+	"github.com/subvisual/fidl/bank/memory"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	banktest.Run(t, func(_ *testing.T, cfg banktest.Config) bank.Service {
+		return memory.NewBankService(&memory.BankConfig{
+			WalletAddress:       cfg.WalletAddress,
+			EscrowAddress:       cfg.EscrowAddress,
+			EscrowDeadline:      cfg.EscrowDeadline,
+			EscrowMinDeadline:   cfg.EscrowMinDeadline,
+			EscrowMaxDeadline:   cfg.EscrowMaxDeadline,
+			ChannelSettleWindow: cfg.ChannelSettleWindow,
+			ChannelLifetime:     cfg.ChannelLifetime,
+			FrozenAllowed:       cfg.FrozenAllowed,
+			Fees:                cfg.Fees,
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+)
+
+// CancelAuthorization releases an open authorization of the client back to its balance. An authorization
+// locked by a proxy's Verify is being redeemed, so it cannot be cancelled until the proxy is done with it.
+func (s *BankService) CancelAuthorization(_ context.Context, address string, id uuid.UUID) (bank.CancelModel, error) {
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.CancelModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return bank.CancelModel{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationCancel); err != nil {
+		return bank.CancelModel{}, err
+	}
+
+	auth, ok := s.authorizations[id]
+	if !ok || auth.client != address {
+		return bank.CancelModel{}, bank.ErrAuthNotFound
+	}
+
+	switch auth.status {
+	case authorizationOpen:
+	case authorizationLocked:
+		return bank.CancelModel{}, bank.ErrAuthNotCancellable
+	default:
+		return bank.CancelModel{}, bank.ErrAuthNotFound
+	}
+
+	auth.status = authorizationCancelled
+	auth.updatedAt = time.Now().UTC()
+
+	acc.balance.Add(acc.balance, auth.balance)
+	acc.escrow.Sub(acc.escrow, auth.balance)
+
+	s.recordTransaction(transaction{
+		transactionID: transactionID.String(),
+		source:        s.cfg.EscrowAddress,
+		destination:   s.cfg.WalletAddress,
+		value:         auth.balance,
+		status:        transactionCompleted,
+		address:       address,
+		counterpart:   auth.proxy,
+		kind:          transactionRefund,
+	})
+
+	s.postJournal(transactionID.String(),
+		debit(address, ledgerEscrow, auth.balance),
+		credit(address, ledgerBalance, auth.balance),
+	)
+
+	return bank.CancelModel{
+		Released:  fil(auth.balance),
+		Available: fil(acc.balance),
+		Escrow:    fil(acc.escrow),
+	}, nil
+}
@@ -0,0 +1,376 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type channelStatus string
+
+const (
+	channelOpen    channelStatus = "Open"
+	channelClosing channelStatus = "Closing"
+	channelClosed  channelStatus = "Closed"
+)
+
+type channel struct {
+	uuid      uuid.UUID
+	client    string
+	proxy     string
+	balance   *big.Int
+	redeemed  *big.Int
+	status    channelStatus
+	closesAt  time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+func (c channel) model() bank.Channel {
+	return bank.Channel{
+		UUID:      c.uuid,
+		Client:    c.client,
+		Proxy:     c.proxy,
+		Balance:   fil(c.balance),
+		Redeemed:  fil(c.redeemed),
+		Status:    string(c.status),
+		ClosesAt:  c.closesAt,
+		CreatedAt: c.createdAt,
+	}
+}
+
+// settleable reports whether the proxy may still redeem vouchers of the channel.
+func (c channel) settleable(now time.Time) bool {
+	switch c.status {
+	case channelOpen:
+		return true
+	case channelClosing:
+		return now.Before(c.closesAt)
+	default:
+		return false
+	}
+}
+
+// OpenChannel moves an amount of the client's balance to escrow, to be paid to a storage provider with vouchers.
+func (s *BankService) OpenChannel(ctx context.Context, address string, proxy string, amount types.FIL) (bank.ChannelModel, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return bank.ChannelModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return bank.ChannelModel{}, fmt.Errorf("failed to fetch cli account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationOpenChannel); err != nil {
+		return bank.ChannelModel{}, err
+	}
+
+	if acc.kind != client {
+		return bank.ChannelModel{}, bank.ErrOperationNotAllowed
+	}
+
+	spAccount, err := s.account(proxy)
+	if err != nil {
+		return bank.ChannelModel{}, fmt.Errorf("failed to fetch sp account: %w", err)
+	}
+
+	if spAccount.kind != storageProvider {
+		return bank.ChannelModel{}, bank.ErrOperationNotAllowed
+	}
+
+	if _, err := s.activeProvider(proxy); err != nil {
+		return bank.ChannelModel{}, err
+	}
+
+	if amount.Cmp(acc.balance) == 1 {
+		return bank.ChannelModel{}, bank.ErrInsufficientFunds
+	}
+
+	acc.balance.Sub(acc.balance, amount.Int)
+	acc.escrow.Add(acc.escrow, amount.Int)
+
+	c := &channel{
+		uuid:      id,
+		client:    address,
+		proxy:     proxy,
+		balance:   new(big.Int).Set(amount.Int),
+		redeemed:  new(big.Int),
+		status:    channelOpen,
+		createdAt: time.Now().UTC(),
+	}
+	c.updatedAt = c.createdAt
+	s.channels[id] = c
+
+	s.recordTransaction(transaction{
+		transactionID: id.String(),
+		source:        s.cfg.WalletAddress,
+		destination:   s.cfg.EscrowAddress,
+		value:         amount.Int,
+		status:        transactionCompleted,
+		address:       address,
+		counterpart:   proxy,
+		kind:          transactionAuthorize,
+	})
+
+	s.postJournal(id.String(),
+		debit(address, ledgerBalance, amount.Int),
+		credit(address, ledgerEscrow, amount.Int),
+	)
+
+	return respond(ctx, s, bank.ChannelModel{Channel: c.model(), Available: fil(acc.balance)})
+}
+
+// Channel returns a channel to its client or to its storage provider.
+func (s *BankService) Channel(_ context.Context, address string, id uuid.UUID) (bank.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.channel(address, id)
+	if err != nil {
+		return bank.Channel{}, err
+	}
+
+	return c.model(), nil
+}
+
+// SettleChannel pays the storage provider the difference between the amount of its latest voucher and what
+// it already redeemed from the channel. The voucher signature must be checked by the caller.
+func (s *BankService) SettleChannel(ctx context.Context, address string, id uuid.UUID, amount types.FIL) (bank.Channel, error) {
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.Channel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.channel(address, id)
+	if err != nil {
+		return bank.Channel{}, err
+	}
+
+	if c.proxy != address {
+		return bank.Channel{}, bank.ErrOperationNotAllowed
+	}
+
+	if !c.settleable(time.Now().UTC()) {
+		return bank.Channel{}, bank.ErrChannelClosed
+	}
+
+	if amount.Cmp(c.balance) == 1 {
+		return bank.Channel{}, bank.ErrInvalidVoucher
+	}
+
+	// An older voucher was already settled, there is nothing left to pay.
+	if amount.Cmp(c.redeemed) <= 0 {
+		return respond(ctx, s, c.model())
+	}
+
+	spAccount, err := s.account(c.proxy)
+	if err != nil {
+		return bank.Channel{}, fmt.Errorf("failed to fetch sp account: %w", err)
+	}
+
+	if err := s.usable(spAccount, bank.OperationSettleChannel); err != nil {
+		return bank.Channel{}, err
+	}
+
+	cli, err := s.account(c.client)
+	if err != nil {
+		return bank.Channel{}, fmt.Errorf("failed to fetch cli account: %w", err)
+	}
+
+	delta := new(big.Int).Sub(amount.Int, c.redeemed)
+
+	c.redeemed.Set(amount.Int)
+	c.updatedAt = time.Now().UTC()
+
+	spAccount.balance.Add(spAccount.balance, delta)
+	cli.escrow.Sub(cli.escrow, delta)
+
+	s.recordTransaction(transaction{
+		transactionID: transactionID.String(),
+		source:        s.cfg.EscrowAddress,
+		destination:   s.cfg.WalletAddress,
+		value:         delta,
+		status:        transactionCompleted,
+		address:       c.client,
+		counterpart:   c.proxy,
+		kind:          transactionRedeem,
+	})
+
+	s.postJournal(transactionID.String(),
+		debit(c.client, ledgerEscrow, delta),
+		credit(c.proxy, ledgerBalance, delta),
+	)
+
+	return respond(ctx, s, c.model())
+}
+
+// CloseChannel closes a channel and returns what was not redeemed to the client. The storage provider closes
+// it right away, after settling its latest voucher. The client starts a settle window instead, so the storage
+// provider can still redeem its vouchers, and closes it by calling again once the window is over.
+func (s *BankService) CloseChannel(ctx context.Context, address string, id uuid.UUID) (bank.Channel, error) {
+	window, err := time.ParseDuration(s.cfg.ChannelSettleWindow)
+	if err != nil {
+		return bank.Channel{}, fmt.Errorf("failed to parse channel settle window from config: %w", err)
+	}
+
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.Channel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.channel(address, id)
+	if err != nil {
+		return bank.Channel{}, err
+	}
+
+	acc, err := s.account(address)
+	if err != nil {
+		return bank.Channel{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationCloseChannel); err != nil {
+		return bank.Channel{}, err
+	}
+
+	now := time.Now().UTC()
+
+	switch {
+	case c.status == channelClosed:
+		return bank.Channel{}, bank.ErrChannelClosed
+	case address == c.proxy:
+	case c.status == channelOpen:
+		c.status = channelClosing
+		c.closesAt = now.Add(window)
+		c.updatedAt = now
+
+		return respond(ctx, s, c.model())
+	case c.settleable(now):
+		return respond(ctx, s, c.model())
+	}
+
+	if err := s.closeChannel(c, transactionID, now); err != nil {
+		return bank.Channel{}, err
+	}
+
+	return respond(ctx, s, c.model())
+}
+
+// SweepChannels closes the channels that nobody closes. A channel open for longer than the channel lifetime
+// starts closing as if its client closed it, and a channel whose settle window is over is closed and what
+// was not redeemed returned to its client. It returns how many channels it moved along.
+func (s *BankService) SweepChannels(_ context.Context, limit int) (int, error) {
+	window, err := time.ParseDuration(s.cfg.ChannelSettleWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse channel settle window from config: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	var stale []*channel
+	for _, c := range s.channels {
+		expired := c.status == channelOpen && s.cfg.ChannelLifetime > 0 && !c.createdAt.After(now.Add(-s.cfg.ChannelLifetime))
+		if expired || (c.status == channelClosing && !c.settleable(now)) {
+			stale = append(stale, c)
+		}
+	}
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].createdAt.Before(stale[j].createdAt) })
+
+	if len(stale) > limit {
+		stale = stale[:limit]
+	}
+
+	var errs []error
+	for _, c := range stale {
+		if c.status == channelOpen {
+			c.status = channelClosing
+			c.closesAt = now.Add(window)
+			c.updatedAt = now
+
+			continue
+		}
+
+		transactionID, err := uuid.NewV7()
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		if err := s.closeChannel(c, transactionID, now); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", c.uuid, err))
+		}
+	}
+
+	return len(stale) - len(errs), errors.Join(errs...)
+}
+
+// closeChannel closes a channel that can no longer be settled, and returns what was not redeemed to its client.
+func (s *BankService) closeChannel(c *channel, transactionID uuid.UUID, now time.Time) error {
+	remaining := new(big.Int).Sub(c.balance, c.redeemed)
+
+	var cli *account
+	if remaining.Sign() != 0 {
+		var err error
+		if cli, err = s.account(c.client); err != nil {
+			return fmt.Errorf("failed to fetch cli account: %w", err)
+		}
+	}
+
+	c.status = channelClosed
+	c.closesAt = now
+	c.updatedAt = now
+
+	if remaining.Sign() == 0 {
+		return nil
+	}
+
+	cli.balance.Add(cli.balance, remaining)
+	cli.escrow.Sub(cli.escrow, remaining)
+
+	s.recordTransaction(transaction{
+		transactionID: transactionID.String(),
+		source:        s.cfg.EscrowAddress,
+		destination:   s.cfg.WalletAddress,
+		value:         remaining,
+		status:        transactionCompleted,
+		address:       c.client,
+		counterpart:   c.proxy,
+		kind:          transactionRefund,
+	})
+
+	s.postJournal(transactionID.String(),
+		debit(c.client, ledgerEscrow, remaining),
+		credit(c.client, ledgerBalance, remaining),
+	)
+
+	return nil
+}
+
+func (s *BankService) channel(address string, id uuid.UUID) (*channel, error) {
+	c, ok := s.channels[id]
+	if !ok || (c.client != address && c.proxy != address) {
+		return nil, bank.ErrChannelNotFound
+	}
+
+	return c, nil
+}
@@ -0,0 +1,26 @@
+package memory
+
+import "context"
+
+// ChainCursor returns the last block processed by the chain follower with the given name, if it ran before.
+func (s *BankService) ChainCursor(_ context.Context, name string) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	block, ok := s.cursors[name]
+
+	return block, ok, nil
+}
+
+// SetChainCursor moves the cursor of a chain follower forward. A follower that lags behind another one does
+// not move it back.
+func (s *BankService) SetChainCursor(_ context.Context, name string, block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.cursors[name]; !ok || block > current {
+		s.cursors[name] = block
+	}
+
+	return nil
+}
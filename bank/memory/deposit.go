@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// Deposit credits a client with a transfer to the bank's wallet, less the deposit fee.
+func (s *BankService) Deposit(ctx context.Context, address string, amount types.FIL, transactionHash string) (types.FIL, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	balance, err := s.deposit(address, amount, transactionHash)
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	return respond(ctx, s, balance)
+}
+
+func (s *BankService) deposit(address string, amount types.FIL, transactionHash string) (types.FIL, error) {
+	for _, t := range s.transactions {
+		if t.transactionID == transactionHash {
+			return types.FIL{}, bank.ErrTransactionExists
+		}
+	}
+
+	acc, ok := s.accounts[address]
+	if !ok {
+		acc = s.createAccount(address, client)
+	}
+
+	if err := s.usable(acc, bank.OperationDeposit); err != nil {
+		return types.FIL{}, err
+	}
+
+	if acc.kind == storageProvider {
+		return types.FIL{}, bank.ErrOperationNotAllowed
+	}
+
+	fee := s.cfg.Fees.Deposit.Charge(amount.Int)
+	credited := new(big.Int).Sub(amount.Int, fee)
+
+	acc.balance.Add(acc.balance, credited)
+
+	s.recordTransaction(transaction{
+		transactionID: transactionHash,
+		source:        address,
+		destination:   s.cfg.WalletAddress,
+		value:         amount.Int,
+		status:        transactionCompleted,
+		address:       address,
+		counterpart:   s.cfg.WalletAddress,
+		kind:          transactionDeposit,
+	})
+
+	s.postJournal(transactionHash,
+		debit(s.cfg.WalletAddress, ledgerWallet, amount.Int),
+		credit(address, ledgerBalance, credited),
+		credit(s.cfg.WalletAddress, ledgerRevenue, fee),
+	)
+
+	s.recordFee(transactionHash, address, transactionDeposit, fee)
+
+	return fil(acc.balance), nil
+}
+
+func (s *BankService) Balance(_ context.Context, address string) (types.FIL, types.FIL, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return types.FIL{}, types.FIL{}, err
+	}
+
+	return fil(acc.balance), fil(acc.escrow), nil
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+)
+
+// Deregister stops new authorizations and channels against a storage provider. Open channels start
+// closing, and once nothing outstanding can be redeemed the remaining balance is paid to the destination.
+func (s *BankService) Deregister(ctx context.Context, address string, destination string) error {
+	if destination == s.cfg.WalletAddress {
+		return bank.ErrOperationNotAllowed
+	}
+
+	window, err := time.ParseDuration(s.cfg.ChannelSettleWindow)
+	if err != nil {
+		return fmt.Errorf("failed to parse channel settle window from config: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return fmt.Errorf("failed to fetch sp account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationDeregister); err != nil {
+		return err
+	}
+
+	if acc.kind != storageProvider {
+		return bank.ErrOperationNotAllowed
+	}
+
+	sp, err := s.activeProvider(address)
+	if err != nil {
+		return bank.ErrProxyNotActive
+	}
+
+	now := time.Now().UTC()
+
+	sp.status = providerDeregistering
+	sp.payoutAddress = destination
+	sp.updatedAt = now
+
+	for _, c := range s.channels {
+		if c.proxy == address && c.status == channelOpen {
+			c.status = channelClosing
+			c.closesAt = now.Add(window)
+			c.updatedAt = now
+		}
+	}
+
+	_, err = respond[any](ctx, s, nil)
+
+	return err
+}
+
+// outstanding reports whether a storage provider may still be paid, by redeeming an authorization that has
+// not expired or settling a channel that is still settleable.
+func (s *BankService) outstanding(address string, now time.Time) bool {
+	for _, auth := range s.authorizations {
+		if auth.proxy == address && auth.pending() && auth.expiresAt.After(now) {
+			return true
+		}
+	}
+
+	for _, c := range s.channels {
+		if c.proxy == address && c.settleable(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FinalizeDeregistrations pays out the balance of up to limit deregistering storage providers that can no
+// longer be paid, and marks them inactive. It returns how many it finalized.
+func (s *BankService) FinalizeDeregistrations(_ context.Context, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	var settled []string
+	for address, sp := range s.providers {
+		if sp.status == providerDeregistering && !s.outstanding(address, now) {
+			settled = append(settled, address)
+		}
+	}
+
+	sort.Slice(settled, func(i, j int) bool {
+		return s.providers[settled[i]].updatedAt.Before(s.providers[settled[j]].updatedAt)
+	})
+
+	if len(settled) > limit {
+		settled = settled[:limit]
+	}
+
+	var finalized int
+	var errs []error
+	for _, address := range settled {
+		if err := s.finalizeDeregistration(address, now); err != nil {
+			errs = append(errs, fmt.Errorf("storage provider %s: %w", address, err))
+
+			continue
+		}
+
+		finalized++
+	}
+
+	return finalized, errors.Join(errs...)
+}
+
+func (s *BankService) finalizeDeregistration(address string, now time.Time) error {
+	sp := s.providers[address]
+
+	acc, err := s.account(address)
+	if err != nil {
+		return err
+	}
+
+	if acc.balance.Sign() == 1 {
+		withdrawalID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		payout := new(big.Int).Set(acc.balance)
+		acc.balance.SetInt64(0)
+
+		// A balance that does not cover the withdrawal fee cannot be paid out, and is all kept as the fee.
+		fee := s.cfg.Fees.Withdraw.Charge(payout)
+		if fee.Cmp(payout) == -1 {
+			s.registerWithdrawal(withdrawalID, address, sp.payoutAddress, payout, fee)
+		} else {
+			s.postJournal(withdrawalID.String(),
+				debit(address, ledgerBalance, payout),
+				credit(s.cfg.WalletAddress, ledgerRevenue, payout),
+			)
+			s.recordFee(withdrawalID.String(), address, transactionWithdraw, payout)
+		}
+	}
+
+	sp.status = providerInactive
+	sp.deregisteredAt = now
+	sp.updatedAt = now
+
+	return nil
+}
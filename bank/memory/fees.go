@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type fee struct {
+	id            int64
+	transactionID string
+	address       string
+	kind          transactionType
+	value         *big.Int
+	refunded      bool
+	createdAt     time.Time
+}
+
+// recordFee keeps the fee an account paid on a transaction. The fee itself is posted to the bank's
+// revenue book by the journal of the transaction.
+func (s *BankService) recordFee(transactionID string, address string, kind transactionType, value *big.Int) {
+	if value.Sign() == 0 {
+		return
+	}
+
+	s.lastFeeID++
+
+	s.fees = append(s.fees, &fee{
+		id:            s.lastFeeID,
+		transactionID: transactionID,
+		address:       address,
+		kind:          kind,
+		value:         new(big.Int).Set(value),
+		createdAt:     time.Now().UTC(),
+	})
+}
+
+// Fees reports the fees the bank collected, newest first, along with their totals over the whole period.
+// Fees of reversed withdrawals were given back and are left out. Only the bank's wallet may see them.
+func (s *BankService) Fees(_ context.Context, address string, params bank.FeesParams) (bank.FeesReport, error) {
+	if address != s.cfg.WalletAddress {
+		return bank.FeesReport{}, bank.ErrOperationNotAllowed
+	}
+
+	var kind transactionType
+	if params.Type != "" {
+		t, ok := parseTransactionType(params.Type)
+		if !ok {
+			return bank.FeesReport{}, fmt.Errorf("unknown transaction type: %s", params.Type)
+		}
+
+		kind = t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := bank.FeesReport{
+		Total:  types.NewFIL(new(big.Int)),
+		ByType: make(map[string]types.FIL),
+	}
+
+	var entries []*fee
+	for _, f := range s.fees {
+		switch {
+		case f.refunded:
+		case !params.From.IsZero() && f.createdAt.Before(params.From):
+		case !params.To.IsZero() && !f.createdAt.Before(params.To):
+		case kind != "" && f.kind != kind:
+		default:
+			total, ok := report.ByType[string(f.kind)]
+			if !ok {
+				total = types.NewFIL(new(big.Int))
+			}
+
+			total.Int.Add(total.Int, f.value)
+			report.ByType[string(f.kind)] = total
+			report.Total.Int.Add(report.Total.Int, f.value)
+
+			if params.Cursor == 0 || f.id < params.Cursor {
+				entries = append(entries, f)
+			}
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].id > entries[j].id
+	})
+
+	if len(entries) > params.Limit {
+		entries = entries[:params.Limit]
+	}
+
+	report.Fees = make([]bank.CollectedFee, 0, len(entries))
+	for _, f := range entries {
+		report.Fees = append(report.Fees, bank.CollectedFee{
+			ID:            f.id,
+			TransactionID: f.transactionID,
+			Type:          string(f.kind),
+			Address:       f.address,
+			Amount:        fil(f.value),
+			CreatedAt:     f.createdAt,
+		})
+	}
+
+	return report, nil
+}
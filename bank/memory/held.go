@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+type heldDepositStatus string
+
+const (
+	heldDepositHeld     heldDepositStatus = "Held"
+	heldDepositCredited heldDepositStatus = "Credited"
+	heldDepositReturned heldDepositStatus = "Returned"
+)
+
+type heldDeposit struct {
+	id         int64
+	hash       string
+	sender     string
+	address    string
+	value      *big.Int
+	reason     string
+	status     heldDepositStatus
+	creditedTo string
+	returnHash string
+	createdAt  time.Time
+	resolvedAt time.Time
+}
+
+func (h *heldDeposit) model() bank.HeldDeposit {
+	return bank.HeldDeposit{
+		ID:         h.id,
+		Hash:       h.hash,
+		Sender:     h.sender,
+		Address:    h.address,
+		Value:      fil(h.value),
+		Reason:     h.reason,
+		Status:     string(h.status),
+		CreditedTo: h.creditedTo,
+		ReturnHash: h.returnHash,
+		CreatedAt:  h.createdAt,
+		ResolvedAt: h.resolvedAt,
+	}
+}
+
+// chainEntries returns the entries a held deposit stands for on chain: the deposit itself, which takes the
+// place of the transaction it was credited with, and the transfer it was returned with.
+func (h *heldDeposit) chainEntries() []bank.ChainEntry {
+	address := h.address
+	if h.creditedTo != "" {
+		address = h.creditedTo
+	}
+
+	deposit := bank.ChainEntry{
+		Type:        bank.TransactionDeposit,
+		ID:          h.hash,
+		Hash:        h.hash,
+		Address:     address,
+		Counterpart: h.sender,
+		Value:       fil(h.value),
+		Status:      bank.TransactionStatus(h.status),
+		At:          h.createdAt,
+	}
+
+	if h.status != heldDepositReturned {
+		return []bank.ChainEntry{deposit}
+	}
+
+	return []bank.ChainEntry{deposit, {
+		Type:        bank.TransactionReturn,
+		ID:          h.hash,
+		Hash:        h.returnHash,
+		Address:     h.address,
+		Counterpart: h.sender,
+		Value:       fil(h.value),
+		Status:      bank.TransactionCompleted,
+		At:          h.resolvedAt,
+	}}
+}
+
+func (s *BankService) HoldDeposit(_ context.Context, deposit bank.HeldDeposit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := strings.ToLower(deposit.Hash)
+	if s.heldDeposit(hash) != nil {
+		return nil
+	}
+
+	s.lastHeldID++
+	s.heldDeposits = append(s.heldDeposits, &heldDeposit{
+		id:        s.lastHeldID,
+		hash:      hash,
+		sender:    deposit.Sender,
+		address:   deposit.Address,
+		value:     new(big.Int).Set(deposit.Value.Int),
+		reason:    deposit.Reason,
+		status:    heldDepositHeld,
+		createdAt: time.Now().UTC(),
+	})
+
+	return nil
+}
+
+func (s *BankService) HeldDeposits(_ context.Context, params bank.HeldDepositsParams) ([]bank.HeldDeposit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deposits := make([]bank.HeldDeposit, 0, params.Limit)
+	for i := len(s.heldDeposits) - 1; i >= 0 && len(deposits) < params.Limit; i-- {
+		h := s.heldDeposits[i]
+		if params.Cursor > 0 && h.id >= params.Cursor {
+			continue
+		}
+
+		if params.Status != "" && !strings.EqualFold(string(h.status), params.Status) {
+			continue
+		}
+
+		deposits = append(deposits, h.model())
+	}
+
+	return deposits, nil
+}
+
+func (s *BankService) CreditHeldDeposit(_ context.Context, operator string, hash string, address string, reason string) (bank.HeldDeposit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.held(hash)
+	if err != nil {
+		return bank.HeldDeposit{}, err
+	}
+
+	if _, err := s.deposit(address, fil(h.value), h.hash); err != nil {
+		return bank.HeldDeposit{}, err
+	}
+
+	h.status = heldDepositCredited
+	h.creditedTo = address
+	h.resolvedAt = time.Now().UTC()
+
+	s.audit(operator, bank.AuditCreditHeldDeposit, h.hash, reason)
+
+	return h.model(), nil
+}
+
+func (s *BankService) ReturnHeldDeposit(_ context.Context, operator string, hash string, returnHash string, reason string) (bank.HeldDeposit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.held(hash)
+	if err != nil {
+		return bank.HeldDeposit{}, err
+	}
+
+	h.status = heldDepositReturned
+	h.returnHash = strings.ToLower(returnHash)
+	h.resolvedAt = time.Now().UTC()
+
+	s.audit(operator, bank.AuditReturnHeldDeposit, h.hash, reason)
+
+	return h.model(), nil
+}
+
+func (s *BankService) heldDeposit(hash string) *heldDeposit {
+	hash = strings.ToLower(hash)
+	for _, h := range s.heldDeposits {
+		if h.hash == hash {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// held returns a deposit that is still held.
+func (s *BankService) held(hash string) (*heldDeposit, error) {
+	h := s.heldDeposit(hash)
+
+	switch {
+	case h == nil:
+		return nil, bank.ErrHeldDepositNotFound
+	case h.status != heldDepositHeld:
+		return nil, bank.ErrHeldDepositResolved
+	default:
+		return h, nil
+	}
+}
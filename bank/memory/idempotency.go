@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+type idempotencyKey struct {
+	hash      string
+	response  *bank.IdempotentResponse
+	expiresAt time.Time
+}
+
+// BeginIdempotentRequest claims the idempotency key of an account for a request. It returns the stored
+// response when the request was already processed, and nil when the caller is the one to process it.
+func (s *BankService) BeginIdempotentRequest(_ context.Context, address string, key string, hash string, expiresAt time.Time) (*bank.IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := requestKey{address: address, value: key}
+
+	stored, ok := s.idempotencyKeys[k]
+	switch {
+	case !ok:
+		s.idempotencyKeys[k] = &idempotencyKey{hash: hash, expiresAt: expiresAt.UTC()}
+
+		return nil, nil // nolint:nilnil
+	case stored.hash != hash:
+		return nil, bank.ErrIdempotencyKeyReused
+	case stored.response == nil:
+		return nil, bank.ErrIdempotencyKeyInProgress
+	}
+
+	response := *stored.response
+	response.Body = append([]byte(nil), stored.response.Body...)
+
+	return &response, nil
+}
+
+// CompleteIdempotentRequest stores the response of a request, to be replayed for its retries. It keeps a
+// response already stored by the service call of the request.
+func (s *BankService) CompleteIdempotentRequest(_ context.Context, address string, key string, response bank.IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.completeIdempotentRequest(address, key, response)
+
+	return nil
+}
+
+func (s *BankService) completeIdempotentRequest(address string, key string, response bank.IdempotentResponse) {
+	if stored, ok := s.idempotencyKeys[requestKey{address: address, value: key}]; ok && stored.response == nil {
+		response.Body = append([]byte(nil), response.Body...)
+		stored.response = &response
+	}
+}
+
+// ReleaseIdempotentRequest frees the idempotency key of a request that did not complete, so it can be retried.
+func (s *BankService) ReleaseIdempotentRequest(_ context.Context, address string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := requestKey{address: address, value: key}
+	if stored, ok := s.idempotencyKeys[k]; ok && stored.response == nil {
+		delete(s.idempotencyKeys, k)
+	}
+
+	return nil
+}
+
+func (s *BankService) PruneIdempotencyKeys(_ context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int
+	for k, stored := range s.idempotencyKeys {
+		if stored.expiresAt.Before(before) {
+			delete(s.idempotencyKeys, k)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
+
+// respond stores the response of the idempotent request in ctx, if it has one, while the service call whose
+// result it reports still holds the lock, and returns the result.
+func respond[T any](ctx context.Context, s *BankService, result T) (T, error) {
+	req, ok := ctx.Value(bank.CtxKeyIdempotentRequest).(*bank.IdempotentRequest)
+	if !ok || req.Respond == nil {
+		return result, nil
+	}
+
+	response, err := req.Respond(result)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to render idempotent response: %w", err)
+	}
+
+	s.completeIdempotentRequest(req.Address, req.Key, response)
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+type ledgerBook int8
+
+const (
+	ledgerWallet ledgerBook = iota + 1
+	ledgerBalance
+	ledgerEscrow
+	ledgerWithdrawal
+	ledgerRevenue
+)
+
+type ledgerEntry struct {
+	transactionID string
+	address       string
+	book          ledgerBook
+	debit         *big.Int
+	credit        *big.Int
+	createdAt     time.Time
+}
+
+type posting struct {
+	address string
+	book    ledgerBook
+	debit   *big.Int
+	credit  *big.Int
+}
+
+func debit(address string, book ledgerBook, amount *big.Int) posting {
+	return posting{address: address, book: book, debit: amount, credit: new(big.Int)}
+}
+
+func credit(address string, book ledgerBook, amount *big.Int) posting {
+	return posting{address: address, book: book, debit: new(big.Int), credit: amount}
+}
+
+// postJournal appends the ledger entries of a transaction, skipping the empty ones.
+func (s *BankService) postJournal(transactionID string, postings ...posting) {
+	now := time.Now().UTC()
+
+	for _, p := range postings {
+		if p.debit.Sign() == 0 && p.credit.Sign() == 0 {
+			continue
+		}
+
+		s.ledger = append(s.ledger, ledgerEntry{
+			transactionID: transactionID,
+			address:       p.address,
+			book:          p.book,
+			debit:         new(big.Int).Set(p.debit),
+			credit:        new(big.Int).Set(p.credit),
+			createdAt:     now,
+		})
+	}
+}
+
+func (s *BankService) Ledger(_ context.Context, address string, at time.Time) (bank.LedgerModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	balance, escrow := new(big.Int), new(big.Int)
+	for _, e := range s.ledger {
+		if e.address != address || e.createdAt.After(at) {
+			continue
+		}
+
+		switch e.book {
+		case ledgerBalance:
+			balance.Add(balance, e.credit).Sub(balance, e.debit)
+		case ledgerEscrow:
+			escrow.Add(escrow, e.credit).Sub(escrow, e.debit)
+		default:
+		}
+	}
+
+	return bank.LedgerModel{
+		Available: fil(balance),
+		Escrow:    fil(escrow),
+		At:        at.UTC(),
+	}, nil
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+// requestKey identifies a nonce or an idempotency key, both of which are scoped to the account that signed the request.
+type requestKey struct {
+	address string
+	value   string
+}
+
+func (s *BankService) RegisterNonce(_ context.Context, address string, nonce string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := requestKey{address: address, value: nonce}
+	if _, ok := s.nonces[key]; ok {
+		return bank.ErrNonceReused
+	}
+
+	s.nonces[key] = expiresAt.UTC()
+
+	return nil
+}
+
+func (s *BankService) PruneNonces(_ context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int
+	for key, expiry := range s.nonces {
+		if expiry.Before(before) {
+			delete(s.nonces, key)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	expiresAt time.Time
+}
+
+// RateLimiter keeps token buckets in memory, for a bank that runs as a single process. It is the memory
+// rate limit store, whatever the database driver.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*bucket)}
+}
+
+func (l *RateLimiter) TakeToken(_ context.Context, key string, limit bank.RateLimit) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	tokens, wait := limit.Take(b.tokens, now.Sub(b.updatedAt))
+
+	b.tokens = tokens
+	b.updatedAt = now
+	b.expiresAt = now.Add(limit.Full(tokens))
+
+	return wait, nil
+}
+
+func (l *RateLimiter) PruneBuckets(_ context.Context, before time.Time) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var pruned int
+	for key, b := range l.buckets {
+		if b.expiresAt.Before(before) {
+			delete(l.buckets, key)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
+
+func (s *BankService) TakeToken(ctx context.Context, key string, limit bank.RateLimit) (time.Duration, error) {
+	return s.limiter.TakeToken(ctx, key, limit)
+}
+
+func (s *BankService) PruneBuckets(ctx context.Context, before time.Time) (int, error) {
+	return s.limiter.PruneBuckets(ctx, before)
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+// ChainEntries lists the deposits, the held deposits and their returns, and the signed withdrawals with one
+// of the hashes, or recorded in the time range, oldest first.
+func (s *BankService) ChainEntries(_ context.Context, params bank.ChainEntriesParams) ([]bank.ChainEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hashes := make(map[string]bool, len(params.Hashes))
+	for _, hash := range params.Hashes {
+		hashes[strings.ToLower(hash)] = true
+	}
+
+	selected := func(hash string, at time.Time) bool {
+		return hashes[strings.ToLower(hash)] || (!at.Before(params.From) && !at.After(params.To))
+	}
+
+	var entries []bank.ChainEntry
+
+	for _, t := range s.transactions {
+		if t.kind != transactionDeposit || !selected(t.transactionID, t.createdAt) || s.heldDeposit(t.transactionID) != nil {
+			continue
+		}
+
+		entries = append(entries, bank.ChainEntry{
+			Type:        bank.TransactionDeposit,
+			ID:          t.transactionID,
+			Hash:        t.transactionID,
+			Address:     t.address,
+			Counterpart: t.source,
+			Value:       fil(t.value),
+			Status:      bank.TransactionStatus(t.status),
+			At:          t.createdAt,
+		})
+	}
+
+	for _, w := range s.withdrawals {
+		if w.hash == "" || !selected(w.hash, w.submittedAt) {
+			continue
+		}
+
+		entries = append(entries, bank.ChainEntry{
+			Type:        bank.TransactionWithdraw,
+			ID:          w.id.String(),
+			Hash:        w.hash,
+			Address:     w.address,
+			Counterpart: w.destination,
+			Value:       fil(w.value),
+			Status:      bank.TransactionStatus(w.status),
+			At:          w.submittedAt,
+		})
+	}
+
+	for _, h := range s.heldDeposits {
+		for _, e := range h.chainEntries() {
+			if selected(e.Hash, e.At) {
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+
+	return entries, nil
+}
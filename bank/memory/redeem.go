@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// Redeem pays a storage provider from a locked authorization. A single authorization is marked redeemed and its
+// excess returned to the client, while a multi authorization keeps the remaining amount and is unlocked for the
+// next retrieval, until it is used up. The storage provider is paid the amount less the redeem fee.
+func (s *BankService) Redeem(ctx context.Context, address string, id uuid.UUID, amount types.FIL) (bank.RedeemModel, error) {
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.RedeemModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	refundID, err := uuid.NewV7()
+	if err != nil {
+		return bank.RedeemModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return bank.RedeemModel{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationRedeem); err != nil {
+		return bank.RedeemModel{}, err
+	}
+
+	if acc.kind != storageProvider {
+		return bank.RedeemModel{}, bank.ErrOperationNotAllowed
+	}
+
+	now := time.Now().UTC()
+
+	auth, ok := s.authorizations[id]
+	switch {
+	case !ok, auth.proxy != address, auth.status != authorizationLocked, !auth.expiresAt.After(now):
+		return bank.RedeemModel{}, bank.ErrAuthNotFound
+	case auth.balance.Cmp(amount.Int) == -1, auth.price.Cmp(amount.Int) == -1:
+		return bank.RedeemModel{}, bank.ErrAuthNotFound
+	}
+
+	cli, err := s.account(auth.client)
+	if err != nil {
+		return bank.RedeemModel{}, fmt.Errorf("failed to fetch cli account: %w", err)
+	}
+
+	fee := s.cfg.Fees.Redeem.Charge(amount.Int)
+	paid := new(big.Int).Sub(amount.Int, fee)
+
+	acc.balance.Add(acc.balance, paid)
+
+	s.recordTransaction(transaction{
+		transactionID: transactionID.String(),
+		source:        s.cfg.EscrowAddress,
+		destination:   s.cfg.WalletAddress,
+		value:         amount.Int,
+		status:        transactionCompleted,
+		address:       cli.address,
+		counterpart:   address,
+		kind:          transactionRedeem,
+	})
+
+	s.postJournal(transactionID.String(),
+		debit(cli.address, ledgerEscrow, amount.Int),
+		credit(address, ledgerBalance, paid),
+		credit(s.cfg.WalletAddress, ledgerRevenue, fee),
+	)
+
+	s.recordFee(transactionID.String(), address, transactionRedeem, fee)
+
+	excess := new(big.Int)
+	remaining := new(big.Int)
+	released := new(big.Int).Set(auth.balance)
+
+	switch auth.mode {
+	case authorizationMulti:
+		remaining.Sub(auth.balance, amount.Int)
+		released.Set(amount.Int)
+
+		auth.status = authorizationOpen
+		if remaining.Sign() == 0 {
+			auth.status = authorizationRedeemed
+		}
+	default:
+		if auth.balance.Cmp(amount.Int) == 1 {
+			excess.Sub(auth.balance, amount.Int)
+
+			cli.balance.Add(cli.balance, excess)
+
+			s.recordTransaction(transaction{
+				transactionID: refundID.String(),
+				source:        s.cfg.EscrowAddress,
+				destination:   s.cfg.WalletAddress,
+				value:         excess,
+				status:        transactionCompleted,
+				address:       cli.address,
+				counterpart:   address,
+				kind:          transactionRefund,
+			})
+
+			s.postJournal(refundID.String(),
+				debit(cli.address, ledgerEscrow, excess),
+				credit(cli.address, ledgerBalance, excess),
+			)
+		}
+
+		auth.status = authorizationRedeemed
+	}
+
+	auth.balance.Set(remaining)
+	auth.redeemed.Add(auth.redeemed, amount.Int)
+	auth.updatedAt = now
+
+	cli.escrow.Sub(cli.escrow, released)
+	s.deleteEmptyClient(cli)
+
+	return respond(ctx, s, bank.RedeemModel{
+		Excess:    types.NewFIL(excess),
+		Remaining: fil(remaining),
+		Fee:       types.NewFIL(fee),
+		SP:        fil(acc.balance),
+		CLI:       fil(cli.balance),
+	})
+}
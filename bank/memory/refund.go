@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+func (s *BankService) Refund(_ context.Context, address string) (bank.RefundModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationRefund); err != nil {
+		return bank.RefundModel{}, err
+	}
+
+	return s.refundExpired(acc, time.Now().UTC())
+}
+
+// SweepEscrow refunds the expired escrow of up to limit accounts. An account that fails to be refunded
+// does not stop the others from being swept.
+func (s *BankService) SweepEscrow(_ context.Context, limit int) (bank.SweepReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	expired := make(map[string]bool)
+	for _, auth := range s.authorizations {
+		if auth.pending() && !auth.expiresAt.After(now) {
+			expired[auth.client] = true
+		}
+	}
+
+	addresses := make([]string, 0, len(expired))
+	for address := range expired {
+		addresses = append(addresses, address)
+	}
+
+	sort.Strings(addresses)
+
+	if len(addresses) > limit {
+		addresses = addresses[:limit]
+	}
+
+	report := bank.SweepReport{Swept: types.NewFIL(new(big.Int))}
+
+	var errs []error
+	for _, address := range addresses {
+		acc, err := s.account(address)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", address, err))
+
+			continue
+		}
+
+		refund, err := s.refundExpired(acc, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", address, err))
+
+			continue
+		}
+
+		report.Accounts++
+		report.Swept.Int.Add(report.Swept.Int, refund.Expired.Int)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// refundExpired moves the escrow of an account that expired by the given time back to its balance.
+func (s *BankService) refundExpired(acc *account, now time.Time) (bank.RefundModel, error) {
+	var expired []*authorization
+
+	sum := new(big.Int)
+	for _, auth := range s.authorizations {
+		if auth.client == acc.address && auth.pending() && !auth.expiresAt.After(now) {
+			expired = append(expired, auth)
+			sum.Add(sum, auth.balance)
+		}
+	}
+
+	if sum.Sign() == 0 {
+		return bank.RefundModel{}, bank.ErrNothingToRefund
+	}
+
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	for _, auth := range expired {
+		auth.status = authorizationRefunded
+		auth.updatedAt = now
+	}
+
+	acc.balance.Add(acc.balance, sum)
+	acc.escrow.Sub(acc.escrow, sum)
+
+	s.recordTransaction(transaction{
+		transactionID: transactionID.String(),
+		source:        s.cfg.EscrowAddress,
+		destination:   s.cfg.WalletAddress,
+		value:         sum,
+		status:        transactionCompleted,
+		address:       acc.address,
+		kind:          transactionRefund,
+	})
+
+	s.postJournal(transactionID.String(),
+		debit(acc.address, ledgerEscrow, sum),
+		credit(acc.address, ledgerBalance, sum),
+	)
+
+	return bank.RefundModel{
+		Expired:   types.NewFIL(sum),
+		Available: fil(acc.balance),
+		Escrow:    fil(acc.escrow),
+	}, nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type providerStatus int8
+
+const (
+	providerActive providerStatus = iota + 1
+	providerDeregistering
+	providerInactive
+)
+
+type provider struct {
+	spid           string
+	price          *big.Int
+	status         providerStatus
+	payoutAddress  string
+	deregisteredAt time.Time
+	updatedAt      time.Time
+}
+
+// RegisterProxy registers a storage provider, or updates the id and price of one already registered.
+// Registering again reactivates a storage provider once its deregistration is finalized, but not while its
+// channels are still closing.
+func (s *BankService) RegisterProxy(ctx context.Context, spid string, walletAddress string, price types.FIL) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, ok := s.accounts[walletAddress]
+	if !ok {
+		acc = s.createAccount(walletAddress, storageProvider)
+	}
+
+	// The wallet already has an account, of a client.
+	if acc.kind != storageProvider {
+		return bank.ErrOperationNotAllowed
+	}
+
+	if err := s.usable(acc, bank.OperationRegister); err != nil {
+		return err
+	}
+
+	if sp, ok := s.providers[walletAddress]; ok && sp.status == providerDeregistering {
+		return bank.ErrProxyNotActive
+	}
+
+	s.providers[walletAddress] = &provider{
+		spid:      spid,
+		price:     new(big.Int).Set(price.Int),
+		status:    providerActive,
+		updatedAt: time.Now().UTC(),
+	}
+
+	_, err := respond[any](ctx, s, nil)
+
+	return err
+}
+
+// activeProvider returns the storage provider of an address, if it takes new authorizations and channels.
+func (s *BankService) activeProvider(address string) (*provider, error) {
+	sp, ok := s.providers[address]
+	if !ok {
+		return nil, fmt.Errorf("failed to fetch storage provider: %w", errProviderNotFound)
+	}
+
+	if sp.status != providerActive {
+		return nil, bank.ErrProxyNotActive
+	}
+
+	return sp, nil
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+// Liabilities lists what the bank owes each wallet, ordered by address. Withdrawals count until they are
+// paid out, and held deposits until they are credited or returned, as their funds are still in the bank's
+// wallet. A held deposit is owed to its sender.
+func (s *BankService) Liabilities(_ context.Context) ([]bank.Liability, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	amounts := make(map[string]*big.Int)
+	owe := func(address string, amount *big.Int) {
+		if _, ok := amounts[address]; !ok {
+			amounts[address] = new(big.Int)
+		}
+
+		amounts[address].Add(amounts[address], amount)
+	}
+
+	for _, acc := range s.accounts {
+		owe(acc.address, acc.balance)
+		owe(acc.address, acc.escrow)
+	}
+
+	for _, w := range s.withdrawals {
+		if w.status == transactionPending || w.status == transactionSubmitted {
+			owe(w.address, w.value)
+		}
+	}
+
+	for _, h := range s.heldDeposits {
+		if h.status == heldDepositHeld {
+			owe(h.sender, h.value)
+		}
+	}
+
+	liabilities := make([]bank.Liability, 0, len(amounts))
+	for address, amount := range amounts {
+		liabilities = append(liabilities, bank.Liability{Address: address, Amount: fil(amount)})
+	}
+
+	sort.Slice(liabilities, func(i, j int) bool {
+		return liabilities[i].Address < liabilities[j].Address
+	})
+
+	return liabilities, nil
+}
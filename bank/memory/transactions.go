@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+type transactionType string
+
+const (
+	transactionDeposit   transactionType = "Deposit"
+	transactionWithdraw  transactionType = "Withdraw"
+	transactionAuthorize transactionType = "Authorize"
+	transactionRedeem    transactionType = "Redeem"
+	transactionRefund    transactionType = "Refund"
+)
+
+func parseTransactionType(name string) (transactionType, bool) {
+	for _, t := range []transactionType{transactionDeposit, transactionWithdraw, transactionAuthorize, transactionRedeem, transactionRefund} {
+		if strings.EqualFold(string(t), name) {
+			return t, true
+		}
+	}
+
+	return "", false
+}
+
+type transactionStatus string
+
+const (
+	transactionPending   transactionStatus = "Pending"
+	transactionCompleted transactionStatus = "Completed"
+	transactionSubmitted transactionStatus = "Submitted"
+	transactionReversed  transactionStatus = "Reversed"
+)
+
+type transaction struct {
+	id            int64
+	transactionID string
+	source        string
+	destination   string
+	value         *big.Int
+	status        transactionStatus
+	address       string
+	counterpart   string
+	kind          transactionType
+	createdAt     time.Time
+}
+
+func (s *BankService) recordTransaction(t transaction) {
+	s.lastTransactionID++
+
+	t.id = s.lastTransactionID
+	t.value = new(big.Int).Set(t.value)
+	t.createdAt = time.Now().UTC()
+	s.transactions = append(s.transactions, &t)
+}
+
+func (s *BankService) setTransactionStatus(transactionID string, status transactionStatus) {
+	for _, t := range s.transactions {
+		if t.transactionID == transactionID {
+			t.status = status
+		}
+	}
+}
+
+func (s *BankService) ValidateBlockchainTransaction(_ context.Context, hash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.transactions {
+		if t.transactionID == hash {
+			return false, bank.ErrTransactionExists
+		}
+	}
+
+	if s.heldDeposit(hash) != nil {
+		return false, bank.ErrTransactionExists
+	}
+
+	return true, nil
+}
+
+// Transactions lists the transactions of an account, newest first. The counterpart of a transaction
+// is the other account involved in it, as seen from the given address.
+func (s *BankService) Transactions(_ context.Context, address string, params bank.TransactionsParams) ([]bank.Transaction, error) {
+	var kind transactionType
+	if params.Type != "" {
+		t, ok := parseTransactionType(params.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction type: %s", params.Type)
+		}
+
+		kind = t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matches []*transaction
+	for _, t := range s.transactions {
+		switch {
+		case t.address != address && t.counterpart != address:
+		case !params.From.IsZero() && t.createdAt.Before(params.From):
+		case !params.To.IsZero() && !t.createdAt.Before(params.To):
+		case kind != "" && t.kind != kind:
+		case params.Cursor > 0 && t.id >= params.Cursor:
+		default:
+			matches = append(matches, t)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].id > matches[j].id
+	})
+
+	if len(matches) > params.Limit {
+		matches = matches[:params.Limit]
+	}
+
+	transactions := make([]bank.Transaction, 0, len(matches))
+	for _, t := range matches {
+		counterpart := t.address
+		if t.address == address {
+			counterpart = t.counterpart
+		}
+
+		transactions = append(transactions, bank.Transaction{
+			ID:            t.id,
+			TransactionID: t.transactionID,
+			Type:          string(t.kind),
+			Counterpart:   counterpart,
+			Amount:        fil(t.value),
+			Status:        string(t.status),
+			CreatedAt:     t.createdAt,
+		})
+	}
+
+	return transactions, nil
+}
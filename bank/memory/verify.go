@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// Verify locks an open authorization for a retrieval by its storage provider, and returns the price the
+// retrieval is charged at: the provider's price when the authorization was made.
+func (s *BankService) Verify(ctx context.Context, address string, id uuid.UUID) (types.FIL, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return types.FIL{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationVerify); err != nil {
+		return types.FIL{}, err
+	}
+
+	if acc.kind != storageProvider {
+		return types.FIL{}, bank.ErrOperationNotAllowed
+	}
+
+	now := time.Now().UTC()
+
+	auth, ok := s.authorizations[id]
+	if !ok || auth.proxy != address || auth.balance.Cmp(auth.price) == -1 || !auth.expiresAt.After(now) || !auth.pending() {
+		return types.FIL{}, bank.ErrAuthNotFound
+	}
+
+	if auth.status == authorizationLocked {
+		return types.FIL{}, bank.ErrAuthLocked
+	}
+
+	auth.status = authorizationLocked
+	auth.updatedAt = now
+
+	return respond(ctx, s, fil(auth.price))
+}
@@ -0,0 +1,279 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type withdrawal struct {
+	id          uuid.UUID
+	address     string
+	destination string
+	value       *big.Int
+	fee         *big.Int
+	status      transactionStatus
+	hash        string
+	raw         []byte
+	submittedAt time.Time
+	lockedUntil time.Time
+	createdAt   time.Time
+}
+
+func (w withdrawal) model() bank.Withdrawal {
+	return bank.Withdrawal{
+		ID:          w.id,
+		Address:     w.address,
+		Destination: w.destination,
+		Amount:      fil(w.value),
+		Fee:         fil(w.fee),
+		Status:      string(w.status),
+		Hash:        w.hash,
+		Raw:         w.raw,
+		SubmittedAt: w.submittedAt,
+		CreatedAt:   w.createdAt,
+	}
+}
+
+// Withdraw debits an amount from the balance of an account, of which the withdrawal fee is kept and the
+// rest is paid out to the destination.
+func (s *BankService) Withdraw(ctx context.Context, address string, destination string, amount types.FIL) (bank.WithdrawModel, error) {
+	if destination == s.cfg.WalletAddress {
+		return bank.WithdrawModel{}, bank.ErrOperationNotAllowed
+	}
+
+	withdrawalID, err := uuid.NewV7()
+	if err != nil {
+		return bank.WithdrawModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.account(address)
+	if err != nil {
+		return bank.WithdrawModel{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	if err := s.usable(acc, bank.OperationWithdraw); err != nil {
+		return bank.WithdrawModel{}, err
+	}
+
+	if amount.Cmp(acc.balance) == 1 {
+		return bank.WithdrawModel{}, bank.ErrInsufficientFunds
+	}
+
+	fee := s.cfg.Fees.Withdraw.Charge(amount.Int)
+	if fee.Sign() == 1 && fee.Cmp(amount.Int) == 0 {
+		return bank.WithdrawModel{}, bank.ErrAmountBelowFee
+	}
+
+	acc.balance.Sub(acc.balance, amount.Int)
+	s.deleteEmptyClient(acc)
+
+	s.registerWithdrawal(withdrawalID, address, destination, amount.Int, fee)
+
+	return respond(ctx, s, bank.WithdrawModel{ID: withdrawalID, Available: fil(acc.balance), Fee: fil(fee)})
+}
+
+// registerWithdrawal records a pending withdrawal of an amount already debited from the balance of an
+// account, for the withdrawal worker to pay out. The withdrawal fee is taken from the amount.
+func (s *BankService) registerWithdrawal(id uuid.UUID, address string, destination string, amount *big.Int, fee *big.Int) {
+	value := new(big.Int).Sub(amount, fee)
+
+	s.withdrawals[id] = &withdrawal{
+		id:          id,
+		address:     address,
+		destination: destination,
+		value:       value,
+		fee:         new(big.Int).Set(fee),
+		status:      transactionPending,
+		createdAt:   time.Now().UTC(),
+	}
+
+	s.recordTransaction(transaction{
+		transactionID: id.String(),
+		source:        s.cfg.WalletAddress,
+		destination:   destination,
+		value:         value,
+		status:        transactionPending,
+		address:       address,
+		counterpart:   destination,
+		kind:          transactionWithdraw,
+	})
+
+	s.postJournal(id.String(),
+		debit(address, ledgerBalance, amount),
+		credit(address, ledgerWithdrawal, value),
+		credit(s.cfg.WalletAddress, ledgerRevenue, fee),
+	)
+
+	s.recordFee(id.String(), address, transactionWithdraw, fee)
+}
+
+func (s *BankService) Withdrawal(_ context.Context, address string, id uuid.UUID) (bank.Withdrawal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.withdrawals[id]
+	if !ok || w.address != address {
+		return bank.Withdrawal{}, bank.ErrWithdrawalNotFound
+	}
+
+	return w.model(), nil
+}
+
+// ClaimWithdrawals leases a batch of unfinished withdrawals to the caller, skipping the ones
+// currently leased by other workers.
+func (s *BankService) ClaimWithdrawals(_ context.Context, limit int, lease time.Duration) ([]bank.Withdrawal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	var claimed []*withdrawal
+	for _, w := range s.withdrawals {
+		if (w.status == transactionPending || w.status == transactionSubmitted) && w.lockedUntil.Before(now) {
+			claimed = append(claimed, w)
+		}
+	}
+
+	sort.Slice(claimed, func(i, j int) bool {
+		return claimed[i].createdAt.Before(claimed[j].createdAt)
+	})
+
+	if len(claimed) > limit {
+		claimed = claimed[:limit]
+	}
+
+	models := make([]bank.Withdrawal, 0, len(claimed))
+	for _, w := range claimed {
+		w.lockedUntil = now.Add(lease)
+		models = append(models, w.model())
+	}
+
+	return models, nil
+}
+
+// withdrawalIn returns a withdrawal if it is in one of the given statuses.
+func (s *BankService) withdrawalIn(id uuid.UUID, statuses ...transactionStatus) (*withdrawal, error) {
+	w, ok := s.withdrawals[id]
+	if ok {
+		for _, status := range statuses {
+			if w.status == status {
+				return w, nil
+			}
+		}
+	}
+
+	return nil, bank.ErrWithdrawalNotFound
+}
+
+// SubmitWithdrawal stores the signed transaction of a pending withdrawal. It must be called
+// before the transaction is broadcast.
+func (s *BankService) SubmitWithdrawal(_ context.Context, id uuid.UUID, hash string, raw []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, err := s.withdrawalIn(id, transactionPending)
+	if err != nil {
+		return fmt.Errorf("failed to submit withdrawal: %w", err)
+	}
+
+	w.hash = hash
+	w.raw = append([]byte(nil), raw...)
+	w.status = transactionSubmitted
+	w.submittedAt = time.Now().UTC()
+
+	s.setTransactionStatus(id.String(), transactionSubmitted)
+
+	return nil
+}
+
+// ResetWithdrawal drops the signed transaction of a withdrawal that can no longer be mined,
+// so that it is signed again.
+func (s *BankService) ResetWithdrawal(_ context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, err := s.withdrawalIn(id, transactionSubmitted)
+	if err != nil {
+		return fmt.Errorf("failed to reset withdrawal: %w", err)
+	}
+
+	w.hash = ""
+	w.raw = nil
+	w.status = transactionPending
+	w.submittedAt = time.Time{}
+	w.lockedUntil = time.Time{}
+
+	s.setTransactionStatus(id.String(), transactionPending)
+
+	return nil
+}
+
+func (s *BankService) CompleteWithdrawal(_ context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, err := s.withdrawalIn(id, transactionSubmitted)
+	if err != nil {
+		return fmt.Errorf("failed to complete withdrawal: %w", err)
+	}
+
+	w.status = transactionCompleted
+	w.lockedUntil = time.Time{}
+
+	s.setTransactionStatus(id.String(), transactionCompleted)
+
+	s.postJournal(id.String(),
+		debit(w.address, ledgerWithdrawal, w.value),
+		credit(s.cfg.WalletAddress, ledgerWallet, w.value),
+	)
+
+	return nil
+}
+
+// ReverseWithdrawal gives the funds of a withdrawal that was never paid out back to the client, along with its fee.
+func (s *BankService) ReverseWithdrawal(_ context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, err := s.withdrawalIn(id, transactionPending, transactionSubmitted)
+	if err != nil {
+		return fmt.Errorf("failed to reverse withdrawal: %w", err)
+	}
+
+	w.status = transactionReversed
+	w.lockedUntil = time.Time{}
+
+	acc, ok := s.accounts[w.address]
+	if !ok {
+		acc = s.createAccount(w.address, client)
+	}
+
+	refund := new(big.Int).Add(w.value, w.fee)
+	acc.balance.Add(acc.balance, refund)
+
+	for _, f := range s.fees {
+		if f.transactionID == id.String() {
+			f.refunded = true
+		}
+	}
+
+	s.setTransactionStatus(id.String(), transactionReversed)
+
+	s.postJournal(id.String(),
+		debit(w.address, ledgerWithdrawal, w.value),
+		debit(s.cfg.WalletAddress, ledgerRevenue, w.fee),
+		credit(w.address, ledgerBalance, refund),
+	)
+
+	return nil
+}
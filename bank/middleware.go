@@ -1,35 +1,86 @@
 package bank
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/subvisual/fidl/crypto"
+	"github.com/subvisual/fidl/http/jsend"
+	"github.com/subvisual/fidl/request"
+	"github.com/subvisual/fidl/types"
 )
 
 type ctxKey int
 
 const (
 	CtxKeyAddress ctxKey = iota
+	CtxKeyIdempotentRequest
 )
 
-func AuthenticationCtx() func(http.Handler) http.Handler {
+const (
+	maxSignedBodyBytes       = 1_048_576
+	maxIdempotencyKeyLength  = 255
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+func (s *Server) AuthenticationCtx() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
-			sig, addr, msg, err := ParseHeader(r)
+			header, err := ParseHeader(r)
 			if err != nil {
 				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 				return
 			}
 
-			if err := crypto.Verify(sig, *addr.Address, msg); err != nil {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodyBytes))
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			msg := request.CanonicalMessage(r.Method, request.Target(r.URL), body, header.Timestamp, header.Nonce, r.Header.Get(request.HeaderIdempotencyKey))
+			if err := crypto.Verify(header.Signature, *header.Address.Address, msg); err != nil {
 				http.Error(w, "failed to verify signature", http.StatusUnauthorized)
 				return
 			}
 
+			if s.blocked(header.Address) {
+				http.Error(w, "address is blocked", http.StatusForbidden)
+				return
+			}
+
+			signedAt := time.Unix(header.Timestamp, 0).UTC()
+			if now := time.Now().UTC(); signedAt.Before(now.Add(-s.RequestWindow)) || signedAt.After(now.Add(s.RequestWindow)) {
+				http.Error(w, "request timestamp outside of the allowed window", http.StatusUnauthorized)
+				return
+			}
+
+			err = s.BankService.RegisterNonce(r.Context(), header.Address.String(), header.Nonce, signedAt.Add(s.RequestWindow))
+			switch {
+			case errors.Is(err, ErrNonceReused):
+				http.Error(w, "request already processed", http.StatusUnauthorized)
+				return
+			case err != nil:
+				s.LogError(r, err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+				return
+			}
+
 			ctx := r.Context()
-			ctx = context.WithValue(ctx, CtxKeyAddress, addr)
+			ctx = context.WithValue(ctx, CtxKeyAddress, header.Address)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		}
 
@@ -37,6 +88,287 @@ func AuthenticationCtx() func(http.Handler) http.Handler {
 	}
 }
 
+// blocked reports whether an address is on the configured blocklist.
+func (s *Server) blocked(address types.Address) bool {
+	for _, blocked := range s.Blocklist {
+		if blocked.String() == address.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OperatorCtx lets through the authenticated requests of the configured operators only.
+// It must run after AuthenticationCtx.
+func (s *Server) OperatorCtx() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			for _, operator := range s.Operators {
+				if operator.String() == address.String() {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// IdempotencyCtx runs a request that carries an idempotency key at most once per account and key. The
+// response of the first request is stored and replayed to its retries, unless it failed with a server
+// error, in which case the request can be retried. A request that changes the bank stores its response in
+// the transaction of its service call, see respondWith, so that it never takes effect without its response
+// being stored; any other response is stored once the handler returns. It must run after AuthenticationCtx.
+func (s *Server) IdempotencyCtx() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(request.HeaderIdempotencyKey)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				s.JSON(w, r, http.StatusBadRequest, envelope{"message": "idempotency key is too long"})
+				return
+			}
+
+			address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+			if !ok {
+				s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				s.JSON(w, r, http.StatusBadRequest, envelope{"message": err.Error()})
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := requestHash(r, body)
+			expiresAt := time.Now().UTC().Add(s.IdempotencyTTL)
+
+			stored, err := s.BankService.BeginIdempotentRequest(r.Context(), address.String(), key, hash, expiresAt)
+			if err != nil {
+				s.JSON(w, r, http.StatusInternalServerError, err)
+				return
+			}
+
+			if stored != nil {
+				w.Header().Set(headerIdempotentReplayed, "true")
+				SetHeaders(w, stored.Status)
+
+				if _, err := w.Write(stored.Body); err != nil {
+					s.LogError(r, err)
+				}
+
+				return
+			}
+
+			// The outcome of the request is stored even when its client has gone away, so that a retry
+			// gets it instead of running the request again.
+			detached := context.WithoutCancel(r.Context())
+			idempotent := &IdempotentRequest{Address: address.String(), Key: key}
+
+			// Releasing the key keeps a response already stored by the service call of the request.
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+
+				if err := s.BankService.ReleaseIdempotentRequest(detached, address.String(), key); err != nil {
+					s.LogError(r, err)
+				}
+			}()
+
+			rec := &responseRecorder{header: http.Header{}}
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), CtxKeyIdempotentRequest, idempotent)))
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			if rec.status >= http.StatusInternalServerError {
+				s.send(w, r, rec)
+				return
+			}
+
+			response := IdempotentResponse{Status: rec.status, Body: rec.body.Bytes()}
+			if err := s.BankService.CompleteIdempotentRequest(detached, address.String(), key, response); err != nil {
+				s.LogError(r, err)
+			} else {
+				completed = true
+			}
+
+			s.send(w, r, rec)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// requestHash identifies a request by its method, target and body, to tell retries from other requests
+// sent with the same idempotency key.
+func requestHash(r *http.Request, body []byte) string {
+	digest := sha256.New()
+	digest.Write([]byte(r.Method + "\n" + request.Target(r.URL) + "\n"))
+	digest.Write(body)
+
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+// respondWith has the service call of an idempotent request store the response rendered from its result
+// with render, in the transaction of the call. The handler must then send render of the same result with
+// status, for the response sent to match the one stored. A call without a result, nil, renders a zero T.
+func respondWith[T any](s *Server, r *http.Request, status int, render func(T) envelope) {
+	idempotent, ok := r.Context().Value(CtxKeyIdempotentRequest).(*IdempotentRequest)
+	if !ok {
+		return
+	}
+
+	idempotent.Respond = func(result any) (IdempotentResponse, error) {
+		value, ok := result.(T)
+		if !ok && result != nil {
+			return IdempotentResponse{}, fmt.Errorf("unexpected idempotent result %T", result)
+		}
+
+		rec := &responseRecorder{header: http.Header{}}
+		s.JSON(rec, r, status, render(value))
+
+		return IdempotentResponse{Status: rec.status, Body: rec.body.Bytes()}, nil
+	}
+}
+
+// responseRecorder holds a response until it is sent.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	return rec.body.Write(b) // nolint:wrapcheck
+}
+
+// send writes a recorded response.
+func (s *Server) send(w http.ResponseWriter, r *http.Request, rec *responseRecorder) {
+	for name, values := range rec.header {
+		w.Header()[name] = values
+	}
+
+	w.WriteHeader(rec.status)
+
+	if _, err := w.Write(rec.body.Bytes()); err != nil {
+		s.LogError(r, err)
+	}
+}
+
+// IPRateLimitCtx limits how often each IP calls a route, with the limit configured for it. It runs before
+// AuthenticationCtx, so that the requests it refuses cost no signature check or database write.
+func (s *Server) IPRateLimitCtx(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			limits, ok := s.RateLimits[route]
+			if ok && limits.IP.Requests > 0 && !s.takeToken(w, r, "ip:"+route+":"+remoteIP(r), limits.IP) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// AddressRateLimitCtx limits how often each signing address calls a route, with the limit configured for it.
+// It must run after AuthenticationCtx.
+func (s *Server) AddressRateLimitCtx(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			limits, ok := s.RateLimits[route]
+			if !ok || limits.Address.Requests <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			address, ok := r.Context().Value(CtxKeyAddress).(types.Address)
+			if !ok {
+				s.JSON(w, r, http.StatusBadRequest, "failed to parse header address")
+				return
+			}
+
+			if !s.takeToken(w, r, "address:"+route+":"+address.String(), limits.Address) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// takeToken takes a token from the bucket of a key, and answers with a 429 when there is none.
+func (s *Server) takeToken(w http.ResponseWriter, r *http.Request, key string, limit RateLimit) bool {
+	wait, err := s.RateLimiter.TakeToken(r.Context(), key, limit)
+	if err != nil {
+		s.JSON(w, r, http.StatusInternalServerError, err)
+		return false
+	}
+
+	if wait <= 0 {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(wait.Seconds()))
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	SetHeaders(w, http.StatusTooManyRequests)
+
+	payload := jsend.Fail(envelope{"bank": "rate limit exceeded", "retry_after": retryAfter})
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		s.LogError(r, err)
+	}
+
+	return false
+}
+
+// remoteIP is the IP of the client, as set by the RealIP middleware when the bank is behind a trusted proxy.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
 func ReadTimeoutCtx(timeout time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,55 @@
+package bank
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Operation is something an account does with its funds. Closed accounts cannot perform any, and frozen
+// accounts only those listed in [accounts] frozen-allowed.
+type Operation string
+
+const (
+	OperationDeposit       Operation = "deposit"
+	OperationWithdraw      Operation = "withdraw"
+	OperationAuthorize     Operation = "authorize"
+	OperationCancel        Operation = "cancel"
+	OperationRefund        Operation = "refund"
+	OperationVerify        Operation = "verify"
+	OperationRedeem        Operation = "redeem"
+	OperationRegister      Operation = "register"
+	OperationDeregister    Operation = "deregister"
+	OperationOpenChannel   Operation = "open-channel"
+	OperationSettleChannel Operation = "settle-channel"
+	OperationCloseChannel  Operation = "close-channel"
+)
+
+var operations = []Operation{
+	OperationDeposit,
+	OperationWithdraw,
+	OperationAuthorize,
+	OperationCancel,
+	OperationRefund,
+	OperationVerify,
+	OperationRedeem,
+	OperationRegister,
+	OperationDeregister,
+	OperationOpenChannel,
+	OperationSettleChannel,
+	OperationCloseChannel,
+}
+
+func ParseOperations(values []string) ([]Operation, error) {
+	parsed := make([]Operation, 0, len(values))
+
+	for _, value := range values {
+		op := Operation(value)
+		if !slices.Contains(operations, op) {
+			return nil, fmt.Errorf("unknown operation %q", value)
+		}
+
+		parsed = append(parsed, op)
+	}
+
+	return parsed, nil
+}
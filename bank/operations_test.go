@@ -0,0 +1,23 @@
+package bank
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseOperations(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := ParseOperations([]string{"cancel", "close-channel"})
+	if err != nil {
+		t.Fatalf("failed to parse operations: %v", err)
+	}
+
+	if expected := []Operation{OperationCancel, OperationCloseChannel}; !slices.Equal(parsed, expected) {
+		t.Errorf("expected %v, got %v", expected, parsed)
+	}
+
+	if _, err := ParseOperations([]string{"cancel", "transfer"}); err == nil {
+		t.Errorf("expected an unknown operation to be rejected")
+	}
+}
@@ -12,6 +12,9 @@ type AuthorizationStatus int8
 const (
 	AuthorizationOpen AuthorizationStatus = iota + 1
 	AuthorizationLocked
+	AuthorizationRedeemed
+	AuthorizationRefunded
+	AuthorizationCancelled
 )
 
 func (a AuthorizationStatus) String() string {
@@ -20,6 +23,30 @@ func (a AuthorizationStatus) String() string {
 		return "Open"
 	case AuthorizationLocked:
 		return "Locked"
+	case AuthorizationRedeemed:
+		return "Redeemed"
+	case AuthorizationRefunded:
+		return "Refunded"
+	case AuthorizationCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type AuthorizationMode int8
+
+const (
+	AuthorizationSingle AuthorizationMode = iota + 1
+	AuthorizationMulti
+)
+
+func (a AuthorizationMode) String() string {
+	switch a {
+	case AuthorizationSingle:
+		return "Single"
+	case AuthorizationMulti:
+		return "Multi"
 	default:
 		return "Unknown" // nolint:goconst
 	}
@@ -31,6 +58,11 @@ type Authorization struct {
 	Balance   types.FIL           `db:"balance"`
 	Proxy     string              `db:"proxy"`
 	Status    AuthorizationStatus `db:"status_id"`
+	MaxPrice  *types.FIL          `db:"max_price"`
+	Mode      AuthorizationMode   `db:"mode_id"`
+	Amount    types.FIL           `db:"amount"`
+	ExpiresAt time.Time           `db:"expires_at"`
+	Price     types.FIL           `db:"price"`
 	CreatedAt time.Time           `db:"created_at"`
 	UpdatedAt time.Time           `db:"updated_at"`
 }
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// authorizationHistoryQuery lists the authorizations of a client or storage provider. Open and locked
+// authorizations past their expiry are reported as expired until they are refunded, and only those still
+// hold escrow.
+const authorizationHistoryQuery = `
+	SELECT *
+	FROM (
+		SELECT e.uuid, a.wallet_address AS client, e.proxy, e.mode_id, e.amount, e.price, e.max_price,
+			e.expires_at, e.created_at, e.updated_at,
+			CASE WHEN e.status_id IN (1, 2) AND e.expires_at <= $2 THEN 'Expired' ELSE s.name END AS status,
+			CASE WHEN e.status_id IN (1, 2) THEN e.balance ELSE 0 END AS remaining,
+			(SELECT COALESCE(SUM(r.value), 0) FROM redemptions r WHERE r.authorization_uuid = e.uuid) AS redeemed
+		FROM escrow e
+		JOIN accounts a ON a.id = e.id
+		JOIN authorization_status s ON s.id = e.status_id
+		WHERE (a.wallet_address = $1 OR e.proxy = $1)
+	) h
+	`
+
+type AuthorizationEntry struct {
+	UUID      uuid.UUID         `db:"uuid"`
+	Client    string            `db:"client"`
+	Proxy     string            `db:"proxy"`
+	Mode      AuthorizationMode `db:"mode_id"`
+	Status    string            `db:"status"`
+	Amount    types.FIL         `db:"amount"`
+	Remaining types.FIL         `db:"remaining"`
+	Redeemed  types.FIL         `db:"redeemed"`
+	Price     types.FIL         `db:"price"`
+	MaxPrice  *types.FIL        `db:"max_price"`
+	ExpiresAt time.Time         `db:"expires_at"`
+	CreatedAt time.Time         `db:"created_at"`
+	UpdatedAt time.Time         `db:"updated_at"`
+}
+
+func (e AuthorizationEntry) Model() bank.Authorization {
+	return bank.Authorization{
+		UUID:      e.UUID,
+		Client:    e.Client,
+		Proxy:     e.Proxy,
+		Mode:      e.Mode.String(),
+		Status:    e.Status,
+		Amount:    e.Amount,
+		Remaining: e.Remaining,
+		Redeemed:  e.Redeemed,
+		Price:     e.Price,
+		MaxPrice:  e.MaxPrice,
+		ExpiresAt: e.ExpiresAt,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+// Authorizations lists the authorizations the given address is the client or storage provider of, newest first.
+func (s BankService) Authorizations(ctx context.Context, address string, params bank.AuthorizationsParams) ([]bank.Authorization, error) {
+	var entries []AuthorizationEntry
+
+	query := authorizationHistoryQuery +
+		`
+		WHERE ($3::text = '' OR lower(h.status) = $3)
+		  AND ($4::uuid IS NULL OR h.uuid < $4)
+		ORDER BY h.uuid DESC
+		LIMIT $5
+		`
+
+	var cursor uuid.NullUUID
+	if params.Cursor != uuid.Nil {
+		cursor = uuid.NullUUID{UUID: params.Cursor, Valid: true}
+	}
+
+	args := []any{address, time.Now().UTC(), params.Status, cursor, params.Limit}
+	if err := s.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch authorizations: %w", err)
+	}
+
+	authorizations := make([]bank.Authorization, 0, len(entries))
+	for _, e := range entries {
+		authorizations = append(authorizations, e.Model())
+	}
+
+	return authorizations, nil
+}
+
+// Authorization returns an authorization of which the given address is the client or storage provider.
+func (s BankService) Authorization(ctx context.Context, address string, id uuid.UUID) (bank.Authorization, error) {
+	var entry AuthorizationEntry
+
+	query := authorizationHistoryQuery +
+		`
+		WHERE h.uuid = $3
+		`
+
+	args := []any{address, time.Now().UTC(), id}
+	if err := s.db.GetContext(ctx, &entry, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return bank.Authorization{}, bank.ErrAuthNotFound
+		}
+
+		return bank.Authorization{}, fmt.Errorf("failed to fetch authorization: %w", err)
+	}
+
+	return entry.Model(), nil
+}
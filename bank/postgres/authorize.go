@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/subvisual/fidl"
@@ -9,11 +11,18 @@ import (
 	"github.com/subvisual/fidl/types"
 )
 
-func (s BankService) Authorize(address string, proxy string) (bank.AuthModel, error) {
+// Authorize escrows an amount for a storage provider, defaulting to its price. The amount must cover at
+// least one retrieval at the provider's price, and the price must not be above the client's maximum.
+// A multi authorization can be redeemed several times, until its amount is used up or it expires.
+// The expiry defaults to the escrow deadline, and a client asking for its own must stay within the bank's bounds.
+// The provider's price is kept with the authorization, so that later price changes do not apply to it.
+func (s BankService) Authorize(ctx context.Context, address string, params bank.AuthorizeParams) (bank.AuthModel, error) {
 	var balance types.FIL
 	var escrow types.FIL
 	var cost types.FIL
 	var id uuid.UUID
+	var expiresAt time.Time
+	var model bank.AuthModel
 
 	withdrawQuery :=
 		`
@@ -26,42 +35,72 @@ func (s BankService) Authorize(address string, proxy string) (bank.AuthModel, er
   			RETURNING balance
 		`
 
-	spCostQuery :=
-		`
-		SELECT price FROM storage_providers
-		WHERE id = $1
-		`
-
 	escrowQuery :=
 		`
-		INSERT INTO escrow (id, uuid, balance, proxy, status_id)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING uuid, balance
+		INSERT INTO escrow (id, uuid, balance, amount, proxy, status_id, max_price, mode_id, expires_at, price)
+		VALUES ($1, $2, $3, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING uuid, balance, expires_at
 		`
 
 	// nolint:goconst
 	transactionQuery :=
 		`
-		INSERT INTO transactions (transaction_id, source, destination, value, status_id)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
 		`
 
-	err := Transaction(s.db, func(tx fidl.Queryable) error {
-		account, err := getAccountByAddress(address, tx)
+	proxy, amount, maxPrice := params.Proxy, params.Amount, params.MaxPrice
+
+	mode := AuthorizationSingle
+	if params.Mode == "multi" {
+		mode = AuthorizationMulti
+	}
+
+	expiry, err := s.authorizationExpiry(params.ExpiresAt)
+	if err != nil {
+		return bank.AuthModel{}, err
+	}
+
+	err = Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
 		if err != nil {
 			return fmt.Errorf("failed to fetch cli account: %w", err)
 		}
 
-		spAccount, err := getAccountByAddress(proxy, tx)
+		if err := s.usable(account, bank.OperationAuthorize); err != nil {
+			return err
+		}
+
+		spAccount, err := getAccountByAddress(ctx, proxy, tx)
 		if err != nil {
 			return fmt.Errorf("failed to fetch sp account: %w", err)
 		}
 
-		if err := tx.QueryRow(spCostQuery, spAccount.ID).Scan(&cost); err != nil {
-			return fmt.Errorf("failed to fetch sp price: %w", err)
+		sp, err := getProvider(ctx, spAccount.ID, tx)
+		if err != nil {
+			return err
+		}
+
+		if sp.Status != StorageProviderActive {
+			return bank.ErrProxyNotActive
 		}
 
-		balance, _, err = s.Balance(address)
+		price := sp.Price
+
+		if maxPrice != nil && price.Cmp(maxPrice.Int) == 1 {
+			return bank.ErrPriceAboveMax
+		}
+
+		cost = price
+		if amount != nil {
+			if amount.Cmp(price.Int) == -1 {
+				return bank.ErrAmountBelowPrice
+			}
+
+			cost = *amount
+		}
+
+		balance, _, err = lockBalances(ctx, tx, account.ID)
 		if err != nil {
 			return err
 		}
@@ -71,7 +110,7 @@ func (s BankService) Authorize(address string, proxy string) (bank.AuthModel, er
 		}
 
 		args := []any{account.ID, cost.Int.String()}
-		if err := tx.QueryRow(withdrawQuery, args...).Scan(&balance); err != nil {
+		if err := tx.QueryRowContext(ctx, withdrawQuery, args...).Scan(&balance); err != nil {
 			return fmt.Errorf("failed to execute withdraw balance: %w", err)
 		}
 
@@ -85,25 +124,63 @@ func (s BankService) Authorize(address string, proxy string) (bank.AuthModel, er
 			return fmt.Errorf("failed to generate v7 uuid: %w", err)
 		}
 
-		args = []any{account.ID, uuid, cost.Int.String(), proxy, AuthorizationOpen}
-		if err := tx.QueryRow(escrowQuery, args...).Scan(&id, &escrow); err != nil {
+		var maxPriceArg any
+		if maxPrice != nil {
+			maxPriceArg = maxPrice.Int.String()
+		}
+
+		args = []any{account.ID, uuid, cost.Int.String(), proxy, AuthorizationOpen, maxPriceArg, mode, expiry, price.Int.String()}
+		if err := tx.QueryRowContext(ctx, escrowQuery, args...).Scan(&id, &escrow, &expiresAt); err != nil {
 			return fmt.Errorf("failed to deposit to escrow: %w", err)
 		}
 
-		args = []any{transactionID.String(), s.cfg.WalletAddress, s.cfg.EscrowAddress, cost.Int.String(), TransactionCompleted}
-		if _, err := tx.Exec(transactionQuery, args...); err != nil {
+		args = []any{transactionID.String(), s.cfg.WalletAddress, s.cfg.EscrowAddress, cost.Int.String(), TransactionCompleted, address, proxy, TransactionAuthorize}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
 			return fmt.Errorf("failed to register transaction during authorize: %w", err)
 		}
 
-		return nil
+		err = postJournal(ctx, tx, transactionID.String(),
+			debit(address, LedgerBalance, cost.Int),
+			credit(address, LedgerEscrow, cost.Int),
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, address); err != nil {
+			return err
+		}
+
+		model = bank.AuthModel{
+			UUID:      id,
+			Mode:      mode.String(),
+			Available: balance,
+			Escrow:    escrow,
+			ExpiresAt: expiresAt,
+		}
+
+		return respond(ctx, tx, model)
 	})
 	if err != nil {
 		return bank.AuthModel{}, err
 	}
 
-	return bank.AuthModel{
-		UUID:      id,
-		Available: balance,
-		Escrow:    escrow,
-	}, nil
+	return model, nil
+}
+
+// authorizationExpiry returns when a new authorization expires, checking a requested expiry against the
+// escrow deadline bounds.
+func (s BankService) authorizationExpiry(requested *time.Time) (time.Time, error) {
+	now := time.Now().UTC()
+
+	if requested == nil {
+		return now.Add(s.cfg.EscrowDeadline), nil
+	}
+
+	lifetime := requested.Sub(now)
+	if lifetime < s.cfg.EscrowMinDeadline || lifetime > s.cfg.EscrowMaxDeadline {
+		return time.Time{}, bank.ErrExpiryOutOfBounds
+	}
+
+	return requested.UTC(), nil
 }
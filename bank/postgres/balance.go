@@ -1,13 +1,14 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/subvisual/fidl"
 	"github.com/subvisual/fidl/types"
 )
 
-func (s BankService) Balance(address string) (types.FIL, types.FIL, error) {
+func (s BankService) Balance(ctx context.Context, address string) (types.FIL, types.FIL, error) {
 	var balance types.FIL
 	var escrow types.FIL
 
@@ -16,14 +17,14 @@ func (s BankService) Balance(address string) (types.FIL, types.FIL, error) {
 		SELECT balance, escrow FROM balances WHERE id = $1
 		`
 
-	err := Transaction(s.db, func(tx fidl.Queryable) error {
-		account, err := getAccountByAddress(address, tx)
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
 		if err != nil {
 			return fmt.Errorf("failed to fetch account: %w", err)
 		}
 
 		args := []any{account.ID}
-		if err := tx.QueryRow(query, args...).Scan(&balance, &escrow); err != nil {
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&balance, &escrow); err != nil {
 			return fmt.Errorf("failed to get balances: %w", err)
 		}
 
@@ -35,3 +36,21 @@ func (s BankService) Balance(address string) (types.FIL, types.FIL, error) {
 
 	return balance, escrow, nil
 }
+
+// lockBalances reads the balances of an account and locks them until the transaction ends, so that
+// checks made against them still hold when they are updated.
+func lockBalances(ctx context.Context, tx fidl.Queryable, id int64) (types.FIL, types.FIL, error) {
+	var balance types.FIL
+	var escrow types.FIL
+
+	query :=
+		`
+		SELECT balance, escrow FROM balances WHERE id = $1 FOR UPDATE
+		`
+
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&balance, &escrow); err != nil {
+		return types.FIL{}, types.FIL{}, fmt.Errorf("failed to lock balances: %w", err)
+	}
+
+	return balance, escrow, nil
+}
@@ -1,15 +1,24 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
 )
 
 type BankConfig struct {
-	WalletAddress  string
-	EscrowAddress  string
-	EscrowDeadline string
+	WalletAddress       string
+	EscrowAddress       string
+	EscrowDeadline      time.Duration
+	EscrowMinDeadline   time.Duration
+	EscrowMaxDeadline   time.Duration
+	ChannelSettleWindow string
+	ChannelLifetime     time.Duration
+	FrozenAllowed       []bank.Operation
+	Fees                bank.FeeSchedule
 }
 
 type BankService struct {
@@ -24,7 +33,7 @@ func NewBankService(db *DB, cfg *BankConfig) *BankService {
 	}
 }
 
-func getAccountByAddress(address string, tx fidl.Queryable) (*Account, error) {
+func getAccountByAddress(ctx context.Context, address string, tx fidl.Queryable) (*Account, error) {
 	query :=
 		`
 		SELECT *
@@ -33,9 +42,25 @@ func getAccountByAddress(address string, tx fidl.Queryable) (*Account, error) {
 		`
 
 	var account Account
-	if err := tx.Get(&account, query, address); err != nil {
+	if err := tx.GetContext(ctx, &account, query, address); err != nil {
 		return nil, fmt.Errorf("failed to fetch account by wallet address: %w", err)
 	}
 
 	return &account, nil
 }
+
+func getAccountByID(ctx context.Context, id int64, tx fidl.Queryable) (*Account, error) {
+	query :=
+		`
+		SELECT *
+		FROM accounts
+		WHERE id = $1
+		`
+
+	var account Account
+	if err := tx.GetContext(ctx, &account, query, id); err != nil {
+		return nil, fmt.Errorf("failed to fetch account by id: %w", err)
+	}
+
+	return &account, nil
+}
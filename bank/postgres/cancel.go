@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// CancelAuthorization releases an open authorization of the client back to its balance. An authorization
+// locked by a proxy's Verify is being redeemed, so it cannot be cancelled until the proxy is done with it.
+func (s BankService) CancelAuthorization(ctx context.Context, address string, id uuid.UUID) (bank.CancelModel, error) {
+	var auth Authorization
+	var balance types.FIL
+	var escrow types.FIL
+
+	cancelAuthQuery :=
+		`
+		UPDATE escrow
+			SET status_id = $4,
+				updated_at = now() at time zone 'utc'
+			WHERE uuid = $1
+			  AND id = $2
+			  AND status_id = $3
+			RETURNING *
+		`
+
+	authStatusQuery :=
+		`
+		SELECT status_id
+		FROM escrow
+		WHERE uuid = $1
+		  AND id = $2
+		`
+
+	updateBalancesQuery :=
+		`
+		UPDATE balances
+  			SET balance = balance + $2,
+				escrow = escrow - $2,
+				updated_at = now() at time zone 'utc'
+  			WHERE id = $1
+  			AND escrow >= $2
+  			RETURNING balance, escrow
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationCancel); err != nil {
+			return err
+		}
+
+		args := []any{id, account.ID, AuthorizationOpen, AuthorizationCancelled}
+		if err := tx.GetContext(ctx, &auth, cancelAuthQuery, args...); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("failed to cancel authorization: %w", err)
+			}
+
+			var status AuthorizationStatus
+			err := tx.GetContext(ctx, &status, authStatusQuery, id, account.ID)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return bank.ErrAuthNotFound
+			case err != nil:
+				return fmt.Errorf("failed to fetch authorization: %w", err)
+			case status != AuthorizationLocked:
+				return bank.ErrAuthNotFound
+			}
+
+			return bank.ErrAuthNotCancellable
+		}
+
+		args = []any{account.ID, auth.Balance.Int.String()}
+		if err := tx.QueryRowContext(ctx, updateBalancesQuery, args...).Scan(&balance, &escrow); err != nil {
+			return fmt.Errorf("failed to update balances: %w", err)
+		}
+
+		transactionID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, auth.Balance.Int.String(), TransactionCompleted, address, auth.Proxy, TransactionRefund}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register transaction during cancel: %w", err)
+		}
+
+		err = postJournal(ctx, tx, transactionID.String(),
+			debit(address, LedgerEscrow, auth.Balance.Int),
+			credit(address, LedgerBalance, auth.Balance.Int),
+		)
+		if err != nil {
+			return err
+		}
+
+		return checkLedger(ctx, tx, address)
+	})
+	if err != nil {
+		return bank.CancelModel{}, err
+	}
+
+	return bank.CancelModel{
+		Released:  auth.Balance,
+		Available: balance,
+		Escrow:    escrow,
+	}, nil
+}
@@ -0,0 +1,585 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type ChannelStatus int8
+
+const (
+	ChannelOpen ChannelStatus = iota + 1
+	ChannelClosing
+	ChannelClosed
+)
+
+func (c ChannelStatus) String() string {
+	switch c {
+	case ChannelOpen:
+		return "Open"
+	case ChannelClosing:
+		return "Closing"
+	case ChannelClosed:
+		return "Closed"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type Channel struct {
+	UUID      uuid.UUID     `db:"uuid"`
+	Address   string        `db:"wallet_address"`
+	Proxy     string        `db:"proxy"`
+	Balance   types.FIL     `db:"balance"`
+	Redeemed  types.FIL     `db:"redeemed"`
+	Status    ChannelStatus `db:"status_id"`
+	ClosesAt  sql.NullTime  `db:"closes_at"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+func (c Channel) Model() bank.Channel {
+	return bank.Channel{
+		UUID:      c.UUID,
+		Client:    c.Address,
+		Proxy:     c.Proxy,
+		Balance:   c.Balance,
+		Redeemed:  c.Redeemed,
+		Status:    c.Status.String(),
+		ClosesAt:  c.ClosesAt.Time,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// settleable reports whether the proxy may still redeem vouchers of the channel.
+func (c Channel) settleable(now time.Time) bool {
+	switch c.Status {
+	case ChannelOpen:
+		return true
+	case ChannelClosing:
+		return now.Before(c.ClosesAt.Time)
+	default:
+		return false
+	}
+}
+
+// OpenChannel moves an amount of the client's balance to escrow, to be paid to a storage provider with vouchers.
+func (s BankService) OpenChannel(ctx context.Context, address string, proxy string, amount types.FIL) (bank.ChannelModel, error) {
+	var channel Channel
+	var balance types.FIL
+
+	escrowQuery :=
+		`
+		UPDATE balances
+  			SET balance = balance - $2,
+				escrow = escrow + $2,
+				updated_at = now() at time zone 'utc'
+  			WHERE id = $1
+  			AND balance >= $2
+  			RETURNING balance
+		`
+
+	channelQuery :=
+		`
+		INSERT INTO channels (uuid, wallet_address, proxy, balance, status_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch cli account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationOpenChannel); err != nil {
+			return err
+		}
+
+		if account.Type != Client {
+			return bank.ErrOperationNotAllowed
+		}
+
+		spAccount, err := getAccountByAddress(ctx, proxy, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sp account: %w", err)
+		}
+
+		if spAccount.Type != StorageProvider {
+			return bank.ErrOperationNotAllowed
+		}
+
+		sp, err := getProvider(ctx, spAccount.ID, tx)
+		if err != nil {
+			return err
+		}
+
+		if sp.Status != StorageProviderActive {
+			return bank.ErrProxyNotActive
+		}
+
+		args := []any{account.ID, amount.Int.String()}
+		if err := tx.QueryRowContext(ctx, escrowQuery, args...).Scan(&balance); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrInsufficientFunds
+			}
+
+			return fmt.Errorf("failed to escrow channel balance: %w", err)
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		args = []any{id, address, proxy, amount.Int.String(), ChannelOpen}
+		if err := tx.GetContext(ctx, &channel, channelQuery, args...); err != nil {
+			return fmt.Errorf("failed to open channel: %w", err)
+		}
+
+		args = []any{id.String(), s.cfg.WalletAddress, s.cfg.EscrowAddress, amount.Int.String(), TransactionCompleted, address, proxy, TransactionAuthorize}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register transaction during channel open: %w", err)
+		}
+
+		err = postJournal(ctx, tx, id.String(),
+			debit(address, LedgerBalance, amount.Int),
+			credit(address, LedgerEscrow, amount.Int),
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, address); err != nil {
+			return err
+		}
+
+		return respond(ctx, tx, bank.ChannelModel{Channel: channel.Model(), Available: balance})
+	})
+	if err != nil {
+		return bank.ChannelModel{}, err
+	}
+
+	return bank.ChannelModel{Channel: channel.Model(), Available: balance}, nil
+}
+
+// Channel returns a channel to its client or to its storage provider.
+func (s BankService) Channel(ctx context.Context, address string, id uuid.UUID) (bank.Channel, error) {
+	var channel Channel
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		var err error
+		channel, err = getChannel(ctx, tx, address, id, false)
+
+		return err
+	})
+	if err != nil {
+		return bank.Channel{}, err
+	}
+
+	return channel.Model(), nil
+}
+
+// SettleChannel pays the storage provider the difference between the amount of its latest voucher and what
+// it already redeemed from the channel. The voucher signature must be checked by the caller.
+func (s BankService) SettleChannel(ctx context.Context, address string, id uuid.UUID, amount types.FIL) (bank.Channel, error) {
+	var channel Channel
+
+	settleQuery :=
+		`
+		UPDATE channels
+			SET redeemed = $2,
+				updated_at = now() at time zone 'utc'
+			WHERE uuid = $1
+			RETURNING *
+		`
+
+	spDepositQuery :=
+		`
+		UPDATE balances SET
+			balance = balance + $2,
+			updated_at = now() at time zone 'utc'
+		WHERE id = $1
+		`
+
+	cliEscrowQuery :=
+		`
+		UPDATE balances
+  			SET escrow = escrow - $2,
+				updated_at = now() at time zone 'utc'
+  			WHERE id = $1
+  			AND escrow >= $2
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		`
+
+	redemptionQuery :=
+		`
+		INSERT INTO redemptions (authorization_uuid, transaction_id, proxy, value, remaining)
+		VALUES ($1, $2, $3, $4, $5)
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		var err error
+		channel, err = getChannel(ctx, tx, address, id, true)
+		if err != nil {
+			return err
+		}
+
+		if channel.Proxy != address {
+			return bank.ErrOperationNotAllowed
+		}
+
+		if !channel.settleable(time.Now().UTC()) {
+			return bank.ErrChannelClosed
+		}
+
+		if amount.Cmp(channel.Balance.Int) == 1 {
+			return bank.ErrInvalidVoucher
+		}
+
+		// An older voucher was already settled, there is nothing left to pay.
+		if amount.Cmp(channel.Redeemed.Int) <= 0 {
+			return respond(ctx, tx, channel.Model())
+		}
+
+		delta := new(big.Int).Sub(amount.Int, channel.Redeemed.Int)
+		remaining := new(big.Int).Sub(channel.Balance.Int, amount.Int)
+
+		spAccount, err := getAccountByAddress(ctx, channel.Proxy, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sp account: %w", err)
+		}
+
+		if err := s.usable(spAccount, bank.OperationSettleChannel); err != nil {
+			return err
+		}
+
+		client, err := getAccountByAddress(ctx, channel.Address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch cli account: %w", err)
+		}
+
+		if err := tx.GetContext(ctx, &channel, settleQuery, id, amount.Int.String()); err != nil {
+			return fmt.Errorf("failed to settle channel: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, spDepositQuery, spAccount.ID, delta.String()); err != nil {
+			return fmt.Errorf("failed to deposit balance to sp: %w", err)
+		}
+
+		if err := execOne(ctx, tx, cliEscrowQuery, client.ID, delta.String()); err != nil {
+			return fmt.Errorf("failed to update cli escrow: %w", err)
+		}
+
+		transactionID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		args := []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, delta.String(), TransactionCompleted, channel.Address, channel.Proxy, TransactionRedeem}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register transaction during channel settle: %w", err)
+		}
+
+		args = []any{id, transactionID.String(), channel.Proxy, delta.String(), remaining.String()}
+		if _, err := tx.ExecContext(ctx, redemptionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register redemption: %w", err)
+		}
+
+		err = postJournal(ctx, tx, transactionID.String(),
+			debit(channel.Address, LedgerEscrow, delta),
+			credit(channel.Proxy, LedgerBalance, delta),
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, channel.Proxy); err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, channel.Address); err != nil {
+			return err
+		}
+
+		return respond(ctx, tx, channel.Model())
+	})
+	if err != nil {
+		return bank.Channel{}, err
+	}
+
+	return channel.Model(), nil
+}
+
+// CloseChannel closes a channel and returns what was not redeemed to the client. The storage provider closes
+// it right away, after settling its latest voucher. The client starts a settle window instead, so the storage
+// provider can still redeem its vouchers, and closes it by calling again once the window is over.
+func (s BankService) CloseChannel(ctx context.Context, address string, id uuid.UUID) (bank.Channel, error) {
+	var channel Channel
+
+	window, err := time.ParseDuration(s.cfg.ChannelSettleWindow)
+	if err != nil {
+		return bank.Channel{}, fmt.Errorf("failed to parse channel settle window from config: %w", err)
+	}
+
+	err = Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		var err error
+		channel, err = getChannel(ctx, tx, address, id, true)
+		if err != nil {
+			return err
+		}
+
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationCloseChannel); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+
+		switch {
+		case channel.Status == ChannelClosed:
+			return bank.ErrChannelClosed
+		case address == channel.Proxy:
+			channel, err = s.closeChannel(ctx, tx, channel)
+		case channel.Status == ChannelOpen:
+			channel, err = startClosing(ctx, tx, id, now.Add(window))
+		case channel.settleable(now):
+		default:
+			channel, err = s.closeChannel(ctx, tx, channel)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return respond(ctx, tx, channel.Model())
+	})
+	if err != nil {
+		return bank.Channel{}, err
+	}
+
+	return channel.Model(), nil
+}
+
+// SweepChannels closes the channels that nobody closes. A channel open for longer than the channel lifetime
+// starts closing as if its client closed it, and a channel whose settle window is over is closed and what
+// was not redeemed returned to its client. It returns how many channels it moved along.
+func (s BankService) SweepChannels(ctx context.Context, limit int) (int, error) {
+	var channels []Channel
+
+	staleQuery :=
+		`
+		SELECT *
+		FROM channels
+		WHERE (status_id = $1 AND created_at <= $2)
+		   OR (status_id = $3 AND closes_at <= $4)
+		ORDER BY created_at
+		LIMIT $5
+		`
+
+	window, err := time.ParseDuration(s.cfg.ChannelSettleWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse channel settle window from config: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	args := []any{ChannelOpen, channelCutoff(now, s.cfg.ChannelLifetime), ChannelClosing, now, limit}
+	if err := s.db.SelectContext(ctx, &channels, staleQuery, args...); err != nil {
+		return 0, fmt.Errorf("failed to fetch stale channels: %w", err)
+	}
+
+	var swept int
+	var errs []error
+	for _, stale := range channels {
+		var moved bool
+
+		err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+			channel, err := getChannel(ctx, tx, stale.Address, stale.UUID, true)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case channel.Status == ChannelOpen && !channel.CreatedAt.After(channelCutoff(now, s.cfg.ChannelLifetime)):
+				_, err = startClosing(ctx, tx, channel.UUID, now.Add(window))
+			case channel.Status == ChannelClosing && !channel.settleable(now):
+				_, err = s.closeChannel(ctx, tx, channel)
+			default:
+				// The channel was closed, or another replica swept it, in the meantime.
+				return nil
+			}
+
+			moved = err == nil
+
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", stale.UUID, err))
+
+			continue
+		}
+
+		if moved {
+			swept++
+		}
+	}
+
+	return swept, errors.Join(errs...)
+}
+
+// channelCutoff returns when the channels that outlived the lifetime were opened. Without a lifetime, channels
+// stay open until they are closed.
+func channelCutoff(now time.Time, lifetime time.Duration) time.Time {
+	if lifetime <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(-lifetime)
+}
+
+// startClosing starts the settle window of an open channel.
+func startClosing(ctx context.Context, tx fidl.Queryable, id uuid.UUID, closesAt time.Time) (Channel, error) {
+	var channel Channel
+
+	query :=
+		`
+		UPDATE channels
+			SET status_id = $2,
+				closes_at = $3,
+				updated_at = now() at time zone 'utc'
+			WHERE uuid = $1
+			RETURNING *
+		`
+
+	if err := tx.GetContext(ctx, &channel, query, id, ChannelClosing, closesAt); err != nil {
+		return Channel{}, fmt.Errorf("failed to start closing channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// closeChannel closes a channel that can no longer be settled, and returns what was not redeemed to its client.
+func (s BankService) closeChannel(ctx context.Context, tx fidl.Queryable, channel Channel) (Channel, error) {
+	closeQuery :=
+		`
+		UPDATE channels
+			SET status_id = $2,
+				closes_at = now() at time zone 'utc',
+				updated_at = now() at time zone 'utc'
+			WHERE uuid = $1
+			RETURNING *
+		`
+
+	refundQuery :=
+		`
+		UPDATE balances
+  			SET balance = balance + $2,
+				escrow = escrow - $2,
+				updated_at = now() at time zone 'utc'
+  			WHERE id = $1
+  			AND escrow >= $2
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		`
+
+	remaining := new(big.Int).Sub(channel.Balance.Int, channel.Redeemed.Int)
+
+	if err := tx.GetContext(ctx, &channel, closeQuery, channel.UUID, ChannelClosed); err != nil {
+		return Channel{}, fmt.Errorf("failed to close channel: %w", err)
+	}
+
+	if remaining.Sign() == 0 {
+		return channel, nil
+	}
+
+	client, err := getAccountByAddress(ctx, channel.Address, tx)
+	if err != nil {
+		return Channel{}, fmt.Errorf("failed to fetch cli account: %w", err)
+	}
+
+	if err := execOne(ctx, tx, refundQuery, client.ID, remaining.String()); err != nil {
+		return Channel{}, fmt.Errorf("failed to refund channel balance: %w", err)
+	}
+
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return Channel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	args := []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, remaining.String(), TransactionCompleted, channel.Address, channel.Proxy, TransactionRefund}
+	if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+		return Channel{}, fmt.Errorf("failed to register transaction during channel close: %w", err)
+	}
+
+	err = postJournal(ctx, tx, transactionID.String(),
+		debit(channel.Address, LedgerEscrow, remaining),
+		credit(channel.Address, LedgerBalance, remaining),
+	)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if err := checkLedger(ctx, tx, channel.Address); err != nil {
+		return Channel{}, err
+	}
+
+	return channel, nil
+}
+
+func getChannel(ctx context.Context, tx fidl.Queryable, address string, id uuid.UUID, lock bool) (Channel, error) {
+	var channel Channel
+
+	query :=
+		`
+		SELECT *
+		FROM channels
+		WHERE uuid = $1
+		AND (wallet_address = $2 OR proxy = $2)
+		`
+
+	if lock {
+		query += "FOR UPDATE"
+	}
+
+	if err := tx.GetContext(ctx, &channel, query, id, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Channel{}, bank.ErrChannelNotFound
+		}
+
+		return Channel{}, fmt.Errorf("failed to fetch channel: %w", err)
+	}
+
+	return channel, nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ChainCursor returns the last block processed by the chain follower with the given name, if it ran before.
+func (s BankService) ChainCursor(ctx context.Context, name string) (uint64, bool, error) {
+	var block int64
+
+	query :=
+		`
+		SELECT block FROM chain_cursors WHERE name = $1
+		`
+
+	err := s.db.QueryRowContext(ctx, query, name).Scan(&block)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to fetch chain cursor: %w", err)
+	}
+
+	return uint64(block), true, nil // nolint:gosec
+}
+
+// SetChainCursor moves the cursor of a chain follower forward. A follower that lags behind another one does
+// not move it back.
+func (s BankService) SetChainCursor(ctx context.Context, name string, block uint64) error {
+	query :=
+		`
+		INSERT INTO chain_cursors (name, block)
+		VALUES ($1, $2)
+		ON CONFLICT (name)
+		DO UPDATE
+		SET block = GREATEST(chain_cursors.block, EXCLUDED.block),
+			updated_at = now() at time zone 'utc'
+		`
+
+	if _, err := s.db.ExecContext(ctx, query, name, int64(block)); err != nil { // nolint:gosec
+		return fmt.Errorf("failed to update chain cursor: %w", err)
+	}
+
+	return nil
+}
@@ -1,14 +1,39 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/subvisual/fidl"
 	"github.com/subvisual/fidl/bank"
 	"github.com/subvisual/fidl/types"
 )
 
-func (s BankService) Deposit(address string, amount types.FIL, transactionHash string) (types.FIL, error) {
+// Deposit credits a client with a transfer to the bank's wallet, less the deposit fee.
+func (s BankService) Deposit(ctx context.Context, address string, amount types.FIL, transactionHash string) (types.FIL, error) {
+	var balance types.FIL
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		var err error
+		balance, err = s.deposit(ctx, tx, address, amount, transactionHash)
+		if err != nil {
+			return err
+		}
+
+		return respond(ctx, tx, balance)
+	})
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	return balance, nil
+}
+
+// deposit credits a client with a transfer in the transaction tx, and returns the client's new balance.
+func (s BankService) deposit(ctx context.Context, tx fidl.Queryable, address string, amount types.FIL, transactionHash string) (types.FIL, error) {
 	var balance types.FIL
 
 	insertAccountQuery :=
@@ -32,38 +57,61 @@ func (s BankService) Deposit(address string, amount types.FIL, transactionHash s
 	// nolint:goconst
 	transactionQuery :=
 		`
-		INSERT INTO transactions (transaction_id, source, destination, value, status_id)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		ON CONFLICT (transaction_id) DO NOTHING
 		`
 
-	err := Transaction(s.db, func(tx fidl.Queryable) error {
-		args := []any{address, Client}
-		if _, err := tx.Exec(insertAccountQuery, args...); err != nil {
-			return fmt.Errorf("failed to add account entry: %w", err)
-		}
+	args := []any{address, Client}
+	if _, err := tx.ExecContext(ctx, insertAccountQuery, args...); err != nil {
+		return types.FIL{}, fmt.Errorf("failed to add account entry: %w", err)
+	}
 
-		account, err := getAccountByAddress(address, tx)
-		if err != nil {
-			return fmt.Errorf("failed to fetch account: %w", err)
-		}
+	account, err := getAccountByAddress(ctx, address, tx)
+	if err != nil {
+		return types.FIL{}, fmt.Errorf("failed to fetch account: %w", err)
+	}
 
-		if account.Type == StorageProvider {
-			return bank.ErrOperationNotAllowed
-		}
+	if err := s.usable(account, bank.OperationDeposit); err != nil {
+		return types.FIL{}, err
+	}
 
-		args = []any{account.ID, amount.Int.String()}
-		if err := tx.QueryRow(depositQuery, args...).Scan(&balance); err != nil {
-			return fmt.Errorf("failed to deposit balance: %w", err)
-		}
+	if account.Type == StorageProvider {
+		return types.FIL{}, bank.ErrOperationNotAllowed
+	}
 
-		args = []any{transactionHash, address, s.cfg.WalletAddress, amount.Int.String(), TransactionCompleted}
-		if _, err := tx.Exec(transactionQuery, args...); err != nil {
-			return fmt.Errorf("failed to register transaction during deposit: %w", err)
-		}
+	fee := s.cfg.Fees.Deposit.Charge(amount.Int)
+	credited := new(big.Int).Sub(amount.Int, fee)
+
+	args = []any{account.ID, credited.String()}
+	if err := tx.QueryRowContext(ctx, depositQuery, args...).Scan(&balance); err != nil {
+		return types.FIL{}, fmt.Errorf("failed to deposit balance: %w", err)
+	}
+
+	args = []any{transactionHash, address, s.cfg.WalletAddress, amount.Int.String(), TransactionCompleted, address, s.cfg.WalletAddress, TransactionDeposit}
+	err = execOne(ctx, tx, transactionQuery, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.FIL{}, bank.ErrTransactionExists
+	}
 
-		return nil
-	})
 	if err != nil {
+		return types.FIL{}, fmt.Errorf("failed to register transaction during deposit: %w", err)
+	}
+
+	err = postJournal(ctx, tx, transactionHash,
+		debit(s.cfg.WalletAddress, LedgerWallet, amount.Int),
+		credit(address, LedgerBalance, credited),
+		credit(s.cfg.WalletAddress, LedgerRevenue, fee),
+	)
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	if err := recordFee(ctx, tx, transactionHash, address, TransactionDeposit, fee); err != nil {
+		return types.FIL{}, err
+	}
+
+	if err := checkLedger(ctx, tx, address); err != nil {
 		return types.FIL{}, err
 	}
 
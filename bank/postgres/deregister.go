@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// outstandingQuery matches the storage providers that may still be paid, by redeeming an authorization
+// that has not expired or settling a channel that is still settleable. It takes the current time as $1,
+// and outstandingArgs from $4 on.
+const outstandingQuery = `
+		EXISTS (
+			SELECT 1 FROM escrow e
+			WHERE e.proxy = a.wallet_address
+			  AND e.status_id IN ($4, $5)
+			  AND e.expires_at > $1
+		)
+		OR EXISTS (
+			SELECT 1 FROM channels c
+			WHERE c.proxy = a.wallet_address
+			  AND (c.status_id = $6 OR (c.status_id = $7 AND c.closes_at > $1))
+		)
+		`
+
+var outstandingArgs = []any{AuthorizationOpen, AuthorizationLocked, ChannelOpen, ChannelClosing}
+
+// Deregister stops new authorizations and channels against a storage provider. Open channels start
+// closing, and once nothing outstanding can be redeemed the remaining balance is paid to the destination.
+func (s BankService) Deregister(ctx context.Context, address string, destination string) error {
+	if destination == s.cfg.WalletAddress {
+		return bank.ErrOperationNotAllowed
+	}
+
+	deregisterQuery :=
+		`
+		UPDATE storage_providers
+			SET status_id = $2,
+				payout_address = $3,
+				updated_at = now() at time zone 'utc'
+			WHERE id = $1
+			AND status_id = $4
+		`
+
+	closeChannelsQuery :=
+		`
+		UPDATE channels
+			SET status_id = $2,
+				closes_at = $3,
+				updated_at = now() at time zone 'utc'
+			WHERE proxy = $1
+			AND status_id = $4
+		`
+
+	window, err := time.ParseDuration(s.cfg.ChannelSettleWindow)
+	if err != nil {
+		return fmt.Errorf("failed to parse channel settle window from config: %w", err)
+	}
+
+	err = Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sp account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationDeregister); err != nil {
+			return err
+		}
+
+		if account.Type != StorageProvider {
+			return bank.ErrOperationNotAllowed
+		}
+
+		args := []any{account.ID, StorageProviderDeregistering, destination, StorageProviderActive}
+		if err := execOne(ctx, tx, deregisterQuery, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrProxyNotActive
+			}
+
+			return fmt.Errorf("failed to deregister storage provider: %w", err)
+		}
+
+		args = []any{address, ChannelClosing, time.Now().UTC().Add(window), ChannelOpen}
+		if _, err := tx.ExecContext(ctx, closeChannelsQuery, args...); err != nil {
+			return fmt.Errorf("failed to close storage provider channels: %w", err)
+		}
+
+		return respond(ctx, tx, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FinalizeDeregistrations pays out the balance of up to limit deregistering storage providers that can no
+// longer be paid, each in its own transaction, and marks them inactive. It returns how many it finalized.
+func (s BankService) FinalizeDeregistrations(ctx context.Context, limit int) (int, error) {
+	var ids []int64
+
+	settledQuery :=
+		`
+		SELECT sp.id
+		FROM storage_providers sp
+		JOIN accounts a ON a.id = sp.id
+		WHERE sp.status_id = $2
+		  AND NOT (` + outstandingQuery + `)
+		ORDER BY sp.updated_at
+		LIMIT $3
+		`
+
+	now := time.Now().UTC()
+
+	args := append([]any{now, StorageProviderDeregistering, limit}, outstandingArgs...)
+	if err := s.db.SelectContext(ctx, &ids, settledQuery, args...); err != nil {
+		return 0, fmt.Errorf("failed to fetch deregistering storage providers: %w", err)
+	}
+
+	var finalized int
+	var errs []error
+	for _, id := range ids {
+		err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+			return s.finalizeDeregistration(ctx, tx, id, now)
+		})
+		if err != nil {
+			// The storage provider registered again, or another replica finalized it, in the meantime.
+			if errors.Is(err, bank.ErrProxyNotActive) {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("storage provider %d: %w", id, err))
+
+			continue
+		}
+
+		finalized++
+	}
+
+	return finalized, errors.Join(errs...)
+}
+
+func (s BankService) finalizeDeregistration(ctx context.Context, tx fidl.Queryable, id int64, now time.Time) error {
+	var balance types.FIL
+	var payout string
+
+	lockQuery :=
+		`
+		SELECT sp.payout_address
+		FROM storage_providers sp
+		JOIN accounts a ON a.id = sp.id
+		WHERE sp.id = $2
+		  AND sp.status_id = $3
+		  AND NOT (` + outstandingQuery + `)
+		FOR UPDATE OF sp
+		`
+
+	balanceQuery :=
+		`
+		SELECT balance
+		FROM balances
+		WHERE id = $1
+		FOR UPDATE
+		`
+
+	payoutQuery :=
+		`
+		UPDATE balances
+  			SET balance = balance - $2,
+				updated_at = now() at time zone 'utc'
+  			WHERE id = $1
+  			AND balance >= $2
+		`
+
+	inactiveQuery :=
+		`
+		UPDATE storage_providers
+			SET status_id = $2,
+				deregistered_at = $3,
+				updated_at = now() at time zone 'utc'
+			WHERE id = $1
+		`
+
+	args := append([]any{now, id, StorageProviderDeregistering}, outstandingArgs...)
+	if err := tx.QueryRowContext(ctx, lockQuery, args...).Scan(&payout); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return bank.ErrProxyNotActive
+		}
+
+		return fmt.Errorf("failed to lock storage provider: %w", err)
+	}
+
+	account, err := getAccountByID(ctx, id, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.QueryRowContext(ctx, balanceQuery, id).Scan(&balance); err != nil {
+		return fmt.Errorf("failed to fetch storage provider balance: %w", err)
+	}
+
+	if balance.Sign() == 1 {
+		if err := execOne(ctx, tx, payoutQuery, id, balance.Int.String()); err != nil {
+			return fmt.Errorf("failed to debit final payout: %w", err)
+		}
+
+		withdrawalID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		// A balance that does not cover the withdrawal fee cannot be paid out, and is all kept as the fee.
+		fee := s.cfg.Fees.Withdraw.Charge(balance.Int)
+		if fee.Cmp(balance.Int) == -1 {
+			if _, err := s.registerWithdrawal(ctx, tx, withdrawalID, account.Address, payout, balance); err != nil {
+				return err
+			}
+		} else {
+			err := postJournal(ctx, tx, withdrawalID.String(),
+				debit(account.Address, LedgerBalance, balance.Int),
+				credit(s.cfg.WalletAddress, LedgerRevenue, balance.Int),
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := recordFee(ctx, tx, withdrawalID.String(), account.Address, TransactionWithdraw, balance.Int); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, inactiveQuery, id, StorageProviderInactive, now); err != nil {
+		return fmt.Errorf("failed to mark storage provider inactive: %w", err)
+	}
+
+	return checkLedger(ctx, tx, account.Address)
+}
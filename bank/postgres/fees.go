@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type FeeEntry struct {
+	ID            int64           `db:"id"`
+	TransactionID string          `db:"transaction_id"`
+	Address       string          `db:"wallet_address"`
+	Type          TransactionType `db:"type_id"`
+	Value         types.FIL       `db:"value"`
+	RefundedAt    sql.NullTime    `db:"refunded_at"`
+	CreatedAt     time.Time       `db:"created_at"`
+}
+
+func (f FeeEntry) Model() bank.CollectedFee {
+	return bank.CollectedFee{
+		ID:            f.ID,
+		TransactionID: f.TransactionID,
+		Type:          f.Type.String(),
+		Address:       f.Address,
+		Amount:        f.Value,
+		CreatedAt:     f.CreatedAt,
+	}
+}
+
+// recordFee keeps the fee an account paid on a transaction. The fee itself is posted to the bank's
+// revenue book by the journal of the transaction.
+func recordFee(ctx context.Context, tx fidl.Queryable, transactionID string, address string, kind TransactionType, fee *big.Int) error {
+	if fee.Sign() == 0 {
+		return nil
+	}
+
+	query :=
+		`
+		INSERT INTO fees (transaction_id, wallet_address, type_id, value)
+		VALUES ($1, $2, $3, $4)
+		`
+
+	if _, err := tx.ExecContext(ctx, query, transactionID, address, kind, fee.String()); err != nil {
+		return fmt.Errorf("failed to record fee: %w", err)
+	}
+
+	return nil
+}
+
+// Fees reports the fees the bank collected, newest first, along with their totals over the whole period.
+// Fees of reversed withdrawals were given back and are left out. Only the bank's wallet may see them.
+func (s BankService) Fees(ctx context.Context, address string, params bank.FeesParams) (bank.FeesReport, error) {
+	var entries []FeeEntry
+	var totals []struct {
+		Type  TransactionType `db:"type_id"`
+		Value types.FIL       `db:"value"`
+	}
+
+	if address != s.cfg.WalletAddress {
+		return bank.FeesReport{}, bank.ErrOperationNotAllowed
+	}
+
+	feesQuery :=
+		`
+		SELECT *
+		FROM fees
+		WHERE refunded_at IS NULL
+		  AND ($1::timestamp IS NULL OR created_at >= $1)
+		  AND ($2::timestamp IS NULL OR created_at < $2)
+		  AND ($3::integer IS NULL OR type_id = $3)
+		  AND ($4::bigint IS NULL OR id < $4)
+		ORDER BY id DESC
+		LIMIT $5
+		`
+
+	totalsQuery :=
+		`
+		SELECT type_id, SUM(value) AS value
+		FROM fees
+		WHERE refunded_at IS NULL
+		  AND ($1::timestamp IS NULL OR created_at >= $1)
+		  AND ($2::timestamp IS NULL OR created_at < $2)
+		  AND ($3::integer IS NULL OR type_id = $3)
+		GROUP BY type_id
+		`
+
+	var from, to sql.NullTime
+	if !params.From.IsZero() {
+		from = sql.NullTime{Time: params.From.UTC(), Valid: true}
+	}
+
+	if !params.To.IsZero() {
+		to = sql.NullTime{Time: params.To.UTC(), Valid: true}
+	}
+
+	var kind sql.NullInt16
+	if params.Type != "" {
+		t, ok := parseTransactionType(params.Type)
+		if !ok {
+			return bank.FeesReport{}, fmt.Errorf("unknown transaction type: %s", params.Type)
+		}
+
+		kind = sql.NullInt16{Int16: int16(t), Valid: true}
+	}
+
+	var cursor sql.NullInt64
+	if params.Cursor > 0 {
+		cursor = sql.NullInt64{Int64: params.Cursor, Valid: true}
+	}
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		if err := tx.SelectContext(ctx, &entries, feesQuery, from, to, kind, cursor, params.Limit); err != nil {
+			return fmt.Errorf("failed to fetch fees: %w", err)
+		}
+
+		if err := tx.SelectContext(ctx, &totals, totalsQuery, from, to, kind); err != nil {
+			return fmt.Errorf("failed to sum fees: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return bank.FeesReport{}, err
+	}
+
+	report := bank.FeesReport{
+		Total:  types.NewFIL(new(big.Int)),
+		ByType: make(map[string]types.FIL, len(totals)),
+		Fees:   make([]bank.CollectedFee, 0, len(entries)),
+	}
+
+	for _, t := range totals {
+		report.ByType[t.Type.String()] = t.Value
+		report.Total.Int.Add(report.Total.Int, t.Value.Int)
+	}
+
+	for _, e := range entries {
+		report.Fees = append(report.Fees, e.Model())
+	}
+
+	return report, nil
+}
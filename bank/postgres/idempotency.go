@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+)
+
+// BeginIdempotentRequest claims the idempotency key of an account for a request. It returns the stored
+// response when the request was already processed, and nil when the caller is the one to process it.
+func (s BankService) BeginIdempotentRequest(ctx context.Context, address string, key string, hash string, expiresAt time.Time) (*bank.IdempotentResponse, error) {
+	var response *bank.IdempotentResponse
+
+	insertKeyQuery :=
+		`
+		INSERT INTO idempotency_keys (wallet_address, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (wallet_address, key) DO NOTHING
+		`
+
+	storedQuery :=
+		`
+		SELECT request_hash, status_code, response
+		FROM idempotency_keys
+		WHERE wallet_address = $1
+		  AND key = $2
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		res, err := tx.ExecContext(ctx, insertKeyQuery, address, key, hash, expiresAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to register idempotency key: %w", err)
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		if rows == 1 {
+			return nil
+		}
+
+		var storedHash string
+		var status sql.NullInt64
+		var body []byte
+		if err := tx.QueryRowContext(ctx, storedQuery, address, key).Scan(&storedHash, &status, &body); err != nil {
+			return fmt.Errorf("failed to fetch idempotency key: %w", err)
+		}
+
+		switch {
+		case storedHash != hash:
+			return bank.ErrIdempotencyKeyReused
+		case !status.Valid:
+			return bank.ErrIdempotencyKeyInProgress
+		}
+
+		response = &bank.IdempotentResponse{Status: int(status.Int64), Body: body}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CompleteIdempotentRequest stores the response of a request, to be replayed for its retries. It keeps a
+// response already stored by the service call of the request.
+func (s BankService) CompleteIdempotentRequest(ctx context.Context, address string, key string, response bank.IdempotentResponse) error {
+	return completeIdempotentRequest(ctx, s.db, address, key, response)
+}
+
+func completeIdempotentRequest(ctx context.Context, q fidl.Queryable, address string, key string, response bank.IdempotentResponse) error {
+	query :=
+		`
+		UPDATE idempotency_keys
+			SET status_code = $3,
+				response = $4,
+				updated_at = now() at time zone 'utc'
+			WHERE wallet_address = $1
+			AND key = $2
+			AND status_code IS NULL
+		`
+
+	if _, err := q.ExecContext(ctx, query, address, key, response.Status, response.Body); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotentRequest frees the idempotency key of a request that did not complete, so it can be retried.
+func (s BankService) ReleaseIdempotentRequest(ctx context.Context, address string, key string) error {
+	query :=
+		`
+		DELETE FROM idempotency_keys
+		WHERE wallet_address = $1
+		  AND key = $2
+		  AND status_code IS NULL
+		`
+
+	if _, err := s.db.ExecContext(ctx, query, address, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// PruneIdempotencyKeys deletes the idempotency keys that expired before the given time.
+func (s BankService) PruneIdempotencyKeys(ctx context.Context, before time.Time) (int, error) {
+	query :=
+		`
+		DELETE FROM idempotency_keys WHERE expires_at < $1
+		`
+
+	res, err := s.db.ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+// respond stores the response of the idempotent request in ctx, if it has one, in the transaction of the
+// service call whose result it reports.
+func respond(ctx context.Context, q fidl.Queryable, result any) error {
+	req, ok := ctx.Value(bank.CtxKeyIdempotentRequest).(*bank.IdempotentRequest)
+	if !ok || req.Respond == nil {
+		return nil
+	}
+
+	response, err := req.Respond(result)
+	if err != nil {
+		return fmt.Errorf("failed to render idempotent response: %w", err)
+	}
+
+	return completeIdempotentRequest(ctx, q, req.Address, req.Key, response)
+}
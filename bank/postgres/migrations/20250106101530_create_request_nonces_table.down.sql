@@ -0,0 +1 @@
+DROP TABLE request_nonces;
\ No newline at end of file
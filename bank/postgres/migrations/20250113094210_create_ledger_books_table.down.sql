@@ -0,0 +1 @@
+DROP TABLE ledger_books;
\ No newline at end of file
@@ -0,0 +1,11 @@
+BEGIN;
+
+INSERT INTO
+  ledger_books (id, name)
+VALUES
+  (1, 'Wallet'),
+  (2, 'Balance'),
+  (3, 'Escrow'),
+  (4, 'Opening');
+
+COMMIT;
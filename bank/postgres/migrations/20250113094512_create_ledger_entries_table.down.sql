@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE ledger_entries;
+DROP FUNCTION ledger_entries_check_journal;
+DROP FUNCTION ledger_entries_append_only;
+
+COMMIT;
@@ -0,0 +1,46 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  ledger_entries (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    journal_id UUID NOT NULL,
+    transaction_id text,
+    wallet_address text NOT NULL,
+    book_id integer NOT NULL REFERENCES ledger_books (id),
+    debit numeric(38) NOT NULL DEFAULT 0 CHECK (debit >= 0),
+    credit numeric(38) NOT NULL DEFAULT 0 CHECK (credit >= 0),
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    CHECK ((debit = 0) <> (credit = 0))
+  );
+
+CREATE INDEX ledger_entries_journal_idx ON ledger_entries (journal_id);
+CREATE INDEX ledger_entries_account_idx ON ledger_entries (wallet_address, book_id, created_at);
+
+-- Every journal must balance once the transaction that wrote it commits.
+CREATE OR REPLACE FUNCTION ledger_entries_check_journal() RETURNS trigger AS $$
+BEGIN
+  IF (SELECT SUM(debit) - SUM(credit) FROM ledger_entries WHERE journal_id = NEW.journal_id) <> 0 THEN
+    RAISE EXCEPTION 'ledger journal % is not balanced', NEW.journal_id;
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+  AFTER INSERT ON ledger_entries
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION ledger_entries_check_journal();
+
+-- The ledger is append only, corrections are posted as new journals.
+CREATE OR REPLACE FUNCTION ledger_entries_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'ledger entries cannot be modified';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_immutable
+  BEFORE UPDATE OR DELETE ON ledger_entries
+  FOR EACH ROW EXECUTE FUNCTION ledger_entries_append_only();
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Open the ledger with the balances that existed before it was introduced.
+WITH opening AS (
+  SELECT gen_random_uuid() AS journal_id, a.wallet_address, b.balance, b.escrow
+  FROM balances b
+  JOIN accounts a ON a.id = b.id
+  WHERE b.balance > 0 OR b.escrow > 0
+)
+INSERT INTO
+  ledger_entries (journal_id, wallet_address, book_id, debit, credit)
+SELECT journal_id, wallet_address, 4, balance + escrow, 0 FROM opening
+UNION ALL
+SELECT journal_id, wallet_address, 2, 0, balance FROM opening WHERE balance > 0
+UNION ALL
+SELECT journal_id, wallet_address, 3, 0, escrow FROM opening WHERE escrow > 0;
+
+COMMIT;
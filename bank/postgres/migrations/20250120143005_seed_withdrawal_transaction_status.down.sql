@@ -0,0 +1,6 @@
+BEGIN;
+
+DELETE FROM ledger_books WHERE id = 5;
+DELETE FROM transaction_status WHERE id IN (3, 4);
+
+COMMIT;
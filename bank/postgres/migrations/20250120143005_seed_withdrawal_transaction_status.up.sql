@@ -0,0 +1,14 @@
+BEGIN;
+
+INSERT INTO
+  transaction_status (id, name)
+VALUES
+  (3, 'Submitted'),
+  (4, 'Reversed');
+
+INSERT INTO
+  ledger_books (id, name)
+VALUES
+  (5, 'Withdrawal');
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  withdrawals (
+    id UUID PRIMARY KEY NOT NULL,
+    wallet_address text NOT NULL,
+    destination text NOT NULL,
+    value numeric(38) NOT NULL DEFAULT 0,
+    status_id integer NOT NULL DEFAULT 1 REFERENCES transaction_status (id),
+    hash text,
+    raw_transaction bytea,
+    submitted_at timestamp(0),
+    locked_until timestamp(0),
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    updated_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc'))
+  );
+
+CREATE INDEX withdrawals_status_idx ON withdrawals (status_id, created_at);
+CREATE INDEX withdrawals_wallet_address_idx ON withdrawals (wallet_address);
+CREATE UNIQUE INDEX withdrawals_hash_idx ON withdrawals (hash);
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE transaction_types;
\ No newline at end of file
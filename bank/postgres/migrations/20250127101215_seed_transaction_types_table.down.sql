@@ -0,0 +1,5 @@
+BEGIN;
+
+DELETE FROM transaction_types WHERE id IN (1, 2, 3, 4, 5);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+INSERT INTO
+  transaction_types (id, name)
+VALUES
+  (1, 'Deposit'),
+  (2, 'Withdraw'),
+  (3, 'Authorize'),
+  (4, 'Redeem'),
+  (5, 'Refund');
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS transactions_counterpart_idx;
+DROP INDEX IF EXISTS transactions_wallet_address_idx;
+
+ALTER TABLE transactions
+  DROP COLUMN type_id,
+  DROP COLUMN counterpart,
+  DROP COLUMN wallet_address;
+
+COMMIT;
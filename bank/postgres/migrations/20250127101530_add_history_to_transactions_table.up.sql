@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE transactions
+  ADD COLUMN wallet_address text,
+  ADD COLUMN counterpart text,
+  ADD COLUMN type_id integer REFERENCES transaction_types (id);
+
+CREATE INDEX transactions_wallet_address_idx ON transactions (wallet_address, id);
+CREATE INDEX transactions_counterpart_idx ON transactions (counterpart, id);
+
+COMMIT;
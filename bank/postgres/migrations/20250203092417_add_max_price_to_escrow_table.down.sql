@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE escrow DROP COLUMN max_price;
+
+COMMIT;
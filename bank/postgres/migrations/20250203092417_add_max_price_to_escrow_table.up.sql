@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE escrow ADD COLUMN max_price numeric(38);
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE authorization_modes;
\ No newline at end of file
@@ -0,0 +1,5 @@
+BEGIN;
+
+DELETE FROM authorization_modes WHERE id IN (1, 2);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+INSERT INTO
+  authorization_modes (id, name)
+VALUES
+  (1, 'Single'),
+  (2, 'Multi');
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE escrow
+  DROP COLUMN amount,
+  DROP COLUMN mode_id;
+
+COMMIT;
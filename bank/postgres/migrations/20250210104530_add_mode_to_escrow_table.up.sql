@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE escrow
+  ADD COLUMN mode_id integer NOT NULL DEFAULT 1 REFERENCES authorization_modes (id),
+  ADD COLUMN amount numeric(38);
+
+UPDATE escrow SET amount = balance;
+
+ALTER TABLE escrow ALTER COLUMN amount SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE redemptions;
\ No newline at end of file
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  redemptions (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    authorization_uuid UUID NOT NULL,
+    transaction_id text NOT NULL,
+    proxy text NOT NULL,
+    value numeric(38) NOT NULL DEFAULT 0,
+    remaining numeric(38) NOT NULL DEFAULT 0,
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc'))
+  );
+
+CREATE INDEX redemptions_authorization_uuid_idx ON redemptions (authorization_uuid);
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE channel_status;
\ No newline at end of file
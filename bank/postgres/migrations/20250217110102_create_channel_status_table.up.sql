@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS 
+  channel_status (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name text NOT NULL,
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    updated_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc'))
+  );
+
+CREATE UNIQUE INDEX idx_channel_status_name_idx ON channel_status(name);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+INSERT INTO
+  channel_status (id, name)
+VALUES
+  (1, 'Open'),
+  (2, 'Closing'),
+  (3, 'Closed');
+
+COMMIT;
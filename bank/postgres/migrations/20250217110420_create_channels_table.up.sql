@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  channels (
+    uuid UUID PRIMARY KEY NOT NULL,
+    wallet_address text NOT NULL,
+    proxy text NOT NULL,
+    balance numeric(38) NOT NULL DEFAULT 0,
+    redeemed numeric(38) NOT NULL DEFAULT 0,
+    status_id integer NOT NULL DEFAULT 1 REFERENCES channel_status (id),
+    closes_at timestamp(0),
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    updated_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    CONSTRAINT channels_redeemed_check CHECK (redeemed <= balance)
+  );
+
+CREATE INDEX channels_wallet_address_idx ON channels (wallet_address);
+CREATE INDEX channels_proxy_idx ON channels (proxy);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX escrow_expires_at_idx;
+
+ALTER TABLE escrow DROP COLUMN expires_at;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE escrow ADD COLUMN expires_at timestamp;
+
+-- Outstanding authorizations keep the lifetime of the default escrow deadline.
+UPDATE escrow SET expires_at = created_at + interval '24 hours';
+
+ALTER TABLE escrow ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX escrow_expires_at_idx ON escrow (expires_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DELETE FROM escrow WHERE status_id IN (3, 4, 5);
+
+DELETE FROM authorization_status WHERE id IN (3, 4, 5);
+
+COMMIT;
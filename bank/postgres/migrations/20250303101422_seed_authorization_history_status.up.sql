@@ -0,0 +1,10 @@
+BEGIN;
+
+INSERT INTO
+  authorization_status (id, name)
+VALUES
+  (3, 'Redeemed'),
+  (4, 'Refunded'),
+  (5, 'Cancelled');
+
+COMMIT;
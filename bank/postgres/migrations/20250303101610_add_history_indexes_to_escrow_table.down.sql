@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX escrow_status_idx;
+DROP INDEX escrow_proxy_idx;
+DROP INDEX escrow_account_idx;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+CREATE INDEX escrow_account_idx ON escrow (id);
+CREATE INDEX escrow_proxy_idx ON escrow (proxy);
+CREATE INDEX escrow_status_idx ON escrow (status_id);
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE storage_provider_prices;
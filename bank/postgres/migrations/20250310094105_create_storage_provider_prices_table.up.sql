@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  storage_provider_prices (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    storage_provider_id bigint NOT NULL REFERENCES storage_providers(id),
+    price numeric(38) NOT NULL,
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc'))
+  );
+
+CREATE INDEX storage_provider_prices_storage_provider_idx ON storage_provider_prices (storage_provider_id, created_at);
+
+INSERT INTO storage_provider_prices (storage_provider_id, price, created_at)
+SELECT id, price, updated_at FROM storage_providers;
+
+COMMIT;
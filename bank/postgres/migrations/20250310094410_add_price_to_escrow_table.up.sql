@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE escrow ADD COLUMN price numeric(38);
+
+UPDATE escrow e
+  SET price = sp.price
+  FROM accounts a
+  JOIN storage_providers sp ON sp.id = a.id
+  WHERE a.wallet_address = e.proxy;
+
+ALTER TABLE escrow ALTER COLUMN price SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DELETE FROM storage_provider_status WHERE id IN (1, 2, 3);
+
+COMMIT;
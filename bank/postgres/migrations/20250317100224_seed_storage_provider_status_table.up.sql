@@ -0,0 +1,10 @@
+BEGIN;
+
+INSERT INTO
+  storage_provider_status (id, name)
+VALUES
+  (1, 'Active'),
+  (2, 'Deregistering'),
+  (3, 'Inactive');
+
+COMMIT;
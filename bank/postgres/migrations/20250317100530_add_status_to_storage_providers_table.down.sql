@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX storage_providers_status_idx;
+
+ALTER TABLE storage_providers
+  DROP COLUMN deregistered_at,
+  DROP COLUMN payout_address,
+  DROP COLUMN status_id;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE storage_providers
+  ADD COLUMN status_id integer NOT NULL DEFAULT 1 REFERENCES storage_provider_status (id),
+  ADD COLUMN payout_address text,
+  ADD COLUMN deregistered_at timestamp(0);
+
+CREATE INDEX storage_providers_status_idx ON storage_providers (status_id);
+
+COMMIT;
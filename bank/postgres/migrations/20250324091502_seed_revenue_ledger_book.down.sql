@@ -0,0 +1,6 @@
+BEGIN;
+
+DELETE FROM ledger_entries WHERE book_id = 6;
+DELETE FROM ledger_books WHERE id = 6;
+
+COMMIT;
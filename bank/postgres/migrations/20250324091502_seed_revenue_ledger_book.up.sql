@@ -0,0 +1,8 @@
+BEGIN;
+
+INSERT INTO
+  ledger_books (id, name)
+VALUES
+  (6, 'Revenue');
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  fees (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    transaction_id text NOT NULL,
+    wallet_address text NOT NULL,
+    type_id integer NOT NULL REFERENCES transaction_types (id),
+    value numeric(38) NOT NULL,
+    refunded_at timestamp(0),
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc'))
+  );
+
+CREATE INDEX fees_created_at_idx ON fees (created_at);
+CREATE INDEX fees_transaction_id_idx ON fees (transaction_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE withdrawals
+  DROP COLUMN fee;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE withdrawals
+  ADD COLUMN fee numeric(38) NOT NULL DEFAULT 0;
+
+COMMIT;
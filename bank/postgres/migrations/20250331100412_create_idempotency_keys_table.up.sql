@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  idempotency_keys (
+    wallet_address text NOT NULL,
+    key text NOT NULL,
+    request_hash text NOT NULL,
+    status_code integer,
+    response bytea,
+    expires_at timestamp(0) NOT NULL,
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    updated_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    PRIMARY KEY (wallet_address, key)
+  );
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+INSERT INTO
+  account_status (id, name)
+VALUES
+  (1, 'Active'),
+  (2, 'Frozen'),
+  (3, 'Closed');
+
+COMMIT;
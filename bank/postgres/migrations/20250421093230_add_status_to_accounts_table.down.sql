@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX accounts_status_idx;
+
+ALTER TABLE accounts
+  DROP COLUMN status_id;
+
+COMMIT;
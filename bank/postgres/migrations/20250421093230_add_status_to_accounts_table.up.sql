@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE accounts
+  ADD COLUMN status_id integer NOT NULL DEFAULT 1 REFERENCES account_status (id);
+
+CREATE INDEX accounts_status_idx ON accounts (status_id);
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE chain_cursors;
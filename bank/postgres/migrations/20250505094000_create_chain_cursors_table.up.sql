@@ -0,0 +1,10 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  chain_cursors (
+    name text PRIMARY KEY,
+    block bigint NOT NULL,
+    updated_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc'))
+  );
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE held_deposit_status;
@@ -0,0 +1,10 @@
+BEGIN;
+
+INSERT INTO
+  held_deposit_status (id, name)
+VALUES
+  (1, 'Held'),
+  (2, 'Credited'),
+  (3, 'Returned');
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS
+  held_deposits (
+    id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    hash text NOT NULL,
+    sender text NOT NULL,
+    wallet_address text,
+    value numeric(38) NOT NULL,
+    reason text NOT NULL,
+    status_id integer NOT NULL DEFAULT 1 REFERENCES held_deposit_status (id),
+    credited_to text,
+    return_hash text,
+    resolved_at timestamp(0),
+    created_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc')),
+    updated_at timestamp(0) NOT NULL DEFAULT (NOW() at time zone ('utc'))
+  );
+
+CREATE UNIQUE INDEX held_deposits_hash_idx ON held_deposits (hash);
+CREATE INDEX held_deposits_return_hash_idx ON held_deposits (return_hash);
+CREATE INDEX held_deposits_status_idx ON held_deposits (status_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE escrow ALTER COLUMN expires_at DROP NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Authorizations left without an expiry keep the lifetime of the default escrow deadline.
+UPDATE escrow SET expires_at = created_at + interval '24 hours' WHERE expires_at IS NULL;
+
+ALTER TABLE escrow ALTER COLUMN expires_at SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+)
+
+func (s BankService) RegisterNonce(ctx context.Context, address string, nonce string, expiresAt time.Time) error {
+	insertNonceQuery :=
+		`
+		INSERT INTO request_nonces (wallet_address, nonce, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (wallet_address, nonce) DO NOTHING
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		res, err := tx.ExecContext(ctx, insertNonceQuery, address, nonce, expiresAt.UTC())
+		if err != nil {
+			return fmt.Errorf("failed to register nonce: %w", err)
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		if rows == 0 {
+			return bank.ErrNonceReused
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PruneNonces deletes the nonces that expired before the given time. Their requests are refused for being
+// too old, so the nonces are no longer needed to refuse a replay.
+func (s BankService) PruneNonces(ctx context.Context, before time.Time) (int, error) {
+	query :=
+		`
+		DELETE FROM request_nonces WHERE expires_at < $1
+		`
+
+	res, err := s.db.ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired nonces: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rows), nil
+}
@@ -1,10 +1,13 @@
 package postgres
 
 import (
+	"context"
+	"errors"
 	"log"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/subvisual/fidl"
 )
 
@@ -40,8 +43,30 @@ func Connect(cfg Config) *DB {
 	return db
 }
 
-func Transaction(db *DB, fn func(fidl.Queryable) error) (err error) {
-	tx, err := db.Beginx()
+// maxTransactionAttempts is how many times Transaction runs a transaction that fails with a serialization
+// failure or a deadlock, which leave nothing behind and are expected to succeed when run again.
+const maxTransactionAttempts = 5
+
+// Transaction runs fn in a transaction, running it again when the database aborts it in favour of a
+// concurrent one. fn may therefore be called more than once, and must only have effects through the
+// given Queryable.
+func Transaction(ctx context.Context, db *DB, fn func(fidl.Queryable) error) (err error) {
+	for attempt := 1; ; attempt++ {
+		err = transaction(ctx, db, fn)
+		if err == nil || !retryable(err) || attempt == maxTransactionAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * 10 * time.Millisecond):
+		}
+	}
+}
+
+func transaction(ctx context.Context, db *DB, fn func(fidl.Queryable) error) (err error) {
+	tx, err := db.BeginTxx(ctx, nil)
 	if err != nil {
 		return
 	}
@@ -64,3 +89,13 @@ func Transaction(db *DB, fn func(fidl.Queryable) error) (err error) {
 
 	return err
 }
+
+// retryable reports whether a transaction failed with a serialization failure or a deadlock.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
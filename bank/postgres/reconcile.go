@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type ChainEntry struct {
+	Type        TransactionType   `db:"type_id"`
+	ID          string            `db:"id"`
+	Hash        string            `db:"hash"`
+	Address     string            `db:"wallet_address"`
+	Counterpart string            `db:"counterpart"`
+	Value       types.FIL         `db:"value"`
+	Status      TransactionStatus `db:"status_id"`
+	At          time.Time         `db:"at"`
+}
+
+func (e ChainEntry) Model() bank.ChainEntry {
+	return bank.ChainEntry{
+		Type:        bank.TransactionType(e.Type.String()),
+		ID:          e.ID,
+		Hash:        e.Hash,
+		Address:     e.Address,
+		Counterpart: e.Counterpart,
+		Value:       e.Value,
+		Status:      bank.TransactionStatus(e.Status.String()),
+		At:          e.At,
+	}
+}
+
+// ChainEntries lists the deposits, the held deposits and their returns, and the signed withdrawals with one
+// of the hashes, or recorded in the time range, oldest first.
+func (s BankService) ChainEntries(ctx context.Context, params bank.ChainEntriesParams) ([]bank.ChainEntry, error) {
+	var entries []ChainEntry
+
+	hashes := make([]string, 0, len(params.Hashes))
+	for _, hash := range params.Hashes {
+		hashes = append(hashes, strings.ToLower(hash))
+	}
+
+	query :=
+		`
+		SELECT type_id, transaction_id AS id, transaction_id AS hash, wallet_address, source AS counterpart, value, status_id,
+			created_at AS at
+		FROM transactions
+		WHERE type_id = $1
+		  AND (lower(transaction_id) = ANY($3::text[]) OR created_at BETWEEN $4 AND $5)
+		  AND NOT EXISTS (SELECT 1 FROM held_deposits h WHERE h.hash = lower(transaction_id))
+		UNION ALL
+		SELECT $2::integer, id::text, hash, wallet_address, destination, value, status_id, submitted_at
+		FROM withdrawals
+		WHERE hash IS NOT NULL
+		  AND (lower(hash) = ANY($3::text[]) OR submitted_at BETWEEN $4 AND $5)
+		ORDER BY at
+		`
+
+	args := []any{TransactionDeposit, TransactionWithdraw, pq.Array(hashes), params.From.UTC(), params.To.UTC()}
+	if err := s.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch chain entries: %w", err)
+	}
+
+	res := make([]bank.ChainEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e.Model())
+	}
+
+	heldQuery :=
+		`
+		SELECT *
+		FROM held_deposits
+		WHERE hash = ANY($1::text[])
+		   OR lower(return_hash) = ANY($1::text[])
+		   OR created_at BETWEEN $2 AND $3
+		   OR resolved_at BETWEEN $2 AND $3
+		`
+
+	var held []HeldDepositEntry
+	if err := s.db.SelectContext(ctx, &held, heldQuery, pq.Array(hashes), params.From.UTC(), params.To.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to fetch held deposits: %w", err)
+	}
+
+	for _, h := range held {
+		for _, e := range h.ChainEntries() {
+			if slices.Contains(hashes, strings.ToLower(e.Hash)) || (!e.At.Before(params.From) && !e.At.After(params.To)) {
+				res = append(res, e)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].At.Before(res[j].At)
+	})
+
+	return res, nil
+}
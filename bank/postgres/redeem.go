@@ -1,6 +1,9 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -11,21 +14,29 @@ import (
 	"github.com/subvisual/fidl/types"
 )
 
-func (s BankService) Redeem(address string, id uuid.UUID, amount types.FIL) (bank.RedeemModel, error) {
+// Redeem pays a storage provider from a locked authorization. A single authorization is marked redeemed and its
+// excess returned to the client, while a multi authorization keeps the remaining amount and is unlocked for the
+// next retrieval, until it is used up. The storage provider is paid the amount less the redeem fee.
+func (s BankService) Redeem(ctx context.Context, address string, id uuid.UUID, amount types.FIL) (bank.RedeemModel, error) {
 	var spBalance types.FIL
 	var cliBalance types.FIL
 	var cliEscrow types.FIL
 	var excess types.FIL
+	var remaining types.FIL
+	var fee types.FIL
+	var model bank.RedeemModel
 
 	verifyAuthQuery :=
 		`
 		SELECT *
 		FROM escrow
-		WHERE uuid = $1 
+		WHERE uuid = $1
 		  AND proxy = $2
 		  AND balance >= $3
-		  AND created_at >= $4
+		  AND expires_at > $4
 		  AND status_id = $5
+		  AND price >= $3
+		FOR UPDATE
 		`
 
 	depositQuery :=
@@ -40,13 +51,23 @@ func (s BankService) Redeem(address string, id uuid.UUID, amount types.FIL) (ban
 	// nolint:goconst
 	transactionQuery :=
 		`
-		INSERT INTO transactions (transaction_id, source, destination, value, status_id)
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		`
+
+	redemptionQuery :=
+		`
+		INSERT INTO redemptions (authorization_uuid, transaction_id, proxy, value, remaining)
 		VALUES ($1, $2, $3, $4, $5)
 		`
 
-	deleteAuthQuery :=
+	drawDownAuthQuery :=
 		`
-		DELETE FROM escrow WHERE uuid = $1
+		UPDATE escrow
+			SET balance = $2,
+				status_id = $3,
+				updated_at = now() at time zone 'utc'
+			WHERE uuid = $1
 		`
 
 	cliEscrowQuery :=
@@ -56,54 +77,64 @@ func (s BankService) Redeem(address string, id uuid.UUID, amount types.FIL) (ban
 				updated_at = now() at time zone 'utc'
   			WHERE id = $1
   			AND escrow >= $2
-  			RETURNING escrow
+  			RETURNING balance, escrow
 		`
 
+	// Clients with authorizations are kept, as their authorizations are history.
 	deleteBalanceEntryQuery :=
 		`
-		DELETE FROM balances WHERE id = $1
+		DELETE FROM balances
+		WHERE id = $1
+		  AND NOT EXISTS (SELECT 1 FROM escrow WHERE escrow.id = $1)
 		`
 
 	deleteAccountEntryQuery :=
 		`
-		DELETE FROM accounts WHERE id = $1
+		DELETE FROM accounts
+		WHERE id = $1
+		  AND NOT EXISTS (SELECT 1 FROM escrow WHERE escrow.id = $1)
 		`
 
-	err := Transaction(s.db, func(tx fidl.Queryable) error {
-		account, err := getAccountByAddress(address, tx)
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
 		if err != nil {
 			return fmt.Errorf("failed to fetch account: %w", err)
 		}
 
+		if err := s.usable(account, bank.OperationRedeem); err != nil {
+			return err
+		}
+
 		if account.Type != StorageProvider {
 			return bank.ErrOperationNotAllowed
 		}
 
-		var ok bool
-		excess.Int, ok = new(big.Int).SetString(string("0"), 10)
-		if !ok {
-			return fmt.Errorf("failed to init excess to zero")
-		}
+		excess = types.NewFIL(new(big.Int))
+		remaining = types.NewFIL(new(big.Int))
 
-		cliBalance.Int, ok = new(big.Int).SetString(string("0"), 10)
-		if !ok {
-			return fmt.Errorf("failed to init cliBalance to zero")
+		var auth Authorization
+
+		// The authorization stays locked until the redemption commits, so a concurrent redeem of it
+		// finds it already drawn down.
+		args := []any{id, address, amount.Int.String(), time.Now().UTC(), AuthorizationLocked}
+		if err := tx.GetContext(ctx, &auth, verifyAuthQuery, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthNotFound
+			}
+
+			return fmt.Errorf("failed to fetch authorization: %w", err)
 		}
 
-		cfgDeadline, err := time.ParseDuration(s.cfg.EscrowDeadline)
+		client, err := getAccountByID(ctx, auth.ID, tx)
 		if err != nil {
-			return fmt.Errorf("failed to parse escrow deadline from config: %w", err)
+			return fmt.Errorf("failed to fetch cli account: %w", err)
 		}
 
-		var auth Authorization
-
-		args := []any{id, address, amount.Int.String(), time.Now().UTC().Add(-cfgDeadline), AuthorizationLocked}
-		if err := tx.Get(&auth, verifyAuthQuery, args...); err != nil {
-			return bank.ErrAuthNotFound
-		}
+		fee = types.NewFIL(s.cfg.Fees.Redeem.Charge(amount.Int))
+		paid := new(big.Int).Sub(amount.Int, fee.Int)
 
-		args = []any{account.ID, amount.Int.String()}
-		if err := tx.QueryRow(depositQuery, args...).Scan(&spBalance); err != nil {
+		args = []any{account.ID, paid.String()}
+		if err := tx.QueryRowContext(ctx, depositQuery, args...).Scan(&spBalance); err != nil {
 			return fmt.Errorf("failed to deposit balance to sp: %w", err)
 		}
 
@@ -112,58 +143,115 @@ func (s BankService) Redeem(address string, id uuid.UUID, amount types.FIL) (ban
 			return fmt.Errorf("failed to generate v7 uuid: %w", err)
 		}
 
-		args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, amount.Int.String(), TransactionCompleted}
-		if _, err := tx.Exec(transactionQuery, args...); err != nil {
+		args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, amount.Int.String(), TransactionCompleted, client.Address, address, TransactionRedeem}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
 			return fmt.Errorf("failed to register transaction during sp deposit: %w", err)
 		}
 
-		if auth.Balance.Int.Cmp(amount.Int) == 1 {
-			excess.Int.Sub(auth.Balance.Int, amount.Int)
+		err = postJournal(ctx, tx, transactionID.String(),
+			debit(client.Address, LedgerEscrow, amount.Int),
+			credit(address, LedgerBalance, paid),
+			credit(s.cfg.WalletAddress, LedgerRevenue, fee.Int),
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := recordFee(ctx, tx, transactionID.String(), address, TransactionRedeem, fee.Int); err != nil {
+			return err
+		}
+
+		released := new(big.Int).Set(auth.Balance.Int)
 
-			args = []any{auth.ID, excess.Int.String()}
-			if err := tx.QueryRow(depositQuery, args...).Scan(&cliBalance); err != nil {
-				return fmt.Errorf("failed to deposit balance to cli: %w", err)
+		switch auth.Mode {
+		case AuthorizationMulti:
+			remaining.Int.Sub(auth.Balance.Int, amount.Int)
+			released.Set(amount.Int)
+
+			status := AuthorizationOpen
+			if remaining.Sign() == 0 {
+				status = AuthorizationRedeemed
+			}
+
+			args = []any{id, remaining.Int.String(), status}
+			if _, err := tx.ExecContext(ctx, drawDownAuthQuery, args...); err != nil {
+				return fmt.Errorf("failed to draw down authorization during redeem: %w", err)
 			}
+		default:
+			if auth.Balance.Int.Cmp(amount.Int) == 1 {
+				excess.Int.Sub(auth.Balance.Int, amount.Int)
+
+				args = []any{auth.ID, excess.Int.String()}
+				if _, err := tx.ExecContext(ctx, depositQuery, args...); err != nil {
+					return fmt.Errorf("failed to deposit balance to cli: %w", err)
+				}
 
-			transactionID, err := uuid.NewV7()
-			if err != nil {
-				return fmt.Errorf("failed to generate v7 uuid: %w", err)
+				transactionID, err := uuid.NewV7()
+				if err != nil {
+					return fmt.Errorf("failed to generate v7 uuid: %w", err)
+				}
+
+				args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, excess.Int.String(), TransactionCompleted, client.Address, address, TransactionRefund}
+				if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+					return fmt.Errorf("failed to register transaction during cli deposit: %w", err)
+				}
+
+				err = postJournal(ctx, tx, transactionID.String(),
+					debit(client.Address, LedgerEscrow, excess.Int),
+					credit(client.Address, LedgerBalance, excess.Int),
+				)
+				if err != nil {
+					return err
+				}
 			}
 
-			args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, excess.Int.String(), TransactionCompleted}
-			if _, err := tx.Exec(transactionQuery, args...); err != nil {
-				return fmt.Errorf("failed to register transaction during cli deposit: %w", err)
+			args = []any{id, remaining.Int.String(), AuthorizationRedeemed}
+			if _, err := tx.ExecContext(ctx, drawDownAuthQuery, args...); err != nil {
+				return fmt.Errorf("failed to close authorization during redeem: %w", err)
 			}
 		}
 
-		if _, err := tx.Exec(deleteAuthQuery, id); err != nil {
-			return fmt.Errorf("failed to delete authorization during redeem: %w", err)
+		args = []any{id, transactionID.String(), address, amount.Int.String(), remaining.Int.String()}
+		if _, err := tx.ExecContext(ctx, redemptionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register redemption: %w", err)
 		}
 
-		args = []any{auth.ID, auth.Balance.Int.String()}
-		if err := tx.QueryRow(cliEscrowQuery, args...).Scan(&cliEscrow); err != nil {
+		args = []any{auth.ID, released.String()}
+		if err := tx.QueryRowContext(ctx, cliEscrowQuery, args...).Scan(&cliBalance, &cliEscrow); err != nil {
 			return fmt.Errorf("failed to update cli escrow: %w", err)
 		}
 
 		if cliBalance.Sign() == 0 && cliEscrow.Sign() == 0 {
-			if _, err := tx.Exec(deleteBalanceEntryQuery, auth.ID); err != nil {
+			if _, err := tx.ExecContext(ctx, deleteBalanceEntryQuery, auth.ID); err != nil {
 				return fmt.Errorf("failed to delete cli balance entry during redeem: %w", err)
 			}
 
-			if _, err := tx.Exec(deleteAccountEntryQuery, auth.ID); err != nil {
+			if _, err := tx.ExecContext(ctx, deleteAccountEntryQuery, auth.ID); err != nil {
 				return fmt.Errorf("failed to delete cli account entry during redeem: %w", err)
 			}
 		}
 
-		return nil
+		if err := checkLedger(ctx, tx, address); err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, client.Address); err != nil {
+			return err
+		}
+
+		model = bank.RedeemModel{
+			Excess:    excess,
+			Remaining: remaining,
+			Fee:       fee,
+			SP:        spBalance,
+			CLI:       cliBalance,
+		}
+
+		return respond(ctx, tx, model)
 	})
 	if err != nil {
 		return bank.RedeemModel{}, err
 	}
 
-	return bank.RedeemModel{
-		Excess: excess,
-		SP:     spBalance,
-		CLI:    cliBalance,
-	}, nil
+	return model, nil
 }
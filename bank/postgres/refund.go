@@ -1,7 +1,10 @@
 package postgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,24 +13,101 @@ import (
 	"github.com/subvisual/fidl/types"
 )
 
-func (s BankService) Refund(address string) (bank.RefundModel, error) {
-	var expiredSum types.FIL
-	var balance types.FIL
-	var escrow types.FIL
+func (s BankService) Refund(ctx context.Context, address string) (bank.RefundModel, error) {
+	var refund bank.RefundModel
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationRefund); err != nil {
+			return err
+		}
+
+		refund, err = s.refundExpired(ctx, tx, account, time.Now().UTC())
+
+		return err
+	})
+	if err != nil {
+		return bank.RefundModel{}, err
+	}
+
+	return refund, nil
+}
 
-	expiredQuery :=
+// SweepEscrow refunds the expired escrow of up to limit accounts, each in its own transaction.
+// An account that fails to be refunded does not stop the others from being swept.
+func (s BankService) SweepEscrow(ctx context.Context, limit int) (bank.SweepReport, error) {
+	var ids []int64
+
+	expiredAccountsQuery :=
 		`
-		SELECT COALESCE(SUM(balance), 0) AS expired_value_sum
+		SELECT DISTINCT id
 		FROM escrow
-		WHERE id = $1
-		AND created_at < $2
+		WHERE expires_at <= $1
+		  AND status_id IN ($2, $3)
+		LIMIT $4
 		`
 
-	deleteExpiredQuery :=
+	now := time.Now().UTC()
+
+	if err := s.db.SelectContext(ctx, &ids, expiredAccountsQuery, now, AuthorizationOpen, AuthorizationLocked, limit); err != nil {
+		return bank.SweepReport{}, fmt.Errorf("failed to fetch accounts with expired escrow: %w", err)
+	}
+
+	report := bank.SweepReport{Swept: types.NewFIL(new(big.Int))}
+
+	var errs []error
+	for _, id := range ids {
+		var refund bank.RefundModel
+
+		err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+			account, err := getAccountByID(ctx, id, tx)
+			if err != nil {
+				return fmt.Errorf("failed to fetch account: %w", err)
+			}
+
+			refund, err = s.refundExpired(ctx, tx, account, now)
+
+			return err
+		})
+		if err != nil {
+			// Another replica swept the account, or its client refunded it, in the meantime.
+			if errors.Is(err, bank.ErrNothingToRefund) {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("account %d: %w", id, err))
+
+			continue
+		}
+
+		report.Accounts++
+		report.Swept.Int.Add(report.Swept.Int, refund.Expired.Int)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// refundExpired moves the escrow of an account that expired by the given time back to its balance.
+// The expired authorizations are summed from the rows it marks refunded, so concurrent refunds of the
+// same account never return the same authorization twice.
+func (s BankService) refundExpired(ctx context.Context, tx fidl.Queryable, account *Account, now time.Time) (bank.RefundModel, error) {
+	var expired []types.FIL
+	var balance types.FIL
+	var escrow types.FIL
+
+	refundExpiredQuery :=
 		`
-		DELETE FROM escrow
-		WHERE id = $1
-		AND created_at < $2
+		UPDATE escrow
+			SET status_id = $3,
+				updated_at = now() at time zone 'utc'
+			WHERE id = $1
+			AND expires_at <= $2
+			AND status_id IN ($4, $5)
+			RETURNING balance
 		`
 
 	updateBalancesQuery :=
@@ -44,55 +124,51 @@ func (s BankService) Refund(address string) (bank.RefundModel, error) {
 	// nolint:goconst
 	transactionQuery :=
 		`
-		INSERT INTO transactions (transaction_id, source, destination, value, status_id)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
 		`
 
-	err := Transaction(s.db, func(tx fidl.Queryable) error {
-		account, err := getAccountByAddress(address, tx)
-		if err != nil {
-			return fmt.Errorf("failed to fetch account: %w", err)
-		}
-
-		cfgDeadline, err := time.ParseDuration(s.cfg.EscrowDeadline)
-		if err != nil {
-			return fmt.Errorf("failed to parse escrow deadline from config: %w", err)
-		}
-
-		args := []any{account.ID, time.Now().UTC().Add(-cfgDeadline)}
-		if err := tx.QueryRow(expiredQuery, args...).Scan(&expiredSum); err != nil {
-			return fmt.Errorf("failed to get expired balance: %w", err)
-		}
+	args := []any{account.ID, now, AuthorizationRefunded, AuthorizationOpen, AuthorizationLocked}
+	if err := tx.SelectContext(ctx, &expired, refundExpiredQuery, args...); err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to refund expired authorizations: %w", err)
+	}
 
-		if expiredSum.Sign() == 0 {
-			return bank.ErrNothingToRefund
-		}
+	expiredSum := types.NewFIL(new(big.Int))
+	for _, e := range expired {
+		expiredSum.Int.Add(expiredSum.Int, e.Int)
+	}
 
-		if _, err := tx.Exec(deleteExpiredQuery, args...); err != nil {
-			return fmt.Errorf("failed to delete expired authorizations: %w", err)
-		}
+	if expiredSum.Sign() == 0 {
+		return bank.RefundModel{}, bank.ErrNothingToRefund
+	}
 
-		args = []any{account.ID, expiredSum.Int.String()}
-		if err := tx.QueryRow(updateBalancesQuery, args...).Scan(&balance, &escrow); err != nil {
-			return fmt.Errorf("failed to update balances: %w", err)
-		}
+	args = []any{account.ID, expiredSum.Int.String()}
+	if err := tx.QueryRowContext(ctx, updateBalancesQuery, args...).Scan(&balance, &escrow); err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to update balances: %w", err)
+	}
 
-		transactionID, err := uuid.NewV7()
-		if err != nil {
-			return fmt.Errorf("failed to generate v7 uuid: %w", err)
-		}
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
 
-		args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, expiredSum.Int.String(), TransactionCompleted}
-		if _, err := tx.Exec(transactionQuery, args...); err != nil {
-			return fmt.Errorf("failed to register transaction during refund: %w", err)
-		}
+	args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, expiredSum.Int.String(), TransactionCompleted, account.Address, "", TransactionRefund}
+	if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to register transaction during refund: %w", err)
+	}
 
-		return nil
-	})
+	err = postJournal(ctx, tx, transactionID.String(),
+		debit(account.Address, LedgerEscrow, expiredSum.Int),
+		credit(account.Address, LedgerBalance, expiredSum.Int),
+	)
 	if err != nil {
 		return bank.RefundModel{}, err
 	}
 
+	if err := checkLedger(ctx, tx, account.Address); err != nil {
+		return bank.RefundModel{}, err
+	}
+
 	return bank.RefundModel{
 		Expired:   expiredSum,
 		Available: balance,
@@ -1,20 +1,29 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
 	"github.com/subvisual/fidl/types"
 )
 
-func (s BankService) RegisterProxy(spid string, walletAddress string, price types.FIL) error {
+// RegisterProxy registers a storage provider, or updates the id and price of one already registered.
+// Every price it is registered with is recorded in its price history. Registering again reactivates a
+// storage provider once its deregistration is finalized, but not while its channels are still closing.
+func (s BankService) RegisterProxy(ctx context.Context, spid string, walletAddress string, price types.FIL) error {
 	var accountID int64
 
 	accountQuery :=
 		`
 		INSERT INTO accounts (wallet_address, account_type)
 		VALUES ($1, $2)
-		ON CONFLICT (wallet_address) DO NOTHING
+		ON CONFLICT (wallet_address) DO UPDATE
+			SET updated_at = now() at time zone 'utc'
+			WHERE accounts.account_type = $2
 		RETURNING id
 		`
 
@@ -22,30 +31,83 @@ func (s BankService) RegisterProxy(spid string, walletAddress string, price type
 		`
 		INSERT INTO balances (id)
 		VALUES ($1)
+		ON CONFLICT (id) DO NOTHING
+		`
+
+	currentQuery :=
+		`
+		SELECT price, status_id FROM storage_providers
+		WHERE id = $1
+		FOR UPDATE
 		`
 
 	spQuery :=
 		`
-		INSERT INTO storage_providers (id, sp_id, price)
-		VALUES ($1, $2, $3)
+		INSERT INTO storage_providers (id, sp_id, price, status_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE
+			SET sp_id = EXCLUDED.sp_id,
+				price = EXCLUDED.price,
+				status_id = $4,
+				payout_address = NULL,
+				deregistered_at = NULL,
+				updated_at = now() at time zone 'utc'
 		`
 
-	err := Transaction(s.db, func(tx fidl.Queryable) error {
+	priceHistoryQuery :=
+		`
+		INSERT INTO storage_provider_prices (storage_provider_id, price)
+		VALUES ($1, $2)
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
 		args := []any{walletAddress, StorageProvider}
-		if err := tx.QueryRow(accountQuery, args...).Scan(&accountID); err != nil {
+		if err := tx.QueryRowContext(ctx, accountQuery, args...).Scan(&accountID); err != nil {
+			// The wallet already has an account, of a client.
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrOperationNotAllowed
+			}
+
 			return fmt.Errorf("failed to add account entry: %w", err)
 		}
 
-		if _, err := tx.Exec(balancesQuery, accountID); err != nil {
+		account, err := getAccountByAddress(ctx, walletAddress, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sp account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationRegister); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, balancesQuery, accountID); err != nil {
 			return fmt.Errorf("failed to add balances entry: %w", err)
 		}
 
-		args = []any{accountID, spid, price.Int.String()}
-		if _, err := tx.Exec(spQuery, args...); err != nil {
+		var current types.FIL
+		var status StorageProviderStatus
+		err = tx.QueryRowContext(ctx, currentQuery, accountID).Scan(&current, &status)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to fetch storage provider: %w", err)
+		}
+
+		if status == StorageProviderDeregistering {
+			return bank.ErrProxyNotActive
+		}
+
+		args = []any{accountID, spid, price.Int.String(), StorageProviderActive}
+		if _, err := tx.ExecContext(ctx, spQuery, args...); err != nil {
 			return fmt.Errorf("failed to add storage provider entry: %w", err)
 		}
 
-		return nil
+		if current.Int == nil || current.Cmp(price.Int) != 0 {
+			args = []any{accountID, price.Int.String()}
+			if _, err := tx.ExecContext(ctx, priceHistoryQuery, args...); err != nil {
+				return fmt.Errorf("failed to record storage provider price: %w", err)
+			}
+		}
+
+		return respond(ctx, tx, nil)
 	})
 	if err != nil {
 		return err
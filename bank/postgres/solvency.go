@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type LiabilityEntry struct {
+	Address string    `db:"wallet_address"`
+	Amount  types.FIL `db:"amount"`
+}
+
+// Liabilities lists what the bank owes each wallet, ordered by address. Withdrawals count until they are
+// paid out, and held deposits until they are credited or returned, as their funds are still in the bank's
+// wallet. A held deposit is owed to its sender.
+func (s BankService) Liabilities(ctx context.Context) ([]bank.Liability, error) {
+	var entries []LiabilityEntry
+
+	query :=
+		`
+		SELECT l.wallet_address, SUM(l.amount) AS amount
+		FROM (
+			SELECT a.wallet_address, b.balance + b.escrow AS amount
+			FROM accounts a
+			JOIN balances b ON b.id = a.id
+			UNION ALL
+			SELECT wallet_address, value
+			FROM withdrawals
+			WHERE status_id IN ($1, $2)
+			UNION ALL
+			SELECT sender, value
+			FROM held_deposits
+			WHERE status_id = $3
+		) l
+		GROUP BY l.wallet_address
+		ORDER BY l.wallet_address COLLATE "C"
+		`
+
+	if err := s.db.SelectContext(ctx, &entries, query, TransactionPending, TransactionSubmitted, HeldDepositHeld); err != nil {
+		return nil, fmt.Errorf("failed to fetch liabilities: %w", err)
+	}
+
+	liabilities := make([]bank.Liability, 0, len(entries))
+	for _, e := range entries {
+		liabilities = append(liabilities, bank.Liability{Address: e.Address, Amount: e.Amount})
+	}
+
+	return liabilities, nil
+}
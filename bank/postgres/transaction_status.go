@@ -5,6 +5,8 @@ type TransactionStatus int8
 const (
 	TransactionPending TransactionStatus = iota + 1
 	TransactionCompleted
+	TransactionSubmitted
+	TransactionReversed
 )
 
 func (a TransactionStatus) String() string {
@@ -13,6 +15,10 @@ func (a TransactionStatus) String() string {
 		return "Pending"
 	case TransactionCompleted:
 		return "Completed"
+	case TransactionSubmitted:
+		return "Submitted"
+	case TransactionReversed:
+		return "Reversed"
 	default:
 		return "Unknown" // nolint:goconst
 	}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type HistoryEntry struct {
+	ID            int64             `db:"id"`
+	TransactionID string            `db:"transaction_id"`
+	Type          TransactionType   `db:"type_id"`
+	Counterpart   string            `db:"counterpart"`
+	Value         types.FIL         `db:"value"`
+	Status        TransactionStatus `db:"status_id"`
+	CreatedAt     time.Time         `db:"created_at"`
+}
+
+// Transactions lists the transactions of an account, newest first. The counterpart of a transaction
+// is the other account involved in it, as seen from the given address.
+func (s BankService) Transactions(ctx context.Context, address string, params bank.TransactionsParams) ([]bank.Transaction, error) {
+	var entries []HistoryEntry
+
+	query :=
+		`
+		SELECT id, transaction_id, type_id, value, status_id, created_at,
+			CASE WHEN wallet_address = $1 THEN COALESCE(counterpart, '') ELSE wallet_address END AS counterpart
+		FROM transactions
+		WHERE (wallet_address = $1 OR counterpart = $1)
+		  AND type_id IS NOT NULL
+		  AND ($2::timestamp IS NULL OR created_at >= $2)
+		  AND ($3::timestamp IS NULL OR created_at < $3)
+		  AND ($4::integer IS NULL OR type_id = $4)
+		  AND ($5::bigint IS NULL OR id < $5)
+		ORDER BY id DESC
+		LIMIT $6
+		`
+
+	var from, to sql.NullTime
+	if !params.From.IsZero() {
+		from = sql.NullTime{Time: params.From.UTC(), Valid: true}
+	}
+
+	if !params.To.IsZero() {
+		to = sql.NullTime{Time: params.To.UTC(), Valid: true}
+	}
+
+	var kind sql.NullInt16
+	if params.Type != "" {
+		t, ok := parseTransactionType(params.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown transaction type: %s", params.Type)
+		}
+
+		kind = sql.NullInt16{Int16: int16(t), Valid: true}
+	}
+
+	var cursor sql.NullInt64
+	if params.Cursor > 0 {
+		cursor = sql.NullInt64{Int64: params.Cursor, Valid: true}
+	}
+
+	args := []any{address, from, to, kind, cursor, params.Limit}
+	if err := s.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+
+	transactions := make([]bank.Transaction, 0, len(entries))
+	for _, e := range entries {
+		transactions = append(transactions, bank.Transaction{
+			ID:            e.ID,
+			TransactionID: e.TransactionID,
+			Type:          e.Type.String(),
+			Counterpart:   e.Counterpart,
+			Amount:        e.Value,
+			Status:        e.Status.String(),
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+
+	return transactions, nil
+}
@@ -1,26 +1,34 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/subvisual/fidl/bank"
 )
 
-func (s BankService) ValidateBlockchainTransaction(hash string) (bool, error) {
+// ValidateBlockchainTransaction checks that a transfer was neither credited nor held already.
+func (s BankService) ValidateBlockchainTransaction(ctx context.Context, hash string) (bool, error) {
 	query := `
 		SELECT EXISTS (
 			SELECT 1
 			FROM transactions
 			WHERE transaction_id = $1
+		) OR EXISTS (
+			SELECT 1
+			FROM held_deposits
+			WHERE hash = lower($1)
 		)
 	`
 
 	var exists bool
-	err := s.db.QueryRow(query, hash).Scan(&exists)
+	err := s.db.QueryRowContext(ctx, query, hash).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to validate transaction: %w", err)
 	}
 
 	if exists {
-		return false, fmt.Errorf("transaction already registered")
+		return false, bank.ErrTransactionExists
 	}
 
 	return true, nil
@@ -1,6 +1,9 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,65 +13,74 @@ import (
 	"github.com/subvisual/fidl/types"
 )
 
-func (s BankService) Verify(address string, uuid uuid.UUID, amount types.FIL) error {
+// Verify locks an open authorization for a retrieval by its storage provider, and returns the price the
+// retrieval is charged at: the provider's price when the authorization was made.
+func (s BankService) Verify(ctx context.Context, address string, uuid uuid.UUID) (types.FIL, error) {
+	var auth Authorization
+
 	getAuthQuery :=
 		`
 		SELECT *
 		FROM escrow
 		WHERE uuid = $1
 		  AND proxy = $2
-		  AND balance >= $3
-		  AND created_at >= $4
+		  AND balance >= price
+		  AND expires_at > $3
+		  AND status_id IN ($4, $5)
 		`
 
 	updateAuthQuery :=
 		`
 		UPDATE escrow
-  			SET status_id = $5,
+  			SET status_id = $3,
 				updated_at = now() at time zone 'utc'
   			WHERE uuid = $1
 		  	  AND proxy = $2
-		  	  AND balance >= $3
-		  	  AND created_at >= $4
-			  AND status_id = 1
+			  AND status_id = $4
 		`
 
-	err := Transaction(s.db, func(tx fidl.Queryable) error {
-		account, err := getAccountByAddress(address, tx)
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
 		if err != nil {
 			return fmt.Errorf("failed to fetch account: %w", err)
 		}
 
+		if err := s.usable(account, bank.OperationVerify); err != nil {
+			return err
+		}
+
 		if account.Type != StorageProvider {
 			return bank.ErrOperationNotAllowed
 		}
 
-		var auth Authorization
+		args := []any{uuid, address, time.Now().UTC(), AuthorizationOpen, AuthorizationLocked}
+		if err := tx.GetContext(ctx, &auth, getAuthQuery, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthNotFound
+			}
 
-		cfgDeadline, err := time.ParseDuration(s.cfg.EscrowDeadline)
-		if err != nil {
-			return fmt.Errorf("failed to parse escrow deadline from config: %w", err)
-		}
-
-		args := []any{uuid, address, amount.Int.String(), time.Now().UTC().Add(-cfgDeadline)}
-		if err := tx.Get(&auth, getAuthQuery, args...); err != nil {
-			return bank.ErrAuthNotFound
+			return fmt.Errorf("failed to fetch authorization: %w", err)
 		}
 
 		if auth.Status == AuthorizationLocked {
 			return bank.ErrAuthLocked
 		}
 
-		args = []any{uuid, address, amount.Int.String(), time.Now().UTC().Add(-cfgDeadline), AuthorizationLocked}
-		if _, err := tx.Exec(updateAuthQuery, args...); err != nil {
+		// A concurrent verify may have locked the authorization since it was read.
+		args = []any{uuid, address, AuthorizationLocked, AuthorizationOpen}
+		if err := execOne(ctx, tx, updateAuthQuery, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthLocked
+			}
+
 			return fmt.Errorf("failed to update authorization status: %w", err)
 		}
 
-		return nil
+		return respond(ctx, tx, auth.Price)
 	})
 	if err != nil {
-		return err
+		return types.FIL{}, err
 	}
 
-	return nil
+	return auth.Price, nil
 }
@@ -0,0 +1,72 @@
+package bank
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Pruner periodically deletes the request nonces, idempotency keys and rate limit buckets that expired,
+// which keeps the middlewares from having to clean up on every request.
+type Pruner struct {
+	BankService Service
+	RateLimiter RateLimiter
+	Interval    time.Duration
+	Log         *zap.Logger
+}
+
+func NewPruner(bankService Service, rateLimiter RateLimiter, interval time.Duration, log *zap.Logger) *Pruner {
+	return &Pruner{
+		BankService: bankService,
+		RateLimiter: rateLimiter,
+		Interval:    interval,
+		Log:         log,
+	}
+}
+
+func (p *Pruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Prune(ctx)
+		}
+	}
+}
+
+// Prune deletes everything that expired before now.
+func (p *Pruner) Prune(ctx context.Context) {
+	now := time.Now().UTC()
+
+	nonces, err := p.BankService.PruneNonces(ctx, now)
+	if err != nil {
+		p.Log.Error("failed to prune nonces", zap.Error(err))
+	}
+
+	if nonces > 0 {
+		p.Log.Debug("pruned expired nonces", zap.Int("nonces", nonces))
+	}
+
+	keys, err := p.BankService.PruneIdempotencyKeys(ctx, now)
+	if err != nil {
+		p.Log.Error("failed to prune idempotency keys", zap.Error(err))
+	}
+
+	if keys > 0 {
+		p.Log.Debug("pruned expired idempotency keys", zap.Int("keys", keys))
+	}
+
+	buckets, err := p.RateLimiter.PruneBuckets(ctx, now)
+	if err != nil {
+		p.Log.Error("failed to prune rate limit buckets", zap.Error(err))
+	}
+
+	if buckets > 0 {
+		p.Log.Debug("pruned full rate limit buckets", zap.Int("buckets", buckets))
+	}
+}
@@ -0,0 +1,105 @@
+package bank
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit lets a caller make Requests requests every Per, in bursts of up to Requests. It is a token
+// bucket that holds Requests tokens and gets one back every Per / Requests.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// RouteLimits are the rate limits of a route, by signing address and by IP. A zero limit is not enforced.
+type RouteLimits struct {
+	Address RateLimit
+	IP      RateLimit
+}
+
+// RateLimiter keeps the token buckets of rate limited callers. TakeToken takes a token from the bucket of
+// a key, filling a new bucket to the limit, and returns how long to wait for one when the bucket is empty.
+// PruneBuckets deletes the buckets that were full again before the given time, which are no different from
+// new ones.
+type RateLimiter interface {
+	TakeToken(ctx context.Context, key string, limit RateLimit) (time.Duration, error)
+	PruneBuckets(ctx context.Context, before time.Time) (int, error)
+}
+
+func (l RateLimit) interval() time.Duration {
+	return l.Per / time.Duration(l.Requests)
+}
+
+// Take refills a bucket left with tokens elapsed ago and takes a token from it. It returns the tokens left
+// and, when there was no token to take, how long until there is one.
+func (l RateLimit) Take(tokens float64, elapsed time.Duration) (float64, time.Duration) {
+	if elapsed > 0 {
+		tokens = math.Min(float64(l.Requests), tokens+float64(elapsed)/float64(l.interval()))
+	}
+
+	if tokens >= 1 {
+		return tokens - 1, 0
+	}
+
+	return tokens, time.Duration((1 - tokens) * float64(l.interval()))
+}
+
+// Full returns how long a bucket left with tokens takes to fill up again, after which it can be forgotten.
+func (l RateLimit) Full(tokens float64) time.Duration {
+	return time.Duration((float64(l.Requests) - tokens) * float64(l.interval()))
+}
+
+// ParseRateLimit parses a limit written as requests/period, e.g. 10/1m. An empty limit is not enforced.
+func ParseRateLimit(value string) (RateLimit, error) {
+	if value == "" {
+		return RateLimit{}, nil
+	}
+
+	requests, per, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit, expected requests/period: %s", value)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid number of requests in rate limit: %s", value)
+	}
+
+	period, err := time.ParseDuration(per)
+	if err != nil || period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid period in rate limit: %s", value)
+	}
+
+	return RateLimit{Requests: n, Per: period}, nil
+}
+
+// ParseRateLimits parses the limits of the configured routes, which must be named as in Routes.
+func ParseRateLimits(cfg RateLimits) (map[string]RouteLimits, error) {
+	limits := make(map[string]RouteLimits, len(cfg.Routes))
+
+	for route, routeCfg := range cfg.Routes {
+		if !slices.Contains(routeNames, route) {
+			return nil, fmt.Errorf("unknown rate limited route: %s", route)
+		}
+
+		address, err := ParseRateLimit(routeCfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", route, err)
+		}
+
+		ip, err := ParseRateLimit(routeCfg.IP)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", route, err)
+		}
+
+		limits[route] = RouteLimits{Address: address, IP: ip}
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,161 @@
+package bank
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		value    string
+		expected RateLimit
+		valid    bool
+	}{
+		{"", RateLimit{}, true},
+		{"10/1m", RateLimit{Requests: 10, Per: time.Minute}, true},
+		{"1/500ms", RateLimit{Requests: 1, Per: 500 * time.Millisecond}, true},
+		{"10", RateLimit{}, false},
+		{"0/1m", RateLimit{}, false},
+		{"-1/1m", RateLimit{}, false},
+		{"10/0s", RateLimit{}, false},
+		{"10/minute", RateLimit{}, false},
+	}
+
+	for _, test := range tests {
+		limit, err := ParseRateLimit(test.value)
+		if test.valid != (err == nil) {
+			t.Errorf("rate limit %q: expected valid to be %t, got error %v", test.value, test.valid, err)
+			continue
+		}
+
+		if limit != test.expected {
+			t.Errorf("rate limit %q: expected %+v, got %+v", test.value, test.expected, limit)
+		}
+	}
+}
+
+func TestParseRateLimits(t *testing.T) {
+	t.Parallel()
+
+	limits, err := ParseRateLimits(RateLimits{Routes: map[string]RateLimitConfig{"deposit": {Address: "5/1m", IP: "20/1m"}}})
+	if err != nil {
+		t.Fatalf("expected the limits of a route to parse, got error %v", err)
+	}
+
+	expected := RouteLimits{Address: RateLimit{Requests: 5, Per: time.Minute}, IP: RateLimit{Requests: 20, Per: time.Minute}}
+	if limits["deposit"] != expected {
+		t.Errorf("expected %+v, got %+v", expected, limits["deposit"])
+	}
+
+	if _, err := ParseRateLimits(RateLimits{Routes: map[string]RateLimitConfig{"deposits": {Address: "5/1m"}}}); err == nil {
+		t.Errorf("expected a route that does not exist to be refused")
+	}
+}
+
+func TestRateLimitTake(t *testing.T) {
+	t.Parallel()
+
+	limit := RateLimit{Requests: 2, Per: time.Second}
+
+	var tests = []struct {
+		tokens   float64
+		elapsed  time.Duration
+		expected float64
+		wait     time.Duration
+	}{
+		{2, 0, 1, 0},
+		{1, 0, 0, 0},
+		{0, 0, 0, 500 * time.Millisecond},
+		{0, 250 * time.Millisecond, 0.5, 250 * time.Millisecond},
+		{0, 500 * time.Millisecond, 0, 0},
+		{0, time.Hour, 1, 0},
+		{1, -time.Second, 0, 0},
+	}
+
+	for _, test := range tests {
+		tokens, wait := limit.Take(test.tokens, test.elapsed)
+		if tokens != test.expected || wait != test.wait {
+			t.Errorf("take from %v tokens after %s: expected %v tokens and %s, got %v and %s", test.tokens, test.elapsed, test.expected, test.wait, tokens, wait)
+		}
+	}
+}
+
+type stubLimiter struct {
+	wait time.Duration
+	keys []string
+}
+
+func (l *stubLimiter) TakeToken(_ context.Context, key string, _ RateLimit) (time.Duration, error) {
+	l.keys = append(l.keys, key)
+
+	return l.wait, nil
+}
+
+func (l *stubLimiter) PruneBuckets(_ context.Context, _ time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestIPRateLimitCtx(t *testing.T) {
+	t.Parallel()
+
+	limiter := &stubLimiter{}
+	s := &Server{
+		RateLimits:  map[string]RouteLimits{"deposit": {IP: RateLimit{Requests: 1, Per: time.Minute}}},
+		RateLimiter: limiter,
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	serve := func(route string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/deposit", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		s.IPRateLimitCtx(route)(ok).ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	if rec := serve("deposit"); rec.Code != http.StatusOK {
+		t.Fatalf("expected the request to go through, got %d", rec.Code)
+	}
+
+	if len(limiter.keys) != 1 || limiter.keys[0] != "ip:deposit:192.0.2.1" {
+		t.Fatalf("expected a token to be taken for the IP, got %v", limiter.keys)
+	}
+
+	if rec := serve("balance"); rec.Code != http.StatusOK || len(limiter.keys) != 1 {
+		t.Fatalf("expected a route without limits to go through untouched, got %d", rec.Code)
+	}
+
+	limiter.wait = 1500 * time.Millisecond
+
+	rec := serve("deposit")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429, got %d", rec.Code)
+	}
+
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Errorf("expected Retry-After to round up to 2 seconds, got %q", retryAfter)
+	}
+
+	var body struct {
+		Status string `json:"status"`
+		Data   struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+
+	if body.Status != "fail" || body.Data.RetryAfter != 2 {
+		t.Errorf("expected a jsend fail with retry_after 2, got %+v", body)
+	}
+}
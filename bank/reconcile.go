@@ -0,0 +1,185 @@
+package bank
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/types"
+)
+
+type Discrepancy string
+
+const (
+	// MissingFromLedger is a transfer of the bank's wallet that the bank has no record of.
+	MissingFromLedger Discrepancy = "missing_from_ledger"
+	// MissingOnChain is a deposit or a withdrawal whose transfer is not on chain.
+	MissingOnChain Discrepancy = "missing_on_chain"
+	// Duplicate is a transfer that the bank recorded more than once.
+	Duplicate Discrepancy = "duplicate"
+	// Mismatch is a transfer that does not move what the bank recorded.
+	Mismatch Discrepancy = "mismatch"
+)
+
+type ReconcileIssue struct {
+	Discrepancy Discrepancy          `json:"discrepancy"`
+	Hash        string               `json:"hash"`
+	Reason      string               `json:"reason,omitempty"`
+	Transfer    *blockchain.Transfer `json:"transfer,omitempty"`
+	Entries     []ChainEntry         `json:"entries,omitempty"`
+}
+
+type ReconcileReport struct {
+	From      uint64           `json:"from"`
+	To        uint64           `json:"to"`
+	Transfers int              `json:"transfers"`
+	Entries   int              `json:"entries"`
+	Matched   int              `json:"matched"`
+	InFlight  int              `json:"in_flight"`
+	Issues    []ReconcileIssue `json:"issues"`
+}
+
+// ReconcileParams are the transfers found on chain and the entries of the bank to match them with. Transfers
+// may include transfers outside the block range, looked up by the hash of an entry, in which case the entry
+// is left out of the report.
+type ReconcileParams struct {
+	From      uint64
+	To        uint64
+	Transfers []blockchain.Transfer
+	Entries   []ChainEntry
+}
+
+// Reconcile matches the deposits and withdrawals of the bank with the transfers of its wallet in a block
+// range, by hash. A withdrawal that is signed but not on chain yet is in flight, not missing.
+func Reconcile(params ReconcileParams) ReconcileReport {
+	report := ReconcileReport{From: params.From, To: params.To, Issues: []ReconcileIssue{}}
+
+	transfers := make(map[string]blockchain.Transfer, len(params.Transfers))
+	for _, t := range params.Transfers {
+		hash := strings.ToLower(t.Hash)
+		transfers[hash] = t
+
+		if inRange(params, t) {
+			report.Transfers++
+		}
+	}
+
+	entries := make(map[string][]ChainEntry, len(params.Entries))
+	hashes := make([]string, 0, len(params.Entries))
+	for _, e := range params.Entries {
+		hash := strings.ToLower(e.Hash)
+		if _, ok := entries[hash]; !ok {
+			hashes = append(hashes, hash)
+		}
+
+		entries[hash] = append(entries[hash], e)
+	}
+
+	for _, hash := range hashes {
+		recorded := entries[hash]
+
+		transfer, ok := transfers[hash]
+		if ok && !inRange(params, transfer) {
+			continue
+		}
+
+		report.Entries += len(recorded)
+
+		if len(recorded) > 1 {
+			report.Issues = append(report.Issues, issue(Duplicate, hash, "recorded more than once", transfer, ok, recorded))
+			continue
+		}
+
+		entry := recorded[0]
+
+		if !ok {
+			if entry.Type == TransactionWithdraw && entry.Status == TransactionSubmitted {
+				report.InFlight++
+				continue
+			}
+
+			report.Issues = append(report.Issues, issue(MissingOnChain, hash, "", transfer, ok, recorded))
+			continue
+		}
+
+		if reason := mismatch(entry, transfer); reason != "" {
+			report.Issues = append(report.Issues, issue(Mismatch, hash, reason, transfer, ok, recorded))
+			continue
+		}
+
+		if entry.Status == TransactionSubmitted {
+			report.InFlight++
+			continue
+		}
+
+		report.Matched++
+	}
+
+	for _, t := range params.Transfers {
+		hash := strings.ToLower(t.Hash)
+		if _, ok := entries[hash]; ok || !inRange(params, t) || t.Status != blockchain.TransactionSucceeded {
+			continue
+		}
+
+		report.Issues = append(report.Issues, issue(MissingFromLedger, hash, "", t, true, nil))
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return issueBlock(report.Issues[i]) < issueBlock(report.Issues[j])
+	})
+
+	return report
+}
+
+func inRange(params ReconcileParams, t blockchain.Transfer) bool {
+	return t.Block >= params.From && t.Block <= params.To
+}
+
+func issue(discrepancy Discrepancy, hash string, reason string, transfer blockchain.Transfer, found bool, entries []ChainEntry) ReconcileIssue {
+	res := ReconcileIssue{Discrepancy: discrepancy, Hash: hash, Reason: reason, Entries: entries}
+	if found {
+		res.Transfer = &transfer
+	}
+
+	return res
+}
+
+func issueBlock(i ReconcileIssue) uint64 {
+	if i.Transfer == nil {
+		return 0
+	}
+
+	return i.Transfer.Block
+}
+
+// mismatch returns why a transfer does not match an entry, if it does not.
+func mismatch(entry ChainEntry, transfer blockchain.Transfer) string {
+	deposit := entry.Type == TransactionDeposit
+
+	switch {
+	case deposit && !transfer.Incoming:
+		return "deposit is a transfer out of the wallet"
+	case !deposit && transfer.Incoming:
+		return "withdrawal is a transfer into the wallet"
+	case entry.Status == TransactionReversed && transfer.Status == blockchain.TransactionSucceeded:
+		return "reversed withdrawal was paid out"
+	case entry.Status == TransactionCompleted && transfer.Status != blockchain.TransactionSucceeded:
+		return "transfer did not succeed"
+	case entry.Status == TransactionReversed:
+		return ""
+	case entry.Value.Int == nil || transfer.Value.Int == nil || entry.Value.Int.Cmp(transfer.Value.Int) != 0:
+		return "value differs"
+	}
+
+	counterpart := transfer.To
+	if deposit {
+		counterpart = transfer.From
+	}
+
+	// Only addresses that map to an Ethereum address can be compared, which f1 and f3 addresses do not.
+	if address, _, err := types.ParseAddress(entry.Counterpart); err == nil && !strings.EqualFold(address, counterpart) {
+		return "counterpart differs"
+	}
+
+	return ""
+}
@@ -0,0 +1,136 @@
+package bank
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/types"
+)
+
+const (
+	bankWallet = "0x1111111111111111111111111111111111111111"
+	depositor  = "0x2222222222222222222222222222222222222222"
+	payee      = "0x3333333333333333333333333333333333333333"
+)
+
+func fil(amount int64) types.FIL {
+	return types.NewFIL(big.NewInt(amount))
+}
+
+func deposited(hash string, amount int64, block uint64) blockchain.Transfer {
+	return blockchain.Transfer{Hash: hash, From: depositor, To: bankWallet, Value: fil(amount), Incoming: true, Block: block, Status: blockchain.TransactionSucceeded}
+}
+
+func paid(hash string, amount int64, block uint64) blockchain.Transfer {
+	return blockchain.Transfer{Hash: hash, From: bankWallet, To: payee, Value: fil(amount), Block: block, Status: blockchain.TransactionSucceeded}
+}
+
+func depositEntry(hash string, amount int64) ChainEntry {
+	return ChainEntry{Type: TransactionDeposit, ID: hash, Hash: hash, Counterpart: depositor, Value: fil(amount), Status: TransactionCompleted}
+}
+
+func withdrawEntry(hash string, amount int64, status TransactionStatus) ChainEntry {
+	return ChainEntry{Type: TransactionWithdraw, ID: "w-" + hash, Hash: hash, Counterpart: payee, Value: fil(amount), Status: status}
+}
+
+func TestReconcile(t *testing.T) {
+	t.Parallel()
+
+	failed := paid("0xa7", 40, 12)
+	failed.Status = blockchain.TransactionFailed
+
+	unrecordedFailure := deposited("0xa9", 5, 13)
+	unrecordedFailure.Status = blockchain.TransactionFailed
+
+	report := Reconcile(ReconcileParams{
+		From: 10,
+		To:   20,
+		Transfers: []blockchain.Transfer{
+			deposited("0xA1", 100, 10),
+			paid("0xa2", 30, 11),
+			deposited("0xa3", 50, 12),
+			deposited("0xa4", 70, 14),
+			paid("0xa5", 25, 15),
+			paid("0xa6", 10, 16),
+			failed,
+			deposited("0xa8", 20, 18),
+			unrecordedFailure,
+			// Looked up by the hash of an entry recorded in the range, but on chain before it.
+			deposited("0xb1", 60, 9),
+		},
+		Entries: []ChainEntry{
+			depositEntry("0xa1", 100),
+			withdrawEntry("0xa2", 30, TransactionCompleted),
+			depositEntry("0xa3", 55),
+			depositEntry("0xa4", 70),
+			depositEntry("0xa4", 70),
+			withdrawEntry("0xa5", 25, TransactionSubmitted),
+			withdrawEntry("0xa6", 10, TransactionReversed),
+			withdrawEntry("0xa7", 40, TransactionReversed),
+			depositEntry("0xb1", 60),
+			depositEntry("0xb2", 15),
+			withdrawEntry("0xb3", 35, TransactionSubmitted),
+		},
+	})
+
+	expected := []struct {
+		discrepancy Discrepancy
+		hash        string
+		reason      string
+	}{
+		{MissingOnChain, "0xb2", ""},
+		{Mismatch, "0xa3", "value differs"},
+		{Duplicate, "0xa4", "recorded more than once"},
+		{Mismatch, "0xa6", "reversed withdrawal was paid out"},
+		{MissingFromLedger, "0xa8", ""},
+	}
+
+	if len(report.Issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %+v", len(expected), report.Issues)
+	}
+
+	for i, e := range expected {
+		got := report.Issues[i]
+		if got.Discrepancy != e.discrepancy || got.Hash != e.hash || got.Reason != e.reason {
+			t.Errorf("issue %d: expected %s of %s (%q), got %s of %s (%q)", i, e.discrepancy, e.hash, e.reason, got.Discrepancy, got.Hash, got.Reason)
+		}
+	}
+
+	if report.Transfers != 9 || report.Entries != 10 || report.Matched != 3 || report.InFlight != 2 {
+		t.Errorf("expected 9 transfers, 10 entries, 3 matched and 2 in flight, got %+v", report)
+	}
+}
+
+func TestReconcileMismatch(t *testing.T) {
+	t.Parallel()
+
+	failed := deposited("0xa1", 100, 10)
+	failed.Status = blockchain.TransactionFailed
+
+	var tests = []struct {
+		entry    ChainEntry
+		transfer blockchain.Transfer
+		reason   string
+	}{
+		{depositEntry("0xa1", 100), deposited("0xa1", 100, 10), ""},
+		{depositEntry("0xa1", 100), paid("0xa1", 100, 10), "deposit is a transfer out of the wallet"},
+		{withdrawEntry("0xa1", 100, TransactionCompleted), deposited("0xa1", 100, 10), "withdrawal is a transfer into the wallet"},
+		{depositEntry("0xa1", 100), failed, "transfer did not succeed"},
+		{depositEntry("0xa1", 100), deposited("0xa1", 99, 10), "value differs"},
+		{withdrawEntry("0xa1", 100, TransactionCompleted), paid("0xa1", 100, 10), ""},
+		{ChainEntry{Type: TransactionDeposit, Hash: "0xa1", Counterpart: payee, Value: fil(100), Status: TransactionCompleted}, deposited("0xa1", 100, 10), "counterpart differs"},
+		// Held deposits are deposits, and their returns transfers out of the wallet.
+		{ChainEntry{Type: TransactionDeposit, Hash: "0xa1", Counterpart: depositor, Value: fil(100), Status: TransactionStatus("Held")}, deposited("0xa1", 100, 10), ""},
+		{ChainEntry{Type: TransactionReturn, Hash: "0xa1", Counterpart: payee, Value: fil(100), Status: TransactionCompleted}, paid("0xa1", 100, 10), ""},
+		{ChainEntry{Type: TransactionReturn, Hash: "0xa1", Counterpart: payee, Value: fil(100), Status: TransactionCompleted}, deposited("0xa1", 100, 10), "withdrawal is a transfer into the wallet"},
+		// An f1 address has no Ethereum address to compare with.
+		{ChainEntry{Type: TransactionDeposit, Hash: "0xa1", Counterpart: "f1abjxfbp274xpdqcpuaykwkfb43omjotacm2p3za", Value: fil(100), Status: TransactionCompleted}, deposited("0xa1", 100, 10), ""},
+	}
+
+	for i, test := range tests {
+		if reason := mismatch(test.entry, test.transfer); reason != test.reason {
+			t.Errorf("test %d: expected %q, got %q", i, test.reason, reason)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package bank
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/crypto"
+	"github.com/subvisual/fidl/types"
+	"go.uber.org/zap"
+)
+
+// Attestation states what the bank owes its accounts against what its wallet holds on chain. Root is the
+// root of a Merkle-sum tree of the liabilities, so each account can check its own amount is counted in them.
+type Attestation struct {
+	Root        []byte
+	Liabilities types.FIL
+	Reserves    types.FIL
+	Accounts    int
+	CreatedAt   time.Time
+}
+
+type SignedAttestation struct {
+	Attestation
+	Signature []byte
+}
+
+// SolvencyProof is what an account needs to check that what it is owed is counted in an attestation. The
+// salt of its leaf is only ever given to the account itself.
+type SolvencyProof struct {
+	Attestation SignedAttestation
+	Liability   Liability
+	Salt        []byte
+	Steps       []crypto.MerkleSumStep
+}
+
+type solvencySnapshot struct {
+	attestation SignedAttestation
+	tree        *crypto.MerkleSumTree
+	leaves      map[string]int
+	liabilities []Liability
+	salts       [][]byte
+}
+
+// Solvent reports whether the bank's wallet holds at least what the bank owes.
+func (a Attestation) Solvent() bool {
+	return a.Reserves.Int.Cmp(a.Liabilities.Int) >= 0
+}
+
+// Message is what the bank signs for an attestation.
+func (a Attestation) Message() []byte {
+	return []byte(fmt.Sprintf("fidl-solvency\n%s\n%s\n%s\n%d\n%d",
+		hex.EncodeToString(a.Root), a.Liabilities.Int, a.Reserves.Int, a.Accounts, a.CreatedAt.Unix()))
+}
+
+// SolvencyAuditor periodically compares the bank's liabilities with the balance of its wallet on chain and
+// signs the result with the wallet's key. The latest attestation is kept in memory to be served, with the
+// proofs of inclusion of every account.
+type SolvencyAuditor struct {
+	BankService       Service
+	BlockChainService blockchain.Service
+	Signer            types.Address
+	Key               types.KeyInfo
+	Interval          time.Duration
+	Log               *zap.Logger
+
+	mu       sync.RWMutex
+	snapshot *solvencySnapshot
+}
+
+func NewSolvencyAuditor(bankService Service, blockChainService blockchain.Service, signer types.Address, key types.KeyInfo, interval time.Duration, log *zap.Logger) *SolvencyAuditor {
+	return &SolvencyAuditor{
+		BankService:       bankService,
+		BlockChainService: blockChainService,
+		Signer:            signer,
+		Key:               key,
+		Interval:          interval,
+		Log:               log,
+	}
+}
+
+func (a *SolvencyAuditor) Run(ctx context.Context) {
+	if _, err := a.Attest(ctx); err != nil {
+		a.Log.Error("failed to attest solvency", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := a.Attest(ctx); err != nil {
+				a.Log.Error("failed to attest solvency", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Attest builds and signs a new attestation, and makes it the latest one.
+func (a *SolvencyAuditor) Attest(ctx context.Context) (SignedAttestation, error) {
+	liabilities, err := a.BankService.Liabilities(ctx)
+	if err != nil {
+		return SignedAttestation{}, fmt.Errorf("failed to fetch liabilities: %w", err)
+	}
+
+	reserves, err := a.BlockChainService.WalletBalance(ctx)
+	if err != nil {
+		return SignedAttestation{}, err
+	}
+
+	leaves := make([]crypto.MerkleSumLeaf, 0, len(liabilities))
+	indexes := make(map[string]int, len(liabilities))
+	salts := make([][]byte, 0, len(liabilities))
+	for i, l := range liabilities {
+		salt, err := crypto.NewMerkleSumSalt()
+		if err != nil {
+			return SignedAttestation{}, err
+		}
+
+		leaves = append(leaves, crypto.MerkleSumLeaf{ID: l.Address, Amount: l.Amount.Int, Salt: salt})
+		indexes[l.Address] = i
+		salts = append(salts, salt)
+	}
+
+	tree, err := crypto.NewMerkleSumTree(leaves)
+	if err != nil {
+		return SignedAttestation{}, fmt.Errorf("failed to build merkle-sum tree: %w", err)
+	}
+
+	root := tree.Root()
+	attestation := Attestation{
+		Root:        root.Hash,
+		Liabilities: types.NewFIL(new(big.Int).Set(root.Sum)),
+		Reserves:    reserves,
+		Accounts:    len(liabilities),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	sig, err := crypto.Sign(a.Key.PrivateKey, a.Key.Type, attestation.Message())
+	if err != nil {
+		return SignedAttestation{}, err
+	}
+
+	signature, err := sig.MarshalBinary()
+	if err != nil {
+		return SignedAttestation{}, fmt.Errorf("failed to marshal signature: %w", err)
+	}
+
+	signed := SignedAttestation{Attestation: attestation, Signature: signature}
+
+	if !attestation.Solvent() {
+		a.Log.Error("bank is insolvent", zap.String("liabilities", attestation.Liabilities.String()), zap.String("reserves", attestation.Reserves.String()))
+	}
+
+	a.mu.Lock()
+	a.snapshot = &solvencySnapshot{attestation: signed, tree: tree, leaves: indexes, liabilities: liabilities, salts: salts}
+	a.mu.Unlock()
+
+	return signed, nil
+}
+
+// Latest returns the latest attestation.
+func (a *SolvencyAuditor) Latest() (SignedAttestation, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.snapshot == nil {
+		return SignedAttestation{}, ErrNoAttestation
+	}
+
+	return a.snapshot.attestation, nil
+}
+
+// Proof returns the liability of an address in the latest attestation, the salt of its leaf and its proof
+// of inclusion.
+func (a *SolvencyAuditor) Proof(address string) (SolvencyProof, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.snapshot == nil {
+		return SolvencyProof{}, ErrNoAttestation
+	}
+
+	index, ok := a.snapshot.leaves[address]
+	if !ok {
+		return SolvencyProof{}, ErrAccountNotFound
+	}
+
+	steps, err := a.snapshot.tree.Proof(index)
+	if err != nil {
+		return SolvencyProof{}, fmt.Errorf("failed to build proof: %w", err)
+	}
+
+	return SolvencyProof{
+		Attestation: a.snapshot.attestation,
+		Liability:   a.snapshot.liabilities[index],
+		Salt:        a.snapshot.salts[index],
+		Steps:       steps,
+	}, nil
+}
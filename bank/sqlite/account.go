@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/subvisual/fidl/bank"
+)
+
+type AccountType int8
+
+const (
+	StorageProvider AccountType = iota + 1
+	Client
+)
+
+func (a AccountType) String() string {
+	switch a {
+	case StorageProvider:
+		return "Storage Provider"
+	case Client:
+		return "Client"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type AccountStatus int8
+
+const (
+	AccountActive AccountStatus = iota + 1
+	AccountFrozen
+	AccountClosed
+)
+
+func (a AccountStatus) String() string {
+	switch a {
+	case AccountActive:
+		return "Active"
+	case AccountFrozen:
+		return "Frozen"
+	case AccountClosed:
+		return "Closed"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+func parseAccountStatus(name string) (AccountStatus, bool) {
+	for status := AccountActive; status <= AccountClosed; status++ {
+		if strings.EqualFold(status.String(), name) {
+			return status, true
+		}
+	}
+
+	return 0, false
+}
+
+type Account struct {
+	ID        int64         `db:"id"`
+	Address   string        `db:"wallet_address"`
+	Type      AccountType   `db:"account_type"`
+	Status    AccountStatus `db:"status_id"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+// usable refuses the operations of closed accounts, and those of frozen accounts that are not listed in
+// [accounts] frozen-allowed.
+func (s BankService) usable(a *Account, op bank.Operation) error {
+	switch {
+	case a.Status == AccountClosed:
+		return bank.ErrAccountClosed
+	case a.Status == AccountFrozen && !slices.Contains(s.cfg.FrozenAllowed, op):
+		return bank.ErrAccountFrozen
+	default:
+		return nil
+	}
+}
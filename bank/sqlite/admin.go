@@ -0,0 +1,325 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type AccountEntry struct {
+	ID      int64         `db:"id"`
+	Address string        `db:"wallet_address"`
+	Type    AccountType   `db:"account_type"`
+	Status  AccountStatus `db:"status_id"`
+	Balance types.FIL     `db:"balance"`
+	Escrow  types.FIL     `db:"escrow"`
+}
+
+func (e AccountEntry) Model() bank.Account {
+	return bank.Account{
+		ID:        e.ID,
+		Address:   e.Address,
+		Type:      e.Type.String(),
+		Status:    e.Status.String(),
+		Available: e.Balance,
+		Escrow:    e.Escrow,
+	}
+}
+
+type AuditEntry struct {
+	ID        int64     `db:"id"`
+	Operator  string    `db:"operator"`
+	Action    string    `db:"action"`
+	Target    string    `db:"target"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (e AuditEntry) Model() bank.AuditEntry {
+	return bank.AuditEntry{
+		ID:        e.ID,
+		Operator:  e.Operator,
+		Action:    e.Action,
+		Target:    e.Target,
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// Accounts lists the accounts of the bank with their balances, newest first.
+func (s BankService) Accounts(ctx context.Context, params bank.AccountsParams) ([]bank.Account, error) {
+	var entries []AccountEntry
+
+	query :=
+		`
+		SELECT a.id, a.wallet_address, a.account_type, a.status_id, b.balance, b.escrow
+		FROM accounts a
+		JOIN balances b ON b.id = a.id
+		WHERE (?1 IS NULL OR a.account_type = ?1)
+		  AND (?2 IS NULL OR a.id < ?2)
+		ORDER BY a.id DESC
+		LIMIT ?3
+		`
+
+	var kind sql.NullInt16
+	switch params.Type {
+	case "client":
+		kind = sql.NullInt16{Int16: int16(Client), Valid: true}
+	case "provider":
+		kind = sql.NullInt16{Int16: int16(StorageProvider), Valid: true}
+	}
+
+	var cursor sql.NullInt64
+	if params.Cursor > 0 {
+		cursor = sql.NullInt64{Int64: params.Cursor, Valid: true}
+	}
+
+	if err := s.db.SelectContext(ctx, &entries, query, kind, cursor, params.Limit); err != nil {
+		return nil, fmt.Errorf("failed to fetch accounts: %w", err)
+	}
+
+	accounts := make([]bank.Account, 0, len(entries))
+	for _, e := range entries {
+		accounts = append(accounts, e.Model())
+	}
+
+	return accounts, nil
+}
+
+// Totals adds up the balances and escrow held by all the accounts of the bank.
+func (s BankService) Totals(ctx context.Context) (bank.Totals, error) {
+	var balances []struct {
+		Balance types.FIL `db:"balance"`
+		Escrow  types.FIL `db:"escrow"`
+	}
+
+	query :=
+		`
+		SELECT balance, escrow FROM balances
+		`
+
+	if err := s.db.SelectContext(ctx, &balances, query); err != nil {
+		return bank.Totals{}, fmt.Errorf("failed to fetch balances: %w", err)
+	}
+
+	totals := bank.Totals{
+		Accounts:  len(balances),
+		Available: types.NewFIL(new(big.Int)),
+		Escrow:    types.NewFIL(new(big.Int)),
+	}
+
+	for _, b := range balances {
+		totals.Available.Int.Add(totals.Available.Int, b.Balance.Int)
+		totals.Escrow.Int.Add(totals.Escrow.Int, b.Escrow.Int)
+	}
+
+	return totals, nil
+}
+
+// ProxyEscrow lists the authorizations against a storage provider that still hold escrow, expired ones
+// included until they are refunded.
+func (s BankService) ProxyEscrow(ctx context.Context, proxy string) (bank.ProxyEscrow, error) {
+	var entries []AuthorizationEntry
+
+	query := authorizationHistoryQuery +
+		`
+		  AND e.proxy = ?1
+		  AND e.status_id IN (1, 2)
+		ORDER BY e.uuid DESC
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		if err := tx.SelectContext(ctx, &entries, query, proxy, time.Now().UTC()); err != nil {
+			return fmt.Errorf("failed to fetch storage provider escrow: %w", err)
+		}
+
+		return sumRedemptions(ctx, tx, entries)
+	})
+	if err != nil {
+		return bank.ProxyEscrow{}, err
+	}
+
+	escrow := bank.ProxyEscrow{
+		Proxy:          proxy,
+		Escrow:         types.NewFIL(new(big.Int)),
+		Authorizations: make([]bank.Authorization, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		escrow.Escrow.Int.Add(escrow.Escrow.Int, e.Remaining.Int)
+		escrow.Authorizations = append(escrow.Authorizations, e.Model())
+	}
+
+	return escrow, nil
+}
+
+// ExpireAuthorization expires an open or locked authorization right away, so that its escrow is refunded to
+// the client. The operator and reason are recorded in the audit log.
+func (s BankService) ExpireAuthorization(ctx context.Context, operator string, id uuid.UUID, reason string) (bank.Authorization, error) {
+	var entry AuthorizationEntry
+
+	expireQuery :=
+		`
+		UPDATE escrow
+			SET expires_at = ?2,
+				updated_at = ?2
+			WHERE uuid = ?1
+			  AND status_id IN (?3, ?4)
+			  AND expires_at > ?2
+			RETURNING id
+		`
+
+	historyQuery := authorizationHistoryQuery +
+		`
+		  AND e.uuid = ?3
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		var clientID int64
+		if err := tx.QueryRowContext(ctx, expireQuery, id, now, AuthorizationOpen, AuthorizationLocked).Scan(&clientID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthNotFound
+			}
+
+			return fmt.Errorf("failed to expire authorization: %w", err)
+		}
+
+		client, err := getAccountByID(ctx, clientID, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch cli account: %w", err)
+		}
+
+		if err := tx.GetContext(ctx, &entry, historyQuery, client.Address, now, id); err != nil {
+			return fmt.Errorf("failed to fetch authorization: %w", err)
+		}
+
+		entries := []AuthorizationEntry{entry}
+		if err := sumRedemptions(ctx, tx, entries); err != nil {
+			return err
+		}
+
+		entry = entries[0]
+
+		return audit(ctx, tx, operator, bank.AuditExpireAuthorization, id.String(), reason, now)
+	})
+	if err != nil {
+		return bank.Authorization{}, err
+	}
+
+	return entry.Model(), nil
+}
+
+// SetAccountStatus freezes, closes or reactivates an account. Closing an account is final. The operator and
+// reason are recorded in the audit log.
+func (s BankService) SetAccountStatus(ctx context.Context, operator string, address string, status string, reason string) (bank.Account, error) {
+	var entry AccountEntry
+
+	statusQuery :=
+		`
+		UPDATE accounts
+			SET status_id = ?2,
+				updated_at = ?4
+			WHERE id = ?1
+			  AND status_id <> ?3
+				`
+
+	accountQuery :=
+		`
+		SELECT a.id, a.wallet_address, a.account_type, a.status_id, b.balance, b.escrow
+		FROM accounts a
+		JOIN balances b ON b.id = a.id
+		WHERE a.id = ?1
+		`
+
+	next, ok := parseAccountStatus(status)
+	if !ok {
+		return bank.Account{}, fmt.Errorf("unknown account status: %s", status)
+	}
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAccountNotFound
+			}
+
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := execOne(ctx, tx, statusQuery, account.ID, next, AccountClosed, now); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAccountClosed
+			}
+
+			return fmt.Errorf("failed to update account status: %w", err)
+		}
+
+		if err := tx.GetContext(ctx, &entry, accountQuery, account.ID); err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		return audit(ctx, tx, operator, bank.AuditAccountStatus(status), address, reason, now)
+	})
+	if err != nil {
+		return bank.Account{}, err
+	}
+
+	return entry.Model(), nil
+}
+
+// AuditLog lists the changes made by operators, newest first.
+func (s BankService) AuditLog(ctx context.Context, params bank.AuditParams) ([]bank.AuditEntry, error) {
+	var entries []AuditEntry
+
+	query :=
+		`
+		SELECT *
+		FROM admin_audit_log
+		WHERE (?1 IS NULL OR id < ?1)
+		ORDER BY id DESC
+		LIMIT ?2
+		`
+
+	var cursor sql.NullInt64
+	if params.Cursor > 0 {
+		cursor = sql.NullInt64{Int64: params.Cursor, Valid: true}
+	}
+
+	if err := s.db.SelectContext(ctx, &entries, query, cursor, params.Limit); err != nil {
+		return nil, fmt.Errorf("failed to fetch audit log: %w", err)
+	}
+
+	log := make([]bank.AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		log = append(log, e.Model())
+	}
+
+	return log, nil
+}
+
+// audit records a change made by an operator, in the transaction that makes it.
+func audit(ctx context.Context, tx fidl.Queryable, operator string, action string, target string, reason string, now time.Time) error {
+	query :=
+		`
+		INSERT INTO admin_audit_log (operator, action, target, reason, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+		`
+
+	if _, err := tx.ExecContext(ctx, query, operator, action, target, reason, now); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
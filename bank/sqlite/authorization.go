@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/types"
+)
+
+type AuthorizationStatus int8
+
+const (
+	AuthorizationOpen AuthorizationStatus = iota + 1
+	AuthorizationLocked
+	AuthorizationRedeemed
+	AuthorizationRefunded
+	AuthorizationCancelled
+)
+
+func (a AuthorizationStatus) String() string {
+	switch a {
+	case AuthorizationOpen:
+		return "Open"
+	case AuthorizationLocked:
+		return "Locked"
+	case AuthorizationRedeemed:
+		return "Redeemed"
+	case AuthorizationRefunded:
+		return "Refunded"
+	case AuthorizationCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type AuthorizationMode int8
+
+const (
+	AuthorizationSingle AuthorizationMode = iota + 1
+	AuthorizationMulti
+)
+
+func (a AuthorizationMode) String() string {
+	switch a {
+	case AuthorizationSingle:
+		return "Single"
+	case AuthorizationMulti:
+		return "Multi"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type Authorization struct {
+	ID        int64               `db:"id"`
+	UUID      uuid.UUID           `db:"uuid"`
+	Balance   types.FIL           `db:"balance"`
+	Proxy     string              `db:"proxy"`
+	Status    AuthorizationStatus `db:"status_id"`
+	MaxPrice  *types.FIL          `db:"max_price"`
+	Mode      AuthorizationMode   `db:"mode_id"`
+	Amount    types.FIL           `db:"amount"`
+	ExpiresAt time.Time           `db:"expires_at"`
+	Price     types.FIL           `db:"price"`
+	CreatedAt time.Time           `db:"created_at"`
+	UpdatedAt time.Time           `db:"updated_at"`
+}
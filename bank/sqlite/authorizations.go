@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// authorizationHistoryQuery lists the authorizations of a client or storage provider. Open and locked
+// authorizations past their expiry are reported as expired until they are refunded, and only those still
+// hold escrow.
+const authorizationHistoryQuery = `
+	SELECT e.uuid, a.wallet_address AS client, e.proxy, e.mode_id, e.amount, e.price, e.max_price,
+		e.expires_at, e.created_at, e.updated_at,
+		CASE WHEN e.status_id IN (1, 2) AND e.expires_at <= ?2 THEN 'Expired' ELSE s.name END AS status,
+		CASE WHEN e.status_id IN (1, 2) THEN e.balance ELSE '0' END AS remaining
+	FROM escrow e
+	JOIN accounts a ON a.id = e.id
+	JOIN authorization_status s ON s.id = e.status_id
+	WHERE (a.wallet_address = ?1 OR e.proxy = ?1)
+	`
+
+type AuthorizationEntry struct {
+	UUID      uuid.UUID         `db:"uuid"`
+	Client    string            `db:"client"`
+	Proxy     string            `db:"proxy"`
+	Mode      AuthorizationMode `db:"mode_id"`
+	Status    string            `db:"status"`
+	Amount    types.FIL         `db:"amount"`
+	Remaining types.FIL         `db:"remaining"`
+	Redeemed  types.FIL         `db:"redeemed"`
+	Price     types.FIL         `db:"price"`
+	MaxPrice  *types.FIL        `db:"max_price"`
+	ExpiresAt time.Time         `db:"expires_at"`
+	CreatedAt time.Time         `db:"created_at"`
+	UpdatedAt time.Time         `db:"updated_at"`
+}
+
+func (e AuthorizationEntry) Model() bank.Authorization {
+	return bank.Authorization{
+		UUID:      e.UUID,
+		Client:    e.Client,
+		Proxy:     e.Proxy,
+		Mode:      e.Mode.String(),
+		Status:    e.Status,
+		Amount:    e.Amount,
+		Remaining: e.Remaining,
+		Redeemed:  e.Redeemed,
+		Price:     e.Price,
+		MaxPrice:  e.MaxPrice,
+		ExpiresAt: e.ExpiresAt,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+// Authorizations lists the authorizations the given address is the client or storage provider of, newest first.
+func (s BankService) Authorizations(ctx context.Context, address string, params bank.AuthorizationsParams) ([]bank.Authorization, error) {
+	var entries []AuthorizationEntry
+
+	query := authorizationHistoryQuery +
+		`
+		  AND (?3 = '' OR lower(status) = ?3)
+		  AND (?4 IS NULL OR e.uuid < ?4)
+		ORDER BY e.uuid DESC
+		LIMIT ?5
+		`
+
+	var cursor uuid.NullUUID
+	if params.Cursor != uuid.Nil {
+		cursor = uuid.NullUUID{UUID: params.Cursor, Valid: true}
+	}
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		args := []any{address, time.Now().UTC(), params.Status, cursor, params.Limit}
+		if err := tx.SelectContext(ctx, &entries, query, args...); err != nil {
+			return fmt.Errorf("failed to fetch authorizations: %w", err)
+		}
+
+		return sumRedemptions(ctx, tx, entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authorizations := make([]bank.Authorization, 0, len(entries))
+	for _, e := range entries {
+		authorizations = append(authorizations, e.Model())
+	}
+
+	return authorizations, nil
+}
+
+// Authorization returns an authorization of which the given address is the client or storage provider.
+func (s BankService) Authorization(ctx context.Context, address string, id uuid.UUID) (bank.Authorization, error) {
+	var entry AuthorizationEntry
+
+	query := authorizationHistoryQuery +
+		`
+		  AND e.uuid = ?3
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		args := []any{address, time.Now().UTC(), id}
+		if err := tx.GetContext(ctx, &entry, query, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthNotFound
+			}
+
+			return fmt.Errorf("failed to fetch authorization: %w", err)
+		}
+
+		entries := []AuthorizationEntry{entry}
+		if err := sumRedemptions(ctx, tx, entries); err != nil {
+			return err
+		}
+
+		entry = entries[0]
+
+		return nil
+	})
+	if err != nil {
+		return bank.Authorization{}, err
+	}
+
+	return entry.Model(), nil
+}
+
+// sumRedemptions fills in how much was redeemed from each of the authorizations.
+func sumRedemptions(ctx context.Context, tx fidl.Queryable, entries []AuthorizationEntry) error {
+	var redemptions []struct {
+		UUID  uuid.UUID `db:"authorization_uuid"`
+		Value types.FIL `db:"value"`
+	}
+
+	redeemed := make(map[uuid.UUID]*types.FIL, len(entries))
+	ids := make([]uuid.UUID, 0, len(entries))
+
+	for i := range entries {
+		entries[i].Redeemed = types.NewFIL(new(big.Int))
+		redeemed[entries[i].UUID] = &entries[i].Redeemed
+		ids = append(ids, entries[i].UUID)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(
+		`
+		SELECT authorization_uuid, value
+		FROM redemptions
+		WHERE authorization_uuid IN (?)
+		`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build redemptions query: %w", err)
+	}
+
+	if err := tx.SelectContext(ctx, &redemptions, query, args...); err != nil {
+		return fmt.Errorf("failed to fetch redemptions: %w", err)
+	}
+
+	for _, r := range redemptions {
+		total := redeemed[r.UUID]
+		total.Int.Add(total.Int, r.Value.Int)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// Authorize escrows an amount for a storage provider, defaulting to its price. The amount must cover at
+// least one retrieval at the provider's price, and the price must not be above the client's maximum.
+// A multi authorization can be redeemed several times, until its amount is used up or it expires.
+// The expiry defaults to the escrow deadline, and a client asking for its own must stay within the bank's bounds.
+// The provider's price is kept with the authorization, so that later price changes do not apply to it.
+func (s BankService) Authorize(ctx context.Context, address string, params bank.AuthorizeParams) (bank.AuthModel, error) {
+	var balance types.FIL
+	var cost types.FIL
+	var id uuid.UUID
+	var model bank.AuthModel
+
+	escrowQuery :=
+		`
+		INSERT INTO escrow (id, uuid, balance, amount, proxy, status_id, max_price, mode_id, expires_at, price, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?10)
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), ?8, ?9, ?9)
+		`
+
+	proxy, amount, maxPrice := params.Proxy, params.Amount, params.MaxPrice
+
+	mode := AuthorizationSingle
+	if params.Mode == "multi" {
+		mode = AuthorizationMulti
+	}
+
+	expiry, err := s.authorizationExpiry(params.ExpiresAt)
+	if err != nil {
+		return bank.AuthModel{}, err
+	}
+
+	err = Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch cli account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationAuthorize); err != nil {
+			return err
+		}
+
+		spAccount, err := getAccountByAddress(ctx, proxy, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sp account: %w", err)
+		}
+
+		sp, err := getProvider(ctx, spAccount.ID, tx)
+		if err != nil {
+			return err
+		}
+
+		if sp.Status != StorageProviderActive {
+			return bank.ErrProxyNotActive
+		}
+
+		price := sp.Price
+
+		if maxPrice != nil && price.Cmp(maxPrice.Int) == 1 {
+			return bank.ErrPriceAboveMax
+		}
+
+		cost = price
+		if amount != nil {
+			if amount.Cmp(price.Int) == -1 {
+				return bank.ErrAmountBelowPrice
+			}
+
+			cost = *amount
+		}
+
+		balance, _, err = updateBalances(ctx, tx, account.ID, new(big.Int).Neg(cost.Int), cost.Int, now)
+		if err != nil {
+			return err
+		}
+
+		transactionID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		id, err = uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		var maxPriceArg any
+		if maxPrice != nil {
+			maxPriceArg = maxPrice.Int.String()
+		}
+
+		args := []any{account.ID, id, cost.Int.String(), proxy, AuthorizationOpen, maxPriceArg, mode, expiry, price.Int.String(), now}
+		if _, err := tx.ExecContext(ctx, escrowQuery, args...); err != nil {
+			return fmt.Errorf("failed to deposit to escrow: %w", err)
+		}
+
+		args = []any{transactionID.String(), s.cfg.WalletAddress, s.cfg.EscrowAddress, cost.Int.String(), TransactionCompleted, address, proxy, TransactionAuthorize, now}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register transaction during authorize: %w", err)
+		}
+
+		err = postJournal(ctx, tx, transactionID.String(), now,
+			debit(address, LedgerBalance, cost.Int),
+			credit(address, LedgerEscrow, cost.Int),
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, address); err != nil {
+			return err
+		}
+
+		model = bank.AuthModel{
+			UUID:      id,
+			Mode:      mode.String(),
+			Available: balance,
+			Escrow:    types.NewFIL(cost.Int),
+			ExpiresAt: expiry,
+		}
+
+		return respond(ctx, tx, model)
+	})
+	if err != nil {
+		return bank.AuthModel{}, err
+	}
+
+	return model, nil
+}
+
+// authorizationExpiry returns when a new authorization expires, checking a requested expiry against the
+// escrow deadline bounds.
+func (s BankService) authorizationExpiry(requested *time.Time) (time.Time, error) {
+	now := time.Now().UTC()
+
+	if requested == nil {
+		return now.Add(s.cfg.EscrowDeadline), nil
+	}
+
+	lifetime := requested.Sub(now)
+	if lifetime < s.cfg.EscrowMinDeadline || lifetime > s.cfg.EscrowMaxDeadline {
+		return time.Time{}, bank.ErrExpiryOutOfBounds
+	}
+
+	return requested.UTC(), nil
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+func (s BankService) Balance(ctx context.Context, address string) (types.FIL, types.FIL, error) {
+	var balance types.FIL
+	var escrow types.FIL
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		balance, escrow, err = getBalances(ctx, tx, account.ID)
+
+		return err
+	})
+	if err != nil {
+		return types.FIL{}, types.FIL{}, err
+	}
+
+	return balance, escrow, nil
+}
+
+func getBalances(ctx context.Context, tx fidl.Queryable, id int64) (types.FIL, types.FIL, error) {
+	var balance types.FIL
+	var escrow types.FIL
+
+	query :=
+		`
+		SELECT balance, escrow FROM balances WHERE id = ?1
+		`
+
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&balance, &escrow); err != nil {
+		return types.FIL{}, types.FIL{}, fmt.Errorf("failed to get balances: %w", err)
+	}
+
+	return balance, escrow, nil
+}
+
+// updateBalances adds amounts, which may be negative, to the balance and escrow of an account and returns
+// the new ones. It fails with ErrInsufficientFunds, leaving them untouched, when either would go negative.
+func updateBalances(ctx context.Context, tx fidl.Queryable, id int64, balanceDelta *big.Int, escrowDelta *big.Int, now time.Time) (types.FIL, types.FIL, error) {
+	query :=
+		`
+		UPDATE balances
+			SET balance = ?2,
+				escrow = ?3,
+				updated_at = ?4
+			WHERE id = ?1
+		`
+
+	balance, escrow, err := getBalances(ctx, tx, id)
+	if err != nil {
+		return types.FIL{}, types.FIL{}, err
+	}
+
+	balance.Int.Add(balance.Int, balanceDelta)
+	escrow.Int.Add(escrow.Int, escrowDelta)
+
+	if balance.Sign() == -1 || escrow.Sign() == -1 {
+		return types.FIL{}, types.FIL{}, bank.ErrInsufficientFunds
+	}
+
+	if err := execOne(ctx, tx, query, id, balance.Int.String(), escrow.Int.String(), now); err != nil {
+		return types.FIL{}, types.FIL{}, fmt.Errorf("failed to update balances: %w", err)
+	}
+
+	return balance, escrow, nil
+}
+
+// sum adds up the amounts selected by a query.
+func sum(ctx context.Context, tx fidl.Queryable, query string, args ...any) (types.FIL, error) {
+	var amounts []types.FIL
+
+	if err := tx.SelectContext(ctx, &amounts, query, args...); err != nil {
+		return types.FIL{}, err // nolint:wrapcheck
+	}
+
+	total := types.NewFIL(new(big.Int))
+	for _, a := range amounts {
+		total.Int.Add(total.Int, a.Int)
+	}
+
+	return total, nil
+}
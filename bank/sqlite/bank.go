@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+)
+
+type BankConfig struct {
+	WalletAddress       string
+	EscrowAddress       string
+	EscrowDeadline      time.Duration
+	EscrowMinDeadline   time.Duration
+	EscrowMaxDeadline   time.Duration
+	ChannelSettleWindow string
+	ChannelLifetime     time.Duration
+	FrozenAllowed       []bank.Operation
+	Fees                bank.FeeSchedule
+}
+
+// BankService keeps the bank in a SQLite database, for operators that run a single bank process.
+// Amounts are stored as decimal text, since attoFIL overflow SQLite's integers, so they are compared
+// and added up in Go rather than in queries. Transaction holds the database's write lock for that.
+type BankService struct {
+	db  *DB
+	cfg *BankConfig
+}
+
+func NewBankService(db *DB, cfg *BankConfig) *BankService {
+	return &BankService{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func getAccountByAddress(ctx context.Context, address string, tx fidl.Queryable) (*Account, error) {
+	query :=
+		`
+		SELECT *
+		FROM accounts
+		WHERE wallet_address = ?1
+		`
+
+	var account Account
+	if err := tx.GetContext(ctx, &account, query, address); err != nil {
+		return nil, fmt.Errorf("failed to fetch account by wallet address: %w", err)
+	}
+
+	return &account, nil
+}
+
+func getAccountByID(ctx context.Context, id int64, tx fidl.Queryable) (*Account, error) {
+	query :=
+		`
+		SELECT *
+		FROM accounts
+		WHERE id = ?1
+		`
+
+	var account Account
+	if err := tx.GetContext(ctx, &account, query, id); err != nil {
+		return nil, fmt.Errorf("failed to fetch account by id: %w", err)
+	}
+
+	return &account, nil
+}
+
+// insertAccount adds an account of the given type for a wallet, unless it already has one, and returns
+// the wallet's account along with a balances entry.
+func insertAccount(ctx context.Context, tx fidl.Queryable, address string, kind AccountType, now time.Time) (*Account, error) {
+	accountQuery :=
+		`
+		INSERT INTO accounts (wallet_address, account_type, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?3)
+		ON CONFLICT (wallet_address) DO NOTHING
+		`
+
+	balancesQuery :=
+		`
+		INSERT INTO balances (id, created_at, updated_at)
+		VALUES (?1, ?2, ?2)
+		ON CONFLICT (id) DO NOTHING
+		`
+
+	if _, err := tx.ExecContext(ctx, accountQuery, address, kind, now); err != nil {
+		return nil, fmt.Errorf("failed to add account entry: %w", err)
+	}
+
+	account, err := getAccountByAddress(ctx, address, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, balancesQuery, account.ID, now); err != nil {
+		return nil, fmt.Errorf("failed to add balances entry: %w", err)
+	}
+
+	return account, nil
+}
+
+// deleteEmptyClient removes the account of a client once it holds nothing. Clients with authorizations
+// are kept, as their authorizations are history.
+func deleteEmptyClient(ctx context.Context, tx fidl.Queryable, id int64) error {
+	deleteBalanceEntryQuery :=
+		`
+		DELETE FROM balances
+		WHERE id = ?1
+		  AND NOT EXISTS (SELECT 1 FROM escrow WHERE escrow.id = ?1)
+		`
+
+	deleteAccountEntryQuery :=
+		`
+		DELETE FROM accounts
+		WHERE id = ?1
+		  AND NOT EXISTS (SELECT 1 FROM escrow WHERE escrow.id = ?1)
+		`
+
+	if _, err := tx.ExecContext(ctx, deleteBalanceEntryQuery, id); err != nil {
+		return fmt.Errorf("failed to delete client balance entry: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteAccountEntryQuery, id); err != nil {
+		return fmt.Errorf("failed to delete client account entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3" // needed for sqlite3 driver for migrations
+	_ "github.com/golang-migrate/migrate/v4/source/file"      // needed for file driver for migrations
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/bank/banktest"
+	"github.com/subvisual/fidl/bank/sqlite"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	banktest.Run(t, func(t *testing.T, cfg banktest.Config) bank.Service {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "bank.db")
+
+		migr, err := migrate.New("file://migrations", "sqlite3://"+path)
+		if err != nil {
+			t.Fatalf("could not create migrations: %v", err)
+		}
+
+		if err := migr.Up(); err != nil {
+			t.Fatalf("could not run up migrations: %v", err)
+		}
+
+		if srcErr, dbErr := migr.Close(); srcErr != nil || dbErr != nil {
+			t.Fatalf("could not close migrations: %v, %v", srcErr, dbErr)
+		}
+
+		db := sqlite.Connect(sqlite.Config{Dsn: path, MaxOpenConns: 4, MaxIdleConns: 4, MaxIdleTime: "1m"})
+		t.Cleanup(func() { _ = db.Close() })
+
+		return sqlite.NewBankService(db, &sqlite.BankConfig{
+			WalletAddress:       cfg.WalletAddress,
+			EscrowAddress:       cfg.EscrowAddress,
+			EscrowDeadline:      cfg.EscrowDeadline,
+			EscrowMinDeadline:   cfg.EscrowMinDeadline,
+			EscrowMaxDeadline:   cfg.EscrowMaxDeadline,
+			ChannelSettleWindow: cfg.ChannelSettleWindow,
+			ChannelLifetime:     cfg.ChannelLifetime,
+			FrozenAllowed:       cfg.FrozenAllowed,
+			Fees:                cfg.Fees,
+		})
+	})
+}
@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// CancelAuthorization releases an open authorization of the client back to its balance. An authorization
+// locked by a proxy's Verify is being redeemed, so it cannot be cancelled until the proxy is done with it.
+func (s BankService) CancelAuthorization(ctx context.Context, address string, id uuid.UUID) (bank.CancelModel, error) {
+	var auth Authorization
+	var balance types.FIL
+	var escrow types.FIL
+
+	authQuery :=
+		`
+		SELECT *
+		FROM escrow
+		WHERE uuid = ?1
+		  AND id = ?2
+		`
+
+	cancelAuthQuery :=
+		`
+		UPDATE escrow
+			SET status_id = ?2,
+				updated_at = ?3
+			WHERE uuid = ?1
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), ?8, ?9, ?9)
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationCancel); err != nil {
+			return err
+		}
+
+		if err := tx.GetContext(ctx, &auth, authQuery, id, account.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthNotFound
+			}
+
+			return fmt.Errorf("failed to fetch authorization: %w", err)
+		}
+
+		switch auth.Status {
+		case AuthorizationOpen:
+		case AuthorizationLocked:
+			return bank.ErrAuthNotCancellable
+		default:
+			return bank.ErrAuthNotFound
+		}
+
+		if _, err := tx.ExecContext(ctx, cancelAuthQuery, id, AuthorizationCancelled, now); err != nil {
+			return fmt.Errorf("failed to cancel authorization: %w", err)
+		}
+
+		balance, escrow, err = updateBalances(ctx, tx, account.ID, auth.Balance.Int, new(big.Int).Neg(auth.Balance.Int), now)
+		if err != nil {
+			return fmt.Errorf("failed to update balances: %w", err)
+		}
+
+		transactionID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		args := []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, auth.Balance.Int.String(), TransactionCompleted, address, auth.Proxy, TransactionRefund, now}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register transaction during cancel: %w", err)
+		}
+
+		err = postJournal(ctx, tx, transactionID.String(), now,
+			debit(address, LedgerEscrow, auth.Balance.Int),
+			credit(address, LedgerBalance, auth.Balance.Int),
+		)
+		if err != nil {
+			return err
+		}
+
+		return checkLedger(ctx, tx, address)
+	})
+	if err != nil {
+		return bank.CancelModel{}, err
+	}
+
+	return bank.CancelModel{
+		Released:  auth.Balance,
+		Available: balance,
+		Escrow:    escrow,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// Deposit credits a client with a transfer to the bank's wallet, less the deposit fee.
+func (s BankService) Deposit(ctx context.Context, address string, amount types.FIL, transactionHash string) (types.FIL, error) {
+	var balance types.FIL
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		var err error
+		balance, err = s.deposit(ctx, tx, address, amount, transactionHash, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		return respond(ctx, tx, balance)
+	})
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	return balance, nil
+}
+
+// deposit credits a client with a transfer in the transaction tx, and returns the client's new balance.
+func (s BankService) deposit(ctx context.Context, tx fidl.Queryable, address string, amount types.FIL, transactionHash string, now time.Time) (types.FIL, error) {
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), ?8, ?9, ?9)
+		ON CONFLICT (transaction_id) DO NOTHING
+		`
+
+	account, err := insertAccount(ctx, tx, address, Client, now)
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	if err := s.usable(account, bank.OperationDeposit); err != nil {
+		return types.FIL{}, err
+	}
+
+	if account.Type == StorageProvider {
+		return types.FIL{}, bank.ErrOperationNotAllowed
+	}
+
+	fee := s.cfg.Fees.Deposit.Charge(amount.Int)
+	credited := new(big.Int).Sub(amount.Int, fee)
+
+	balance, _, err := updateBalances(ctx, tx, account.ID, credited, new(big.Int), now)
+	if err != nil {
+		return types.FIL{}, fmt.Errorf("failed to deposit balance: %w", err)
+	}
+
+	args := []any{transactionHash, address, s.cfg.WalletAddress, amount.Int.String(), TransactionCompleted, address, s.cfg.WalletAddress, TransactionDeposit, now}
+	err = execOne(ctx, tx, transactionQuery, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.FIL{}, bank.ErrTransactionExists
+	}
+
+	if err != nil {
+		return types.FIL{}, fmt.Errorf("failed to register transaction during deposit: %w", err)
+	}
+
+	err = postJournal(ctx, tx, transactionHash, now,
+		debit(s.cfg.WalletAddress, LedgerWallet, amount.Int),
+		credit(address, LedgerBalance, credited),
+		credit(s.cfg.WalletAddress, LedgerRevenue, fee),
+	)
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	if err := recordFee(ctx, tx, transactionHash, address, TransactionDeposit, fee, now); err != nil {
+		return types.FIL{}, err
+	}
+
+	if err := checkLedger(ctx, tx, address); err != nil {
+		return types.FIL{}, err
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,268 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type HeldDepositStatus int8
+
+const (
+	HeldDepositHeld HeldDepositStatus = iota + 1
+	HeldDepositCredited
+	HeldDepositReturned
+)
+
+func (h HeldDepositStatus) String() string {
+	switch h {
+	case HeldDepositHeld:
+		return "Held"
+	case HeldDepositCredited:
+		return "Credited"
+	case HeldDepositReturned:
+		return "Returned"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+func parseHeldDepositStatus(name string) (HeldDepositStatus, bool) {
+	for status := HeldDepositHeld; status <= HeldDepositReturned; status++ {
+		if strings.EqualFold(status.String(), name) {
+			return status, true
+		}
+	}
+
+	return 0, false
+}
+
+type HeldDepositEntry struct {
+	ID         int64             `db:"id"`
+	Hash       string            `db:"hash"`
+	Sender     string            `db:"sender"`
+	Address    sql.NullString    `db:"wallet_address"`
+	Value      types.FIL         `db:"value"`
+	Reason     string            `db:"reason"`
+	Status     HeldDepositStatus `db:"status_id"`
+	CreditedTo sql.NullString    `db:"credited_to"`
+	ReturnHash sql.NullString    `db:"return_hash"`
+	ResolvedAt sql.NullTime      `db:"resolved_at"`
+	CreatedAt  time.Time         `db:"created_at"`
+	UpdatedAt  time.Time         `db:"updated_at"`
+}
+
+func (e HeldDepositEntry) Model() bank.HeldDeposit {
+	return bank.HeldDeposit{
+		ID:         e.ID,
+		Hash:       e.Hash,
+		Sender:     e.Sender,
+		Address:    e.Address.String,
+		Value:      e.Value,
+		Reason:     e.Reason,
+		Status:     e.Status.String(),
+		CreditedTo: e.CreditedTo.String,
+		ReturnHash: e.ReturnHash.String,
+		CreatedAt:  e.CreatedAt,
+		ResolvedAt: e.ResolvedAt.Time,
+	}
+}
+
+// ChainEntries returns the entries a held deposit stands for on chain: the deposit itself, which takes the
+// place of the transaction it was credited with, and the transfer it was returned with.
+func (e HeldDepositEntry) ChainEntries() []bank.ChainEntry {
+	address := e.Address.String
+	if e.CreditedTo.Valid {
+		address = e.CreditedTo.String
+	}
+
+	deposit := bank.ChainEntry{
+		Type:        bank.TransactionDeposit,
+		ID:          e.Hash,
+		Hash:        e.Hash,
+		Address:     address,
+		Counterpart: e.Sender,
+		Value:       e.Value,
+		Status:      bank.TransactionStatus(e.Status.String()),
+		At:          e.CreatedAt,
+	}
+
+	if e.Status != HeldDepositReturned {
+		return []bank.ChainEntry{deposit}
+	}
+
+	return []bank.ChainEntry{deposit, {
+		Type:        bank.TransactionReturn,
+		ID:          e.Hash,
+		Hash:        e.ReturnHash.String,
+		Address:     e.Address.String,
+		Counterpart: e.Sender,
+		Value:       e.Value,
+		Status:      bank.TransactionCompleted,
+		At:          e.ResolvedAt.Time,
+	}}
+}
+
+// HoldDeposit keeps a deposit that could not be credited. Holding a deposit again does nothing.
+func (s BankService) HoldDeposit(ctx context.Context, deposit bank.HeldDeposit) error {
+	query :=
+		`
+		INSERT INTO held_deposits (hash, sender, wallet_address, value, reason, status_id, created_at, updated_at)
+		VALUES (?1, ?2, NULLIF(?3, ''), ?4, ?5, ?6, ?7, ?7)
+		ON CONFLICT (hash) DO NOTHING
+		`
+
+	args := []any{strings.ToLower(deposit.Hash), deposit.Sender, deposit.Address, deposit.Value.Int.String(), deposit.Reason, HeldDepositHeld, time.Now().UTC()}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to hold deposit: %w", err)
+	}
+
+	return nil
+}
+
+// HeldDeposits lists the held deposits, newest first.
+func (s BankService) HeldDeposits(ctx context.Context, params bank.HeldDepositsParams) ([]bank.HeldDeposit, error) {
+	var entries []HeldDepositEntry
+
+	query :=
+		`
+		SELECT *
+		FROM held_deposits
+		WHERE (?1 IS NULL OR status_id = ?1)
+		  AND (?2 IS NULL OR id < ?2)
+		ORDER BY id DESC
+		LIMIT ?3
+		`
+
+	var status sql.NullInt16
+	if params.Status != "" {
+		s, ok := parseHeldDepositStatus(params.Status)
+		if !ok {
+			return nil, fmt.Errorf("unknown held deposit status: %s", params.Status)
+		}
+
+		status = sql.NullInt16{Int16: int16(s), Valid: true}
+	}
+
+	var cursor sql.NullInt64
+	if params.Cursor > 0 {
+		cursor = sql.NullInt64{Int64: params.Cursor, Valid: true}
+	}
+
+	if err := s.db.SelectContext(ctx, &entries, query, status, cursor, params.Limit); err != nil {
+		return nil, fmt.Errorf("failed to fetch held deposits: %w", err)
+	}
+
+	deposits := make([]bank.HeldDeposit, 0, len(entries))
+	for _, e := range entries {
+		deposits = append(deposits, e.Model())
+	}
+
+	return deposits, nil
+}
+
+// CreditHeldDeposit deposits a held deposit into an account, less the deposit fee, as if the account had
+// sent it. The operator and reason are recorded in the audit log.
+func (s BankService) CreditHeldDeposit(ctx context.Context, operator string, hash string, address string, reason string) (bank.HeldDeposit, error) {
+	var entry HeldDepositEntry
+
+	creditQuery :=
+		`
+		UPDATE held_deposits
+			SET status_id = ?2,
+				credited_to = ?3,
+				resolved_at = ?5,
+				updated_at = ?5
+			WHERE hash = ?1
+			  AND status_id = ?4
+			RETURNING *
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		err := tx.GetContext(ctx, &entry, creditQuery, strings.ToLower(hash), HeldDepositCredited, address, HeldDepositHeld, now)
+		if errors.Is(err, sql.ErrNoRows) {
+			return heldDepositNotHeld(ctx, tx, hash)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to credit held deposit: %w", err)
+		}
+
+		if _, err := s.deposit(ctx, tx, address, entry.Value, entry.Hash, now); err != nil {
+			return err
+		}
+
+		return audit(ctx, tx, operator, bank.AuditCreditHeldDeposit, entry.Hash, reason, now)
+	})
+	if err != nil {
+		return bank.HeldDeposit{}, err
+	}
+
+	return entry.Model(), nil
+}
+
+// ReturnHeldDeposit records that a held deposit was sent back to its sender with the transfer returnHash.
+// The operator and reason are recorded in the audit log.
+func (s BankService) ReturnHeldDeposit(ctx context.Context, operator string, hash string, returnHash string, reason string) (bank.HeldDeposit, error) {
+	var entry HeldDepositEntry
+
+	returnQuery :=
+		`
+		UPDATE held_deposits
+			SET status_id = ?2,
+				return_hash = ?3,
+				resolved_at = ?5,
+				updated_at = ?5
+			WHERE hash = ?1
+			  AND status_id = ?4
+			RETURNING *
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		err := tx.GetContext(ctx, &entry, returnQuery, strings.ToLower(hash), HeldDepositReturned, strings.ToLower(returnHash), HeldDepositHeld, now)
+		if errors.Is(err, sql.ErrNoRows) {
+			return heldDepositNotHeld(ctx, tx, hash)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to return held deposit: %w", err)
+		}
+
+		return audit(ctx, tx, operator, bank.AuditReturnHeldDeposit, entry.Hash, reason, now)
+	})
+	if err != nil {
+		return bank.HeldDeposit{}, err
+	}
+
+	return entry.Model(), nil
+}
+
+// heldDepositNotHeld tells why a deposit could not be credited or returned: it is unknown, or was already.
+func heldDepositNotHeld(ctx context.Context, tx fidl.Queryable, hash string) error {
+	query :=
+		`
+		SELECT EXISTS (SELECT 1 FROM held_deposits WHERE hash = ?1)
+		`
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, query, strings.ToLower(hash)).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to fetch held deposit: %w", err)
+	}
+
+	if exists {
+		return bank.ErrHeldDepositResolved
+	}
+
+	return bank.ErrHeldDepositNotFound
+}
@@ -0,0 +1,191 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type LedgerBook int8
+
+const (
+	LedgerWallet LedgerBook = iota + 1
+	LedgerBalance
+	LedgerEscrow
+	LedgerWithdrawal
+	LedgerRevenue
+)
+
+func (l LedgerBook) String() string {
+	switch l {
+	case LedgerWallet:
+		return "Wallet"
+	case LedgerBalance:
+		return "Balance"
+	case LedgerEscrow:
+		return "Escrow"
+	case LedgerWithdrawal:
+		return "Withdrawal"
+	case LedgerRevenue:
+		return "Revenue"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type LedgerEntry struct {
+	ID            int64          `db:"id"`
+	JournalID     uuid.UUID      `db:"journal_id"`
+	TransactionID sql.NullString `db:"transaction_id"`
+	Address       string         `db:"wallet_address"`
+	Book          LedgerBook     `db:"book_id"`
+	Debit         types.FIL      `db:"debit"`
+	Credit        types.FIL      `db:"credit"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+type posting struct {
+	address string
+	book    LedgerBook
+	debit   *big.Int
+	credit  *big.Int
+}
+
+func debit(address string, book LedgerBook, amount *big.Int) posting {
+	return posting{address: address, book: book, debit: amount, credit: new(big.Int)}
+}
+
+func credit(address string, book LedgerBook, amount *big.Int) posting {
+	return posting{address: address, book: book, debit: new(big.Int), credit: amount}
+}
+
+// postJournal writes a balanced set of ledger entries under a new journal.
+func postJournal(ctx context.Context, tx fidl.Queryable, transactionID string, now time.Time, postings ...posting) error {
+	entryQuery :=
+		`
+		INSERT INTO ledger_entries (journal_id, transaction_id, wallet_address, book_id, debit, credit, created_at)
+		VALUES (?1, NULLIF(?2, ''), ?3, ?4, ?5, ?6, ?7)
+		`
+
+	debits, credits := new(big.Int), new(big.Int)
+	for _, p := range postings {
+		debits.Add(debits, p.debit)
+		credits.Add(credits, p.credit)
+	}
+
+	if debits.Cmp(credits) != 0 {
+		return fmt.Errorf("unbalanced journal: debits %s, credits %s", debits, credits)
+	}
+
+	journalID, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	for _, p := range postings {
+		if p.debit.Sign() == 0 && p.credit.Sign() == 0 {
+			continue
+		}
+
+		args := []any{journalID, transactionID, p.address, p.book, p.debit.String(), p.credit.String(), now}
+		if _, err := tx.ExecContext(ctx, entryQuery, args...); err != nil {
+			return fmt.Errorf("failed to post ledger entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ledgerBalances derives the available and escrowed balances of an account from its ledger entries,
+// up to the given time when it is valid.
+func ledgerBalances(ctx context.Context, tx fidl.Queryable, address string, at sql.NullTime) (types.FIL, types.FIL, error) {
+	var entries []LedgerEntry
+
+	query :=
+		`
+		SELECT *
+		FROM ledger_entries
+		WHERE wallet_address = ?1
+		  AND book_id IN (?2, ?3)
+		  AND (?4 IS NULL OR created_at <= ?4)
+		`
+
+	args := []any{address, LedgerBalance, LedgerEscrow, at}
+	if err := tx.SelectContext(ctx, &entries, query, args...); err != nil {
+		return types.FIL{}, types.FIL{}, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+
+	balance := types.NewFIL(new(big.Int))
+	escrow := types.NewFIL(new(big.Int))
+
+	for _, e := range entries {
+		book := balance.Int
+		if e.Book == LedgerEscrow {
+			book = escrow.Int
+		}
+
+		book.Add(book, e.Credit.Int)
+		book.Sub(book, e.Debit.Int)
+	}
+
+	return balance, escrow, nil
+}
+
+// checkLedger verifies that the stored balances of an account match the ones derived from its ledger entries.
+func checkLedger(ctx context.Context, tx fidl.Queryable, address string) error {
+	query :=
+		`
+		SELECT b.balance, b.escrow
+		FROM balances b
+		JOIN accounts a ON a.id = b.id
+		WHERE a.wallet_address = ?1
+		`
+
+	// A closed account holds nothing.
+	balance, escrow := types.NewFIL(new(big.Int)), types.NewFIL(new(big.Int))
+
+	err := tx.QueryRowContext(ctx, query, address).Scan(&balance, &escrow)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get balances: %w", err)
+	}
+
+	ledgerBalance, ledgerEscrow, err := ledgerBalances(ctx, tx, address, sql.NullTime{})
+	if err != nil {
+		return err
+	}
+
+	if balance.Cmp(ledgerBalance.Int) != 0 || escrow.Cmp(ledgerEscrow.Int) != 0 {
+		return fmt.Errorf("%w: account %s", bank.ErrLedgerMismatch, address)
+	}
+
+	return nil
+}
+
+func (s BankService) Ledger(ctx context.Context, address string, at time.Time) (bank.LedgerModel, error) {
+	var balance types.FIL
+	var escrow types.FIL
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		var err error
+		balance, escrow, err = ledgerBalances(ctx, tx, address, sql.NullTime{Time: at.UTC(), Valid: true})
+
+		return err
+	})
+	if err != nil {
+		return bank.LedgerModel{}, err
+	}
+
+	return bank.LedgerModel{
+		Available: balance,
+		Escrow:    escrow,
+		At:        at.UTC(),
+	}, nil
+}
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS
+  accounts_types (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  accounts_types (id, name)
+VALUES
+  (1, 'Storage Provider'),
+  (2, 'Client');
+
+CREATE TABLE IF NOT EXISTS
+  accounts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    wallet_address text NOT NULL,
+    account_type integer NOT NULL REFERENCES accounts_types (id),
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
+
+CREATE UNIQUE INDEX accounts_idx ON accounts (wallet_address);
+CREATE INDEX accounts_types_idx ON accounts (account_type);
+
+-- Amounts are kept as decimal text, as attoFIL do not fit SQLite's integers.
+CREATE TABLE IF NOT EXISTS
+  balances (
+    id integer PRIMARY KEY REFERENCES accounts (id),
+    balance text NOT NULL DEFAULT '0',
+    escrow text NOT NULL DEFAULT '0',
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
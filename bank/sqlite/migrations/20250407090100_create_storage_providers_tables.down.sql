@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS storage_provider_prices;
+DROP TABLE IF EXISTS storage_providers;
+DROP TABLE IF EXISTS storage_provider_status;
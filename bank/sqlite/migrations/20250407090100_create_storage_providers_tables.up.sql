@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS
+  storage_provider_status (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  storage_provider_status (id, name)
+VALUES
+  (1, 'Active'),
+  (2, 'Deregistering'),
+  (3, 'Inactive');
+
+CREATE TABLE IF NOT EXISTS
+  storage_providers (
+    id integer PRIMARY KEY REFERENCES accounts (id),
+    sp_id text NOT NULL,
+    price text NOT NULL DEFAULT '0',
+    status_id integer NOT NULL DEFAULT 1 REFERENCES storage_provider_status (id),
+    payout_address text,
+    deregistered_at timestamp,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
+
+CREATE INDEX storage_providers_status_idx ON storage_providers (status_id);
+
+CREATE TABLE IF NOT EXISTS
+  storage_provider_prices (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    storage_provider_id integer NOT NULL REFERENCES storage_providers (id),
+    price text NOT NULL,
+    created_at timestamp NOT NULL
+  );
+
+CREATE INDEX storage_provider_prices_storage_provider_idx ON storage_provider_prices (storage_provider_id, created_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS redemptions;
+DROP TABLE IF EXISTS escrow;
+DROP TABLE IF EXISTS authorization_modes;
+DROP TABLE IF EXISTS authorization_status;
@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS
+  authorization_status (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  authorization_status (id, name)
+VALUES
+  (1, 'Open'),
+  (2, 'Locked'),
+  (3, 'Redeemed'),
+  (4, 'Refunded'),
+  (5, 'Cancelled');
+
+CREATE TABLE IF NOT EXISTS
+  authorization_modes (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  authorization_modes (id, name)
+VALUES
+  (1, 'Single'),
+  (2, 'Multi');
+
+CREATE TABLE IF NOT EXISTS
+  escrow (
+    id integer REFERENCES accounts (id),
+    uuid text PRIMARY KEY NOT NULL,
+    balance text NOT NULL DEFAULT '0',
+    proxy text NOT NULL,
+    status_id integer NOT NULL DEFAULT 1 REFERENCES authorization_status (id),
+    max_price text,
+    mode_id integer NOT NULL DEFAULT 1 REFERENCES authorization_modes (id),
+    amount text NOT NULL,
+    expires_at timestamp NOT NULL,
+    price text NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
+
+CREATE INDEX escrow_account_idx ON escrow (id);
+CREATE INDEX escrow_proxy_idx ON escrow (proxy);
+CREATE INDEX escrow_status_idx ON escrow (status_id);
+CREATE INDEX escrow_expires_at_idx ON escrow (expires_at);
+
+CREATE TABLE IF NOT EXISTS
+  redemptions (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    authorization_uuid text NOT NULL,
+    transaction_id text NOT NULL,
+    proxy text NOT NULL,
+    value text NOT NULL DEFAULT '0',
+    remaining text NOT NULL DEFAULT '0',
+    created_at timestamp NOT NULL
+  );
+
+CREATE INDEX redemptions_authorization_uuid_idx ON redemptions (authorization_uuid);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS transaction_types;
+DROP TABLE IF EXISTS transaction_status;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS
+  transaction_status (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  transaction_status (id, name)
+VALUES
+  (1, 'Pending'),
+  (2, 'Completed'),
+  (3, 'Submitted'),
+  (4, 'Reversed');
+
+CREATE TABLE IF NOT EXISTS
+  transaction_types (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  transaction_types (id, name)
+VALUES
+  (1, 'Deposit'),
+  (2, 'Withdraw'),
+  (3, 'Authorize'),
+  (4, 'Redeem'),
+  (5, 'Refund');
+
+CREATE TABLE IF NOT EXISTS
+  transactions (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    transaction_id text NOT NULL,
+    source text NOT NULL,
+    destination text NOT NULL,
+    value text NOT NULL DEFAULT '0',
+    status_id integer NOT NULL DEFAULT 1 REFERENCES transaction_status (id),
+    wallet_address text,
+    counterpart text,
+    type_id integer REFERENCES transaction_types (id),
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
+
+CREATE INDEX transaction_status_idx ON transactions (status_id);
+CREATE UNIQUE INDEX transaction_id_idx ON transactions (transaction_id);
+CREATE INDEX transactions_wallet_address_idx ON transactions (wallet_address, id);
+CREATE INDEX transactions_counterpart_idx ON transactions (counterpart, id);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS
+  withdrawals (
+    id text PRIMARY KEY NOT NULL,
+    wallet_address text NOT NULL,
+    destination text NOT NULL,
+    value text NOT NULL DEFAULT '0',
+    fee text NOT NULL DEFAULT '0',
+    status_id integer NOT NULL DEFAULT 1 REFERENCES transaction_status (id),
+    hash text,
+    raw_transaction blob,
+    submitted_at timestamp,
+    locked_until timestamp,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
+
+CREATE INDEX withdrawals_status_idx ON withdrawals (status_id, created_at);
+CREATE INDEX withdrawals_wallet_address_idx ON withdrawals (wallet_address);
+CREATE UNIQUE INDEX withdrawals_hash_idx ON withdrawals (hash);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS
+  fees (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    transaction_id text NOT NULL,
+    wallet_address text NOT NULL,
+    type_id integer NOT NULL REFERENCES transaction_types (id),
+    value text NOT NULL,
+    refunded_at timestamp,
+    created_at timestamp NOT NULL
+  );
+
+CREATE INDEX fees_created_at_idx ON fees (created_at);
+CREATE INDEX fees_transaction_id_idx ON fees (transaction_id);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS ledger_entries_immutable_delete;
+DROP TRIGGER IF EXISTS ledger_entries_immutable_update;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_books;
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS
+  ledger_books (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  ledger_books (id, name)
+VALUES
+  (1, 'Wallet'),
+  (2, 'Balance'),
+  (3, 'Escrow'),
+  (4, 'Withdrawal'),
+  (5, 'Revenue');
+
+-- Journals are checked to balance when they are posted, as SQLite has no deferred triggers.
+CREATE TABLE IF NOT EXISTS
+  ledger_entries (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    journal_id text NOT NULL,
+    transaction_id text,
+    wallet_address text NOT NULL,
+    book_id integer NOT NULL REFERENCES ledger_books (id),
+    debit text NOT NULL DEFAULT '0',
+    credit text NOT NULL DEFAULT '0',
+    created_at timestamp NOT NULL,
+    CHECK ((debit = '0') <> (credit = '0'))
+  );
+
+CREATE INDEX ledger_entries_journal_idx ON ledger_entries (journal_id);
+CREATE INDEX ledger_entries_account_idx ON ledger_entries (wallet_address, book_id, created_at);
+
+-- The ledger is append only, corrections are posted as new journals.
+CREATE TRIGGER ledger_entries_immutable_update
+  BEFORE UPDATE ON ledger_entries
+BEGIN
+  SELECT RAISE(ABORT, 'ledger entries cannot be modified');
+END;
+
+CREATE TRIGGER ledger_entries_immutable_delete
+  BEFORE DELETE ON ledger_entries
+BEGIN
+  SELECT RAISE(ABORT, 'ledger entries cannot be modified');
+END;
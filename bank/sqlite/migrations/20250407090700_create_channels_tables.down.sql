@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS channels;
+DROP TABLE IF EXISTS channel_status;
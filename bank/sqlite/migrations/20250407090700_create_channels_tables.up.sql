@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS
+  channel_status (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  channel_status (id, name)
+VALUES
+  (1, 'Open'),
+  (2, 'Closing'),
+  (3, 'Closed');
+
+CREATE TABLE IF NOT EXISTS
+  channels (
+    uuid text PRIMARY KEY NOT NULL,
+    wallet_address text NOT NULL,
+    proxy text NOT NULL,
+    balance text NOT NULL DEFAULT '0',
+    redeemed text NOT NULL DEFAULT '0',
+    status_id integer NOT NULL DEFAULT 1 REFERENCES channel_status (id),
+    closes_at timestamp,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
+
+CREATE INDEX channels_wallet_address_idx ON channels (wallet_address);
+CREATE INDEX channels_proxy_idx ON channels (proxy);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS
+  request_nonces (
+    wallet_address text NOT NULL,
+    nonce text NOT NULL,
+    expires_at timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (wallet_address, nonce)
+  );
+
+CREATE INDEX request_nonces_expires_at_idx ON request_nonces (expires_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS
+  idempotency_keys (
+    wallet_address text NOT NULL,
+    key text NOT NULL,
+    request_hash text NOT NULL,
+    status_code integer,
+    response blob,
+    expires_at timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY (wallet_address, key)
+  );
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS
+  admin_audit_log (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    operator text NOT NULL,
+    action text NOT NULL,
+    target text NOT NULL,
+    reason text NOT NULL,
+    created_at timestamp NOT NULL
+  );
+
+CREATE INDEX admin_audit_log_operator_idx ON admin_audit_log (operator);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS accounts_status_idx;
+ALTER TABLE accounts DROP COLUMN status_id;
+DROP TABLE IF EXISTS account_status;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS
+  account_status (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  account_status (id, name)
+VALUES
+  (1, 'Active'),
+  (2, 'Frozen'),
+  (3, 'Closed');
+
+-- SQLite cannot add a column that references another table with a default other than NULL, so the status
+-- is checked instead.
+ALTER TABLE accounts ADD COLUMN status_id integer NOT NULL DEFAULT 1 CHECK (status_id IN (1, 2, 3));
+
+CREATE INDEX accounts_status_idx ON accounts (status_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS
+  rate_limit_buckets (
+    key text PRIMARY KEY,
+    tokens real NOT NULL,
+    updated_at timestamp NOT NULL,
+    expires_at timestamp NOT NULL
+  );
+
+CREATE INDEX rate_limit_buckets_expires_at_idx ON rate_limit_buckets (expires_at);
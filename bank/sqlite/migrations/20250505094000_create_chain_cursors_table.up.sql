@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS
+  chain_cursors (
+    name text PRIMARY KEY,
+    block integer NOT NULL,
+    updated_at timestamp NOT NULL
+  );
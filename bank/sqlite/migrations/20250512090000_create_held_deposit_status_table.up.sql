@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS
+  held_deposit_status (
+    id integer PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+  );
+
+INSERT INTO
+  held_deposit_status (id, name)
+VALUES
+  (1, 'Held'),
+  (2, 'Credited'),
+  (3, 'Returned');
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS
+  held_deposits (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    hash text NOT NULL,
+    sender text NOT NULL,
+    wallet_address text,
+    value text NOT NULL,
+    reason text NOT NULL,
+    status_id integer NOT NULL DEFAULT 1 REFERENCES held_deposit_status (id),
+    credited_to text,
+    return_hash text,
+    resolved_at timestamp,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+  );
+
+CREATE UNIQUE INDEX held_deposits_hash_idx ON held_deposits (hash);
+CREATE INDEX held_deposits_return_hash_idx ON held_deposits (return_hash);
+CREATE INDEX held_deposits_status_idx ON held_deposits (status_id);
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+)
+
+// TakeToken keeps the token buckets in the database, so that they outlive restarts of the bank. A bucket
+// that is empty is only read, so that the requests it refuses do not wait for the database's write lock.
+func (s BankService) TakeToken(ctx context.Context, key string, limit bank.RateLimit) (time.Duration, error) {
+	var wait time.Duration
+
+	insertBucketQuery :=
+		`
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+		VALUES (?1, ?2, ?3, ?3)
+		ON CONFLICT (key) DO NOTHING
+		`
+
+	bucketQuery :=
+		`
+		SELECT tokens, updated_at
+		FROM rate_limit_buckets
+		WHERE key = ?1
+		`
+
+	updateBucketQuery :=
+		`
+		UPDATE rate_limit_buckets
+			SET tokens = ?2,
+				updated_at = ?3,
+				expires_at = ?4
+			WHERE key = ?1
+		`
+
+	var tokens float64
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx, bucketQuery, key).Scan(&tokens, &updatedAt)
+	switch {
+	case err == nil:
+		if _, wait := limit.Take(tokens, time.Now().UTC().Sub(updatedAt)); wait > 0 {
+			return wait, nil
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, fmt.Errorf("failed to fetch rate limit bucket: %w", err)
+	}
+
+	err = Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		if _, err := tx.ExecContext(ctx, insertBucketQuery, key, limit.Requests, now); err != nil {
+			return fmt.Errorf("failed to add rate limit bucket: %w", err)
+		}
+
+		if err := tx.QueryRowContext(ctx, bucketQuery, key).Scan(&tokens, &updatedAt); err != nil {
+			return fmt.Errorf("failed to fetch rate limit bucket: %w", err)
+		}
+
+		// The bucket was emptied by a concurrent request since it was read, and is left as it is.
+		tokens, wait = limit.Take(tokens, now.Sub(updatedAt))
+		if wait > 0 {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, updateBucketQuery, key, tokens, now, now.Add(limit.Full(tokens))); err != nil {
+			return fmt.Errorf("failed to update rate limit bucket: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return wait, nil
+}
+
+// PruneBuckets deletes the buckets that were full again before the given time.
+func (s BankService) PruneBuckets(ctx context.Context, before time.Time) (int, error) {
+	query :=
+		`
+		DELETE FROM rate_limit_buckets WHERE expires_at < ?1
+		`
+
+	res, err := s.db.ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired rate limit buckets: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rows), nil
+}
@@ -0,0 +1,217 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// Redeem pays a storage provider from a locked authorization. A single authorization is marked redeemed and its
+// excess returned to the client, while a multi authorization keeps the remaining amount and is unlocked for the
+// next retrieval, until it is used up. The storage provider is paid the amount less the redeem fee.
+func (s BankService) Redeem(ctx context.Context, address string, id uuid.UUID, amount types.FIL) (bank.RedeemModel, error) {
+	var spBalance types.FIL
+	var cliBalance types.FIL
+	var excess types.FIL
+	var remaining types.FIL
+	var fee types.FIL
+	var model bank.RedeemModel
+
+	verifyAuthQuery :=
+		`
+		SELECT *
+		FROM escrow
+		WHERE uuid = ?1
+		  AND proxy = ?2
+		  AND expires_at > ?3
+		  AND status_id = ?4
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), ?8, ?9, ?9)
+		`
+
+	redemptionQuery :=
+		`
+		INSERT INTO redemptions (authorization_uuid, transaction_id, proxy, value, remaining, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		`
+
+	drawDownAuthQuery :=
+		`
+		UPDATE escrow
+			SET balance = ?2,
+				status_id = ?3,
+				updated_at = ?4
+			WHERE uuid = ?1
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationRedeem); err != nil {
+			return err
+		}
+
+		if account.Type != StorageProvider {
+			return bank.ErrOperationNotAllowed
+		}
+
+		excess = types.NewFIL(new(big.Int))
+		remaining = types.NewFIL(new(big.Int))
+
+		var auth Authorization
+
+		args := []any{id, address, now, AuthorizationLocked}
+		if err := tx.GetContext(ctx, &auth, verifyAuthQuery, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthNotFound
+			}
+
+			return fmt.Errorf("failed to fetch authorization: %w", err)
+		}
+
+		// The amount must be covered by the authorization, and not be above the price it was made at.
+		if amount.Cmp(auth.Balance.Int) == 1 || amount.Cmp(auth.Price.Int) == 1 {
+			return bank.ErrAuthNotFound
+		}
+
+		client, err := getAccountByID(ctx, auth.ID, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch cli account: %w", err)
+		}
+
+		fee = types.NewFIL(s.cfg.Fees.Redeem.Charge(amount.Int))
+		paid := new(big.Int).Sub(amount.Int, fee.Int)
+
+		spBalance, _, err = updateBalances(ctx, tx, account.ID, paid, new(big.Int), now)
+		if err != nil {
+			return fmt.Errorf("failed to deposit balance to sp: %w", err)
+		}
+
+		transactionID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate v7 uuid: %w", err)
+		}
+
+		args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, amount.Int.String(), TransactionCompleted, client.Address, address, TransactionRedeem, now}
+		if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register transaction during sp deposit: %w", err)
+		}
+
+		err = postJournal(ctx, tx, transactionID.String(), now,
+			debit(client.Address, LedgerEscrow, amount.Int),
+			credit(address, LedgerBalance, paid),
+			credit(s.cfg.WalletAddress, LedgerRevenue, fee.Int),
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := recordFee(ctx, tx, transactionID.String(), address, TransactionRedeem, fee.Int, now); err != nil {
+			return err
+		}
+
+		released := new(big.Int).Set(auth.Balance.Int)
+
+		switch auth.Mode {
+		case AuthorizationMulti:
+			remaining.Int.Sub(auth.Balance.Int, amount.Int)
+			released.Set(amount.Int)
+
+			status := AuthorizationOpen
+			if remaining.Sign() == 0 {
+				status = AuthorizationRedeemed
+			}
+
+			args = []any{id, remaining.Int.String(), status, now}
+			if _, err := tx.ExecContext(ctx, drawDownAuthQuery, args...); err != nil {
+				return fmt.Errorf("failed to draw down authorization during redeem: %w", err)
+			}
+		default:
+			if auth.Balance.Int.Cmp(amount.Int) == 1 {
+				excess.Int.Sub(auth.Balance.Int, amount.Int)
+
+				transactionID, err := uuid.NewV7()
+				if err != nil {
+					return fmt.Errorf("failed to generate v7 uuid: %w", err)
+				}
+
+				args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, excess.Int.String(), TransactionCompleted, client.Address, address, TransactionRefund, now}
+				if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+					return fmt.Errorf("failed to register transaction during cli deposit: %w", err)
+				}
+
+				err = postJournal(ctx, tx, transactionID.String(), now,
+					debit(client.Address, LedgerEscrow, excess.Int),
+					credit(client.Address, LedgerBalance, excess.Int),
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			args = []any{id, remaining.Int.String(), AuthorizationRedeemed, now}
+			if _, err := tx.ExecContext(ctx, drawDownAuthQuery, args...); err != nil {
+				return fmt.Errorf("failed to close authorization during redeem: %w", err)
+			}
+		}
+
+		args = []any{id, transactionID.String(), address, amount.Int.String(), remaining.Int.String(), now}
+		if _, err := tx.ExecContext(ctx, redemptionQuery, args...); err != nil {
+			return fmt.Errorf("failed to register redemption: %w", err)
+		}
+
+		// The whole authorization leaves the client's escrow, of which the excess goes back to its balance.
+		var cliEscrow types.FIL
+		cliBalance, cliEscrow, err = updateBalances(ctx, tx, auth.ID, excess.Int, new(big.Int).Neg(released), now)
+		if err != nil {
+			return fmt.Errorf("failed to update cli escrow: %w", err)
+		}
+
+		if cliBalance.Sign() == 0 && cliEscrow.Sign() == 0 {
+			if err := deleteEmptyClient(ctx, tx, auth.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := checkLedger(ctx, tx, address); err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, client.Address); err != nil {
+			return err
+		}
+
+		model = bank.RedeemModel{
+			Excess:    excess,
+			Remaining: remaining,
+			Fee:       fee,
+			SP:        spBalance,
+			CLI:       cliBalance,
+		}
+
+		return respond(ctx, tx, model)
+	})
+	if err != nil {
+		return bank.RedeemModel{}, err
+	}
+
+	return model, nil
+}
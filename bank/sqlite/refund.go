@@ -0,0 +1,159 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+func (s BankService) Refund(ctx context.Context, address string) (bank.RefundModel, error) {
+	var refund bank.RefundModel
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationRefund); err != nil {
+			return err
+		}
+
+		refund, err = s.refundExpired(ctx, tx, account, time.Now().UTC())
+
+		return err
+	})
+	if err != nil {
+		return bank.RefundModel{}, err
+	}
+
+	return refund, nil
+}
+
+// SweepEscrow refunds the expired escrow of up to limit accounts, each in its own transaction.
+// An account that fails to be refunded does not stop the others from being swept.
+func (s BankService) SweepEscrow(ctx context.Context, limit int) (bank.SweepReport, error) {
+	var ids []int64
+
+	expiredAccountsQuery :=
+		`
+		SELECT DISTINCT id
+		FROM escrow
+		WHERE expires_at <= ?1
+		  AND status_id IN (?2, ?3)
+		LIMIT ?4
+		`
+
+	now := time.Now().UTC()
+
+	if err := s.db.SelectContext(ctx, &ids, expiredAccountsQuery, now, AuthorizationOpen, AuthorizationLocked, limit); err != nil {
+		return bank.SweepReport{}, fmt.Errorf("failed to fetch accounts with expired escrow: %w", err)
+	}
+
+	report := bank.SweepReport{Swept: types.NewFIL(new(big.Int))}
+
+	var errs []error
+	for _, id := range ids {
+		var refund bank.RefundModel
+
+		err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+			account, err := getAccountByID(ctx, id, tx)
+			if err != nil {
+				return fmt.Errorf("failed to fetch account: %w", err)
+			}
+
+			refund, err = s.refundExpired(ctx, tx, account, now)
+
+			return err
+		})
+		if err != nil {
+			// The client refunded the account in the meantime.
+			if errors.Is(err, bank.ErrNothingToRefund) {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("account %d: %w", id, err))
+
+			continue
+		}
+
+		report.Accounts++
+		report.Swept.Int.Add(report.Swept.Int, refund.Expired.Int)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// refundExpired moves the escrow of an account that expired by the given time back to its balance.
+func (s BankService) refundExpired(ctx context.Context, tx fidl.Queryable, account *Account, now time.Time) (bank.RefundModel, error) {
+	var balance types.FIL
+	var escrow types.FIL
+
+	refundExpiredQuery :=
+		`
+		UPDATE escrow
+			SET status_id = ?3,
+				updated_at = ?2
+			WHERE id = ?1
+			AND expires_at <= ?2
+			AND status_id IN (?4, ?5)
+			RETURNING balance
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), ?8, ?9, ?9)
+		`
+
+	args := []any{account.ID, now, AuthorizationRefunded, AuthorizationOpen, AuthorizationLocked}
+	expiredSum, err := sum(ctx, tx, refundExpiredQuery, args...)
+	if err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to refund expired authorizations: %w", err)
+	}
+
+	if expiredSum.Sign() == 0 {
+		return bank.RefundModel{}, bank.ErrNothingToRefund
+	}
+
+	balance, escrow, err = updateBalances(ctx, tx, account.ID, expiredSum.Int, new(big.Int).Neg(expiredSum.Int), now)
+	if err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to update balances: %w", err)
+	}
+
+	transactionID, err := uuid.NewV7()
+	if err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	args = []any{transactionID.String(), s.cfg.EscrowAddress, s.cfg.WalletAddress, expiredSum.Int.String(), TransactionCompleted, account.Address, "", TransactionRefund, now}
+	if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+		return bank.RefundModel{}, fmt.Errorf("failed to register transaction during refund: %w", err)
+	}
+
+	err = postJournal(ctx, tx, transactionID.String(), now,
+		debit(account.Address, LedgerEscrow, expiredSum.Int),
+		credit(account.Address, LedgerBalance, expiredSum.Int),
+	)
+	if err != nil {
+		return bank.RefundModel{}, err
+	}
+
+	if err := checkLedger(ctx, tx, account.Address); err != nil {
+		return bank.RefundModel{}, err
+	}
+
+	return bank.RefundModel{
+		Expired:   expiredSum,
+		Available: balance,
+		Escrow:    escrow,
+	}, nil
+}
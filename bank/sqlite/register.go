@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// RegisterProxy registers a storage provider, or updates the id and price of one already registered.
+// Every price it is registered with is recorded in its price history. Registering again reactivates a
+// storage provider once its deregistration is finalized, but not while its channels are still closing.
+func (s BankService) RegisterProxy(ctx context.Context, spid string, walletAddress string, price types.FIL) error {
+	currentQuery :=
+		`
+		SELECT price, status_id FROM storage_providers
+		WHERE id = ?1
+		`
+
+	spQuery :=
+		`
+		INSERT INTO storage_providers (id, sp_id, price, status_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?5)
+		ON CONFLICT (id) DO UPDATE
+			SET sp_id = excluded.sp_id,
+				price = excluded.price,
+				status_id = ?4,
+				payout_address = NULL,
+				deregistered_at = NULL,
+				updated_at = ?5
+		`
+
+	priceHistoryQuery :=
+		`
+		INSERT INTO storage_provider_prices (storage_provider_id, price, created_at)
+		VALUES (?1, ?2, ?3)
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		account, err := insertAccount(ctx, tx, walletAddress, StorageProvider, now)
+		if err != nil {
+			return err
+		}
+
+		// The wallet already has an account, of a client.
+		if account.Type != StorageProvider {
+			return bank.ErrOperationNotAllowed
+		}
+
+		if err := s.usable(account, bank.OperationRegister); err != nil {
+			return err
+		}
+
+		var current types.FIL
+		var status StorageProviderStatus
+		err = tx.QueryRowContext(ctx, currentQuery, account.ID).Scan(&current, &status)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to fetch storage provider: %w", err)
+		}
+
+		if status == StorageProviderDeregistering {
+			return bank.ErrProxyNotActive
+		}
+
+		args := []any{account.ID, spid, price.Int.String(), StorageProviderActive, now}
+		if _, err := tx.ExecContext(ctx, spQuery, args...); err != nil {
+			return fmt.Errorf("failed to add storage provider entry: %w", err)
+		}
+
+		if current.Int == nil || current.Cmp(price.Int) != 0 {
+			args = []any{account.ID, price.Int.String(), now}
+			if _, err := tx.ExecContext(ctx, priceHistoryQuery, args...); err != nil {
+				return fmt.Errorf("failed to record storage provider price: %w", err)
+			}
+		}
+
+		return respond(ctx, tx, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type LiabilityEntry struct {
+	Address string    `db:"wallet_address"`
+	Amount  types.FIL `db:"amount"`
+}
+
+// Liabilities lists what the bank owes each wallet, ordered by address. Withdrawals count until they are
+// paid out, and held deposits until they are credited or returned, as their funds are still in the bank's
+// wallet. A held deposit is owed to its sender.
+func (s BankService) Liabilities(ctx context.Context) ([]bank.Liability, error) {
+	var entries []LiabilityEntry
+
+	balancesQuery :=
+		`
+		SELECT a.wallet_address, b.balance AS amount
+		FROM accounts a
+		JOIN balances b ON b.id = a.id
+		UNION ALL
+		SELECT a.wallet_address, b.escrow
+		FROM accounts a
+		JOIN balances b ON b.id = a.id
+		`
+
+	withdrawalsQuery :=
+		`
+		SELECT wallet_address, value AS amount
+		FROM withdrawals
+		WHERE status_id IN (?1, ?2)
+		`
+
+	heldQuery :=
+		`
+		SELECT sender AS wallet_address, value AS amount
+		FROM held_deposits
+		WHERE status_id = ?1
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		if err := tx.SelectContext(ctx, &entries, balancesQuery); err != nil {
+			return fmt.Errorf("failed to fetch balances: %w", err)
+		}
+
+		var withdrawals []LiabilityEntry
+		if err := tx.SelectContext(ctx, &withdrawals, withdrawalsQuery, TransactionPending, TransactionSubmitted); err != nil {
+			return fmt.Errorf("failed to fetch withdrawals: %w", err)
+		}
+
+		entries = append(entries, withdrawals...)
+
+		var held []LiabilityEntry
+		if err := tx.SelectContext(ctx, &held, heldQuery, HeldDepositHeld); err != nil {
+			return fmt.Errorf("failed to fetch held deposits: %w", err)
+		}
+
+		entries = append(entries, held...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := make(map[string]*big.Int)
+	for _, e := range entries {
+		if _, ok := amounts[e.Address]; !ok {
+			amounts[e.Address] = new(big.Int)
+		}
+
+		amounts[e.Address].Add(amounts[e.Address], e.Amount.Int)
+	}
+
+	liabilities := make([]bank.Liability, 0, len(amounts))
+	for address, amount := range amounts {
+		liabilities = append(liabilities, bank.Liability{Address: address, Amount: types.NewFIL(amount)})
+	}
+
+	sort.Slice(liabilities, func(i, j int) bool {
+		return liabilities[i].Address < liabilities[j].Address
+	})
+
+	return liabilities, nil
+}
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 driver
+	"github.com/subvisual/fidl"
+)
+
+type Config struct {
+	Dsn          string
+	MaxOpenConns int
+	MaxIdleConns int
+	MaxIdleTime  string
+}
+
+type DB struct {
+	*sqlx.DB
+}
+
+// connectionParams make every transaction take the database's write lock when it begins, so that
+// transactions run one after the other and the balances they read cannot change before they are written.
+// Transactions wait on each other for up to the busy timeout.
+const connectionParams = "_txlock=immediate&_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL"
+
+func Connect(cfg Config) *DB {
+	dsn := cfg.Dsn
+	if strings.Contains(dsn, "?") {
+		dsn += "&" + connectionParams
+	} else {
+		dsn += "?" + connectionParams
+	}
+
+	dbx, err := sqlx.Connect("sqlite3", dsn)
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+
+	db := &DB{DB: dbx}
+
+	duration, err := time.ParseDuration(cfg.MaxIdleTime)
+	if err != nil {
+		log.Fatalf("Failed to parse duration: %v", err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxIdleTime(duration)
+
+	return db
+}
+
+// Transaction runs fn in a transaction that holds the database's write lock until it commits or rolls back.
+// fn must only use the given Queryable, as anything else writing to the database would wait for it.
+func Transaction(ctx context.Context, db *DB, fn func(fidl.Queryable) error) (err error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		p := recover()
+
+		switch {
+		case p != nil:
+			_ = tx.Rollback()
+			panic(p)
+		case err != nil:
+			_ = tx.Rollback()
+		default:
+			err = tx.Commit()
+		}
+	}()
+
+	err = fn(tx)
+
+	return err
+}
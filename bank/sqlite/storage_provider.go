@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/types"
+)
+
+type StorageProviderStatus int8
+
+const (
+	StorageProviderActive StorageProviderStatus = iota + 1
+	StorageProviderDeregistering
+	StorageProviderInactive
+)
+
+func (s StorageProviderStatus) String() string {
+	switch s {
+	case StorageProviderActive:
+		return "Active"
+	case StorageProviderDeregistering:
+		return "Deregistering"
+	case StorageProviderInactive:
+		return "Inactive"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+type Provider struct {
+	ID             int64                 `db:"id"`
+	SPID           string                `db:"sp_id"`
+	Price          types.FIL             `db:"price"`
+	Status         StorageProviderStatus `db:"status_id"`
+	PayoutAddress  sql.NullString        `db:"payout_address"`
+	DeregisteredAt sql.NullTime          `db:"deregistered_at"`
+	CreatedAt      time.Time             `db:"created_at"`
+	UpdatedAt      time.Time             `db:"updated_at"`
+}
+
+func getProvider(ctx context.Context, id int64, tx fidl.Queryable) (*Provider, error) {
+	var sp Provider
+
+	query :=
+		`
+		SELECT *
+		FROM storage_providers
+		WHERE id = ?1
+		`
+
+	if err := tx.GetContext(ctx, &sp, query, id); err != nil {
+		return nil, fmt.Errorf("failed to fetch storage provider: %w", err)
+	}
+
+	return &sp, nil
+}
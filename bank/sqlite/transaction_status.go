@@ -0,0 +1,25 @@
+package sqlite
+
+type TransactionStatus int8
+
+const (
+	TransactionPending TransactionStatus = iota + 1
+	TransactionCompleted
+	TransactionSubmitted
+	TransactionReversed
+)
+
+func (a TransactionStatus) String() string {
+	switch a {
+	case TransactionPending:
+		return "Pending"
+	case TransactionCompleted:
+		return "Completed"
+	case TransactionSubmitted:
+		return "Submitted"
+	case TransactionReversed:
+		return "Reversed"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import "strings"
+
+type TransactionType int8
+
+const (
+	TransactionDeposit TransactionType = iota + 1
+	TransactionWithdraw
+	TransactionAuthorize
+	TransactionRedeem
+	TransactionRefund
+)
+
+func (t TransactionType) String() string {
+	switch t {
+	case TransactionDeposit:
+		return "Deposit"
+	case TransactionWithdraw:
+		return "Withdraw"
+	case TransactionAuthorize:
+		return "Authorize"
+	case TransactionRedeem:
+		return "Redeem"
+	case TransactionRefund:
+		return "Refund"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+func parseTransactionType(name string) (TransactionType, bool) {
+	for t := TransactionDeposit; t <= TransactionRefund; t++ {
+		if strings.EqualFold(t.String(), name) {
+			return t, true
+		}
+	}
+
+	return 0, false
+}
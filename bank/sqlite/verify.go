@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+// Verify locks an open authorization for a retrieval by its storage provider, and returns the price the
+// retrieval is charged at: the provider's price when the authorization was made.
+func (s BankService) Verify(ctx context.Context, address string, uuid uuid.UUID) (types.FIL, error) {
+	var auth Authorization
+
+	getAuthQuery :=
+		`
+		SELECT *
+		FROM escrow
+		WHERE uuid = ?1
+		  AND proxy = ?2
+		  AND expires_at > ?3
+		  AND status_id IN (?4, ?5)
+		`
+
+	updateAuthQuery :=
+		`
+		UPDATE escrow
+  			SET status_id = ?3,
+				updated_at = ?4
+  			WHERE uuid = ?1
+		  	  AND proxy = ?2
+			  AND status_id = ?5
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationVerify); err != nil {
+			return err
+		}
+
+		if account.Type != StorageProvider {
+			return bank.ErrOperationNotAllowed
+		}
+
+		args := []any{uuid, address, now, AuthorizationOpen, AuthorizationLocked}
+		if err := tx.GetContext(ctx, &auth, getAuthQuery, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthNotFound
+			}
+
+			return fmt.Errorf("failed to fetch authorization: %w", err)
+		}
+
+		// A multi authorization drawn down below the price cannot pay for another retrieval.
+		if auth.Balance.Cmp(auth.Price.Int) == -1 {
+			return bank.ErrAuthNotFound
+		}
+
+		if auth.Status == AuthorizationLocked {
+			return bank.ErrAuthLocked
+		}
+
+		args = []any{uuid, address, AuthorizationLocked, now, AuthorizationOpen}
+		if err := execOne(ctx, tx, updateAuthQuery, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return bank.ErrAuthLocked
+			}
+
+			return fmt.Errorf("failed to update authorization status: %w", err)
+		}
+
+		return respond(ctx, tx, auth.Price)
+	})
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	return auth.Price, nil
+}
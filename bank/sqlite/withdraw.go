@@ -0,0 +1,407 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/types"
+)
+
+type Withdrawal struct {
+	ID          uuid.UUID         `db:"id"`
+	Address     string            `db:"wallet_address"`
+	Destination string            `db:"destination"`
+	Value       types.FIL         `db:"value"`
+	Fee         types.FIL         `db:"fee"`
+	Status      TransactionStatus `db:"status_id"`
+	Hash        sql.NullString    `db:"hash"`
+	Raw         []byte            `db:"raw_transaction"`
+	SubmittedAt sql.NullTime      `db:"submitted_at"`
+	LockedUntil sql.NullTime      `db:"locked_until"`
+	CreatedAt   time.Time         `db:"created_at"`
+	UpdatedAt   time.Time         `db:"updated_at"`
+}
+
+func (w Withdrawal) Model() bank.Withdrawal {
+	return bank.Withdrawal{
+		ID:          w.ID,
+		Address:     w.Address,
+		Destination: w.Destination,
+		Amount:      w.Value,
+		Fee:         w.Fee,
+		Status:      w.Status.String(),
+		Hash:        w.Hash.String,
+		Raw:         w.Raw,
+		SubmittedAt: w.SubmittedAt.Time,
+		CreatedAt:   w.CreatedAt,
+	}
+}
+
+// Withdraw debits an amount from the balance of an account, of which the withdrawal fee is kept and the
+// rest is paid out to the destination.
+func (s BankService) Withdraw(ctx context.Context, address string, destination string, amount types.FIL) (bank.WithdrawModel, error) {
+	var balance types.FIL
+	var fee types.FIL
+
+	if destination == s.cfg.WalletAddress {
+		return bank.WithdrawModel{}, bank.ErrOperationNotAllowed
+	}
+
+	withdrawalID, err := uuid.NewV7()
+	if err != nil {
+		return bank.WithdrawModel{}, fmt.Errorf("failed to generate v7 uuid: %w", err)
+	}
+
+	err = Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		account, err := getAccountByAddress(ctx, address, tx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch account: %w", err)
+		}
+
+		if err := s.usable(account, bank.OperationWithdraw); err != nil {
+			return err
+		}
+
+		var escrow types.FIL
+		balance, escrow, err = updateBalances(ctx, tx, account.ID, new(big.Int).Neg(amount.Int), new(big.Int), now)
+		if err != nil {
+			return err
+		}
+
+		if account.Type == Client && balance.Sign() == 0 && escrow.Sign() == 0 {
+			if err := deleteEmptyClient(ctx, tx, account.ID); err != nil {
+				return err
+			}
+		}
+
+		fee, err = s.registerWithdrawal(ctx, tx, withdrawalID, address, destination, amount, now)
+		if err != nil {
+			return err
+		}
+
+		if err := checkLedger(ctx, tx, address); err != nil {
+			return err
+		}
+
+		return respond(ctx, tx, bank.WithdrawModel{ID: withdrawalID, Available: balance, Fee: fee})
+	})
+	if err != nil {
+		return bank.WithdrawModel{}, err
+	}
+
+	return bank.WithdrawModel{ID: withdrawalID, Available: balance, Fee: fee}, nil
+}
+
+// registerWithdrawal records a pending withdrawal of an amount already debited from the balance of an
+// account, for the withdrawal worker to pay out. The withdrawal fee is taken from the amount, and returned.
+func (s BankService) registerWithdrawal(ctx context.Context, tx fidl.Queryable, id uuid.UUID, address string, destination string, amount types.FIL, now time.Time) (types.FIL, error) {
+	withdrawalQuery :=
+		`
+		INSERT INTO withdrawals (id, wallet_address, destination, value, fee, status_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?7)
+		`
+
+	// nolint:goconst
+	transactionQuery :=
+		`
+		INSERT INTO transactions (transaction_id, source, destination, value, status_id, wallet_address, counterpart, type_id, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), ?8, ?9, ?9)
+		`
+
+	fee := s.cfg.Fees.Withdraw.Charge(amount.Int)
+	if fee.Sign() == 1 && fee.Cmp(amount.Int) == 0 {
+		return types.FIL{}, bank.ErrAmountBelowFee
+	}
+
+	value := new(big.Int).Sub(amount.Int, fee)
+
+	args := []any{id, address, destination, value.String(), fee.String(), TransactionPending, now}
+	if _, err := tx.ExecContext(ctx, withdrawalQuery, args...); err != nil {
+		return types.FIL{}, fmt.Errorf("failed to register withdrawal: %w", err)
+	}
+
+	args = []any{id.String(), s.cfg.WalletAddress, destination, value.String(), TransactionPending, address, destination, TransactionWithdraw, now}
+	if _, err := tx.ExecContext(ctx, transactionQuery, args...); err != nil {
+		return types.FIL{}, fmt.Errorf("failed to register transaction during withdraw: %w", err)
+	}
+
+	err := postJournal(ctx, tx, id.String(), now,
+		debit(address, LedgerBalance, amount.Int),
+		credit(address, LedgerWithdrawal, value),
+		credit(s.cfg.WalletAddress, LedgerRevenue, fee),
+	)
+	if err != nil {
+		return types.FIL{}, err
+	}
+
+	if err := recordFee(ctx, tx, id.String(), address, TransactionWithdraw, fee, now); err != nil {
+		return types.FIL{}, err
+	}
+
+	return types.NewFIL(fee), nil
+}
+
+func (s BankService) Withdrawal(ctx context.Context, address string, id uuid.UUID) (bank.Withdrawal, error) {
+	var withdrawal Withdrawal
+
+	query :=
+		`
+		SELECT *
+		FROM withdrawals
+		WHERE id = ?1
+		AND wallet_address = ?2
+		`
+
+	if err := s.db.GetContext(ctx, &withdrawal, query, id, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return bank.Withdrawal{}, bank.ErrWithdrawalNotFound
+		}
+
+		return bank.Withdrawal{}, fmt.Errorf("failed to fetch withdrawal: %w", err)
+	}
+
+	return withdrawal.Model(), nil
+}
+
+// ClaimWithdrawals leases a batch of unfinished withdrawals to the caller, oldest first, skipping the ones
+// currently leased by other workers.
+func (s BankService) ClaimWithdrawals(ctx context.Context, limit int, lease time.Duration) ([]bank.Withdrawal, error) {
+	var withdrawals []Withdrawal
+
+	claimQuery :=
+		`
+		UPDATE withdrawals
+			SET locked_until = ?2,
+				updated_at = ?1
+			WHERE id IN (
+				SELECT id
+				FROM withdrawals
+				WHERE status_id IN (?3, ?4)
+				AND (locked_until IS NULL OR locked_until < ?1)
+				ORDER BY created_at
+				LIMIT ?5
+			)
+			RETURNING *
+		`
+
+	err := Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		args := []any{now, now.Add(lease), TransactionPending, TransactionSubmitted, limit}
+		if err := tx.SelectContext(ctx, &withdrawals, claimQuery, args...); err != nil {
+			return fmt.Errorf("failed to claim withdrawals: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(withdrawals, func(i, j int) bool {
+		return withdrawals[i].CreatedAt.Before(withdrawals[j].CreatedAt)
+	})
+
+	models := make([]bank.Withdrawal, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		models = append(models, w.Model())
+	}
+
+	return models, nil
+}
+
+// SubmitWithdrawal stores the signed transaction of a pending withdrawal. It must be called
+// before the transaction is broadcast.
+func (s BankService) SubmitWithdrawal(ctx context.Context, id uuid.UUID, hash string, raw []byte) error {
+	submitQuery :=
+		`
+		UPDATE withdrawals
+			SET hash = ?2,
+				raw_transaction = ?3,
+				status_id = ?4,
+				submitted_at = ?6,
+				updated_at = ?6
+			WHERE id = ?1
+			AND status_id = ?5
+		`
+
+	return Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		args := []any{id, hash, raw, TransactionSubmitted, TransactionPending, now}
+		if err := execOne(ctx, tx, submitQuery, args...); err != nil {
+			return fmt.Errorf("failed to submit withdrawal: %w", err)
+		}
+
+		return setTransactionStatus(ctx, tx, id.String(), TransactionSubmitted, now)
+	})
+}
+
+// ResetWithdrawal drops the signed transaction of a withdrawal that can no longer be mined,
+// so that it is signed again.
+func (s BankService) ResetWithdrawal(ctx context.Context, id uuid.UUID) error {
+	resetQuery :=
+		`
+		UPDATE withdrawals
+			SET hash = NULL,
+				raw_transaction = NULL,
+				status_id = ?2,
+				submitted_at = NULL,
+				locked_until = NULL,
+				updated_at = ?4
+			WHERE id = ?1
+			AND status_id = ?3
+		`
+
+	return Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		args := []any{id, TransactionPending, TransactionSubmitted, now}
+		if err := execOne(ctx, tx, resetQuery, args...); err != nil {
+			return fmt.Errorf("failed to reset withdrawal: %w", err)
+		}
+
+		return setTransactionStatus(ctx, tx, id.String(), TransactionPending, now)
+	})
+}
+
+func (s BankService) CompleteWithdrawal(ctx context.Context, id uuid.UUID) error {
+	var withdrawal Withdrawal
+
+	completeQuery :=
+		`
+		UPDATE withdrawals
+			SET status_id = ?2,
+				locked_until = NULL,
+				updated_at = ?4
+			WHERE id = ?1
+			AND status_id = ?3
+			RETURNING *
+		`
+
+	return Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		args := []any{id, TransactionCompleted, TransactionSubmitted, now}
+		if err := tx.GetContext(ctx, &withdrawal, completeQuery, args...); err != nil {
+			return fmt.Errorf("failed to complete withdrawal: %w", err)
+		}
+
+		if err := setTransactionStatus(ctx, tx, id.String(), TransactionCompleted, now); err != nil {
+			return err
+		}
+
+		return postJournal(ctx, tx, id.String(), now,
+			debit(withdrawal.Address, LedgerWithdrawal, withdrawal.Value.Int),
+			credit(s.cfg.WalletAddress, LedgerWallet, withdrawal.Value.Int),
+		)
+	})
+}
+
+// ReverseWithdrawal gives the funds of a withdrawal that was never paid out back to the client, along with its fee.
+func (s BankService) ReverseWithdrawal(ctx context.Context, id uuid.UUID) error {
+	var withdrawal Withdrawal
+
+	reverseQuery :=
+		`
+		UPDATE withdrawals
+			SET status_id = ?2,
+				locked_until = NULL,
+				updated_at = ?5
+			WHERE id = ?1
+			AND status_id IN (?3, ?4)
+			RETURNING *
+		`
+
+	refundFeeQuery :=
+		`
+		UPDATE fees
+			SET refunded_at = ?2
+			WHERE transaction_id = ?1
+			AND refunded_at IS NULL
+		`
+
+	return Transaction(ctx, s.db, func(tx fidl.Queryable) error {
+		now := time.Now().UTC()
+
+		args := []any{id, TransactionReversed, TransactionPending, TransactionSubmitted, now}
+		if err := tx.GetContext(ctx, &withdrawal, reverseQuery, args...); err != nil {
+			return fmt.Errorf("failed to reverse withdrawal: %w", err)
+		}
+
+		account, err := insertAccount(ctx, tx, withdrawal.Address, Client, now)
+		if err != nil {
+			return err
+		}
+
+		refund := new(big.Int).Add(withdrawal.Value.Int, withdrawal.Fee.Int)
+
+		if _, _, err := updateBalances(ctx, tx, account.ID, refund, new(big.Int), now); err != nil {
+			return fmt.Errorf("failed to refund withdrawal balance: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, refundFeeQuery, id.String(), now); err != nil {
+			return fmt.Errorf("failed to refund withdrawal fee: %w", err)
+		}
+
+		if err := setTransactionStatus(ctx, tx, id.String(), TransactionReversed, now); err != nil {
+			return err
+		}
+
+		err = postJournal(ctx, tx, id.String(), now,
+			debit(withdrawal.Address, LedgerWithdrawal, withdrawal.Value.Int),
+			debit(s.cfg.WalletAddress, LedgerRevenue, withdrawal.Fee.Int),
+			credit(withdrawal.Address, LedgerBalance, refund),
+		)
+		if err != nil {
+			return err
+		}
+
+		return checkLedger(ctx, tx, withdrawal.Address)
+	})
+}
+
+func setTransactionStatus(ctx context.Context, tx fidl.Queryable, transactionID string, status TransactionStatus, now time.Time) error {
+	query :=
+		`
+		UPDATE transactions
+			SET status_id = ?2,
+				updated_at = ?3
+			WHERE transaction_id = ?1
+		`
+
+	if _, err := tx.ExecContext(ctx, query, transactionID, status, now); err != nil {
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+
+	return nil
+}
+
+// execOne runs a statement that is expected to change exactly one row.
+func execOne(ctx context.Context, tx fidl.Queryable, query string, args ...any) error {
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err // nolint:wrapcheck
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err // nolint:wrapcheck
+	}
+
+	if n != 1 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package bank
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/subvisual/fidl/types"
+	"go.uber.org/zap"
+)
+
+const sweepBatchSize = 100
+
+// EscrowSweeper periodically returns expired escrow to the balance of the clients that did not
+// ask for a refund. Each authorization is refunded at most once, so several bank replicas may
+// sweep at the same time.
+type EscrowSweeper struct {
+	BankService Service
+	Interval    time.Duration
+	Log         *zap.Logger
+}
+
+func NewEscrowSweeper(bankService Service, interval time.Duration, log *zap.Logger) *EscrowSweeper {
+	return &EscrowSweeper{
+		BankService: bankService,
+		Interval:    interval,
+		Log:         log,
+	}
+}
+
+func (e *EscrowSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep refunds expired escrow in batches until none is left.
+func (e *EscrowSweeper) Sweep(ctx context.Context) SweepReport {
+	total := SweepReport{Swept: types.NewFIL(new(big.Int))}
+
+	for {
+		report, err := e.BankService.SweepEscrow(ctx, sweepBatchSize)
+		if report.Swept.Int != nil {
+			total.Accounts += report.Accounts
+			total.Swept.Int.Add(total.Swept.Int, report.Swept.Int)
+		}
+
+		if err != nil {
+			e.Log.Error("failed to sweep expired escrow", zap.Error(err))
+			break
+		}
+
+		if report.Accounts < sweepBatchSize {
+			break
+		}
+	}
+
+	if total.Accounts > 0 {
+		e.Log.Info("swept expired escrow", zap.Int("accounts", total.Accounts), zap.String("fil", total.Swept.String()))
+	}
+
+	return total
+}
+
+// ChannelSweeper periodically closes the channels nobody closes, so that the escrow of a client that
+// went away does not stay locked. Channels open for longer than their lifetime start closing, and the
+// ones whose settle window is over are closed and refunded.
+type ChannelSweeper struct {
+	BankService Service
+	Interval    time.Duration
+	Log         *zap.Logger
+}
+
+func NewChannelSweeper(bankService Service, interval time.Duration, log *zap.Logger) *ChannelSweeper {
+	return &ChannelSweeper{
+		BankService: bankService,
+		Interval:    interval,
+		Log:         log,
+	}
+}
+
+func (c *ChannelSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep moves stale channels along in batches until none is left, and returns how many it moved.
+func (c *ChannelSweeper) Sweep(ctx context.Context) int {
+	var total int
+
+	for {
+		swept, err := c.BankService.SweepChannels(ctx, sweepBatchSize)
+		total += swept
+
+		if err != nil {
+			c.Log.Error("failed to sweep channels", zap.Error(err))
+			break
+		}
+
+		if swept < sweepBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		c.Log.Info("swept stale channels", zap.Int("channels", total))
+	}
+
+	return total
+}
@@ -0,0 +1,131 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/types"
+	"go.uber.org/zap"
+)
+
+const withdrawalBatchSize = 10
+
+// WithdrawalWorker pays out pending withdrawals on chain. A withdrawal is signed and its hash stored before
+// it is broadcast, so a restarted worker can always tell whether a payout may already be on chain.
+type WithdrawalWorker struct {
+	BankService       Service
+	BlockChainService blockchain.Service
+	Interval          time.Duration
+	Lease             time.Duration
+	Log               *zap.Logger
+
+	wake chan struct{}
+}
+
+func NewWithdrawalWorker(bankService Service, blockChainService blockchain.Service, interval time.Duration, lease time.Duration, log *zap.Logger) *WithdrawalWorker {
+	return &WithdrawalWorker{
+		BankService:       bankService,
+		BlockChainService: blockChainService,
+		Interval:          interval,
+		Lease:             lease,
+		Log:               log,
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the worker up without waiting for the next tick.
+func (w *WithdrawalWorker) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *WithdrawalWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+func (w *WithdrawalWorker) runOnce(ctx context.Context) {
+	withdrawals, err := w.BankService.ClaimWithdrawals(ctx, withdrawalBatchSize, w.Lease)
+	if err != nil {
+		w.Log.Error("failed to claim withdrawals", zap.Error(err))
+		return
+	}
+
+	for _, withdrawal := range withdrawals {
+		if err := w.process(ctx, withdrawal); err != nil {
+			w.Log.Error("failed to process withdrawal", zap.String("id", withdrawal.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+func (w *WithdrawalWorker) process(ctx context.Context, withdrawal Withdrawal) error {
+	if withdrawal.Hash == "" {
+		return w.submit(ctx, withdrawal)
+	}
+
+	status, err := w.BlockChainService.TransferStatus(ctx, withdrawal.Hash)
+	if err != nil {
+		return err // nolint:wrapcheck
+	}
+
+	switch status {
+	case blockchain.TransactionSucceeded:
+		w.Log.Info("withdrawal completed", zap.String("id", withdrawal.ID.String()), zap.String("hash", withdrawal.Hash))
+		return w.BankService.CompleteWithdrawal(ctx, withdrawal.ID)
+	case blockchain.TransactionFailed:
+		w.Log.Warn("withdrawal failed on chain, reversing", zap.String("id", withdrawal.ID.String()), zap.String("hash", withdrawal.Hash))
+		return w.BankService.ReverseWithdrawal(ctx, withdrawal.ID)
+	case blockchain.TransactionUnknown:
+	}
+
+	err = w.BlockChainService.SendRawTransfer(ctx, withdrawal.Raw)
+	if errors.Is(err, blockchain.ErrNonceTooLow) {
+		// The nonce was used by another transaction, so this one can never be mined, unless it just was.
+		status, err := w.BlockChainService.TransferStatus(ctx, withdrawal.Hash)
+		if err != nil || status != blockchain.TransactionUnknown {
+			return err // nolint:wrapcheck
+		}
+
+		w.Log.Warn("withdrawal superseded, signing again", zap.String("id", withdrawal.ID.String()), zap.String("hash", withdrawal.Hash))
+
+		return w.BankService.ResetWithdrawal(ctx, withdrawal.ID)
+	}
+
+	return err // nolint:wrapcheck
+}
+
+func (w *WithdrawalWorker) submit(ctx context.Context, withdrawal Withdrawal) error {
+	ethAddr, _, err := types.ParseAddress(withdrawal.Destination)
+	if err != nil {
+		w.Log.Warn("invalid withdrawal destination, reversing", zap.String("id", withdrawal.ID.String()), zap.Error(err))
+		return w.BankService.ReverseWithdrawal(ctx, withdrawal.ID)
+	}
+
+	hash, raw, err := w.BlockChainService.SignTransfer(ctx, ethAddr, withdrawal.Amount)
+	if err != nil {
+		return err // nolint:wrapcheck
+	}
+
+	if err := w.BankService.SubmitWithdrawal(ctx, withdrawal.ID, hash, raw); err != nil {
+		return err
+	}
+
+	w.Log.Info("withdrawal submitted", zap.String("id", withdrawal.ID.String()), zap.String("hash", hash))
+
+	return w.BlockChainService.SendRawTransfer(ctx, raw) // nolint:wrapcheck
+}
@@ -0,0 +1,19 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	ethtypes "github.com/defiweb/go-eth/types"
+	"github.com/subvisual/fidl/types"
+)
+
+// WalletBalance returns the balance of the bank's wallet at the latest block.
+func (c Client) WalletBalance(ctx context.Context) (types.FIL, error) {
+	balance, err := c.GetBalance(ctx, c.address, ethtypes.LatestBlockNumber)
+	if err != nil {
+		return types.FIL{}, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	return types.NewFIL(balance), nil
+}
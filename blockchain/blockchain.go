@@ -8,6 +8,7 @@ import (
 	"github.com/defiweb/go-eth/rpc"
 	"github.com/defiweb/go-eth/rpc/transport"
 	"github.com/defiweb/go-eth/txmodifier"
+	ethtypes "github.com/defiweb/go-eth/types"
 	"github.com/defiweb/go-eth/wallet"
 	"github.com/subvisual/fidl/types"
 )
@@ -15,6 +16,7 @@ import (
 type Client struct {
 	*rpc.Client
 
+	address        ethtypes.Address
 	verifyTimeout  time.Duration
 	verifyInterval time.Duration
 }
@@ -60,6 +62,7 @@ func NewService(cfg *Config, pkey []byte, timeout time.Duration) (*Client, error
 
 	return &Client{
 		Client:         client,
+		address:        key.Address(),
 		verifyTimeout:  timeout,
 		verifyInterval: time.Duration(cfg.VerifyInterval) * time.Second,
 	}, nil
@@ -68,4 +71,8 @@ func NewService(cfg *Config, pkey []byte, timeout time.Duration) (*Client, error
 type Service interface {
 	VerifyTransaction(ctx context.Context, opts VerifyTransactionOptions) error
 	Transfer(ctx context.Context, to string, amount types.FIL) (string, error)
+	SignTransfer(ctx context.Context, to string, amount types.FIL) (string, []byte, error)
+	SendRawTransfer(ctx context.Context, raw []byte) error
+	TransferStatus(ctx context.Context, hash string) (TransactionStatus, error)
+	WalletBalance(ctx context.Context) (types.FIL, error)
 }
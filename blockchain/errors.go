@@ -0,0 +1,7 @@
+package blockchain
+
+import "errors"
+
+var (
+	ErrNonceTooLow = errors.New("nonce too low")
+)
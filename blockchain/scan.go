@@ -0,0 +1,242 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ethtypes "github.com/defiweb/go-eth/types"
+	"github.com/subvisual/fidl/types"
+)
+
+var ErrTransferNotFound = errors.New("transfer not found")
+
+// Transfer is a transfer of FIL into or out of the bank's wallet. Addresses and hashes are lower case hex.
+type Transfer struct {
+	Hash     string            `json:"hash"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Value    types.FIL         `json:"value"`
+	Incoming bool              `json:"incoming"`
+	Block    uint64            `json:"block"`
+	Status   TransactionStatus `json:"status"`
+}
+
+// Scan is what was found in a range of blocks. Start and End are the times of the first and last blocks of
+// the range that were not null rounds.
+type Scan struct {
+	From      uint64
+	To        uint64
+	Start     time.Time
+	End       time.Time
+	Transfers []Transfer
+}
+
+func (t TransactionStatus) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// LatestBlock returns the number of the latest block.
+func (c Client) LatestBlock(ctx context.Context) (uint64, error) {
+	number, err := c.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	return number.Uint64(), nil
+}
+
+// scanConcurrency is how many blocks of a range are fetched at once.
+const scanConcurrency = 8
+
+// Transfers scans the blocks from..to, both included, for transfers of FIL into or out of the bank's wallet.
+// Blocks are fetched scanConcurrency at a time, and the statuses of a block's transfers come from a single
+// eth_getBlockReceipts call, made only for blocks that move FIL into or out of the wallet.
+func (c Client) Transfers(ctx context.Context, from uint64, to uint64) (Scan, error) {
+	scan := Scan{From: from, To: to}
+	if from > to {
+		return scan, nil
+	}
+
+	blocks, err := c.scanBlocks(ctx, from, to)
+	if err != nil {
+		return Scan{}, err
+	}
+
+	for _, block := range blocks {
+		if block.block == nil {
+			continue
+		}
+
+		if scan.Start.IsZero() {
+			scan.Start = block.block.Timestamp
+		}
+		scan.End = block.block.Timestamp
+
+		scan.Transfers = append(scan.Transfers, block.transfers...)
+	}
+
+	return scan, nil
+}
+
+// scannedBlock is a block of a range and its transfers. The block is nil for null rounds.
+type scannedBlock struct {
+	block     *ethtypes.Block
+	transfers []Transfer
+}
+
+// scanBlocks scans the blocks from..to in order, stopping at the first error.
+func (c Client) scanBlocks(ctx context.Context, from uint64, to uint64) ([]scannedBlock, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		blocks   = make([]scannedBlock, to-from+1)
+		sem      = make(chan struct{}, scanConcurrency)
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for number := from; number <= to && ctx.Err() == nil; number++ {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			block, err := c.scanBlock(ctx, number)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+
+				return
+			}
+
+			blocks[number-from] = block
+		}()
+
+		if number == to {
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+func (c Client) scanBlock(ctx context.Context, number uint64) (scannedBlock, error) {
+	block, err := c.BlockByNumber(ctx, ethtypes.BlockNumberFromUint64(number), true)
+	if err != nil && strings.Contains(err.Error(), "null round") {
+		return scannedBlock{}, nil
+	}
+
+	if err != nil {
+		return scannedBlock{}, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+
+	if block == nil || block.Number == nil {
+		return scannedBlock{}, nil
+	}
+
+	var moves []ethtypes.OnChainTransaction
+	for _, tx := range block.Transactions {
+		if c.moves(tx) {
+			moves = append(moves, tx)
+		}
+	}
+
+	if len(moves) == 0 {
+		return scannedBlock{block: block}, nil
+	}
+
+	receipts, err := c.GetBlockReceipts(ctx, ethtypes.BlockNumberFromUint64(number))
+	if err != nil {
+		return scannedBlock{}, fmt.Errorf("failed to get receipts of block %d: %w", number, err)
+	}
+
+	statuses := make(map[ethtypes.Hash]TransactionStatus, len(receipts))
+	for _, receipt := range receipts {
+		if receipt != nil {
+			statuses[receipt.TransactionHash] = receiptStatus(receipt)
+		}
+	}
+
+	transfers := make([]Transfer, 0, len(moves))
+	for _, tx := range moves {
+		status, ok := statuses[*tx.Hash]
+		if !ok {
+			status = TransactionUnknown
+		}
+
+		transfers = append(transfers, c.newTransfer(tx, status))
+	}
+
+	return scannedBlock{block: block, transfers: transfers}, nil
+}
+
+// TransferByHash looks a transfer of the bank's wallet up by its hash, wherever it is on chain. It returns
+// ErrTransferNotFound when the chain does not know the hash or the transaction does not move FIL into or
+// out of the wallet.
+func (c Client) TransferByHash(ctx context.Context, hash string) (Transfer, error) {
+	var txHash ethtypes.Hash
+	if err := txHash.UnmarshalText([]byte(hash)); err != nil {
+		return Transfer{}, fmt.Errorf("%w: invalid hash %s", ErrTransferNotFound, hash)
+	}
+
+	tx, err := c.GetTransactionByHash(ctx, txHash)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if tx == nil || tx.BlockNumber == nil || !c.moves(*tx) {
+		return Transfer{}, ErrTransferNotFound
+	}
+
+	return c.transfer(ctx, *tx)
+}
+
+func (c Client) moves(tx ethtypes.OnChainTransaction) bool {
+	if tx.Hash == nil || tx.Value == nil || tx.Value.Sign() != 1 || tx.From == nil || tx.To == nil {
+		return false
+	}
+
+	return *tx.From == c.address || *tx.To == c.address
+}
+
+func (c Client) transfer(ctx context.Context, tx ethtypes.OnChainTransaction) (Transfer, error) {
+	status, err := c.TransferStatus(ctx, tx.Hash.String())
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	return c.newTransfer(tx, status), nil
+}
+
+func (c Client) newTransfer(tx ethtypes.OnChainTransaction, status TransactionStatus) Transfer {
+	return Transfer{
+		Hash:     strings.ToLower(tx.Hash.String()),
+		From:     strings.ToLower(tx.From.String()),
+		To:       strings.ToLower(tx.To.String()),
+		Value:    types.NewFIL(new(big.Int).Set(tx.Value)),
+		Incoming: *tx.To == c.address,
+		Block:    tx.BlockNumber.Uint64(),
+		Status:   status,
+	}
+}
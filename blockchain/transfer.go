@@ -3,26 +3,107 @@ package blockchain
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/crypto"
 	ethtypes "github.com/defiweb/go-eth/types"
 	"github.com/subvisual/fidl/types"
 )
 
-func (c Client) Transfer(ctx context.Context, to string, amount types.FIL) (string, error) {
+type TransactionStatus int8
+
+const (
+	TransactionUnknown TransactionStatus = iota
+	TransactionSucceeded
+	TransactionFailed
+)
+
+func (t TransactionStatus) String() string {
+	switch t {
+	case TransactionUnknown:
+		return "Unknown"
+	case TransactionSucceeded:
+		return "Succeeded"
+	case TransactionFailed:
+		return "Failed"
+	default:
+		return "Unknown" // nolint:goconst
+	}
+}
+
+func transferTransaction(to string, amount types.FIL) *ethtypes.Transaction {
 	transfer := abi.MustParseMethod("transfer(address, uint256)(bool)")
 
 	calldata := transfer.MustEncodeArgs(to, amount.Int)
 
-	tx := ethtypes.NewTransaction().
+	return ethtypes.NewTransaction().
 		SetTo(ethtypes.MustAddressFromHex(to)).
 		SetValue(amount.Int).
 		SetInput(calldata)
+}
 
-	txHash, _, err := c.SendTransaction(ctx, tx)
+func (c Client) Transfer(ctx context.Context, to string, amount types.FIL) (string, error) {
+	txHash, _, err := c.SendTransaction(ctx, transferTransaction(to, amount))
 	if err != nil {
 		return "", fmt.Errorf("failed to send transaction: %w", err)
 	}
 
 	return txHash.String(), nil
 }
+
+// SignTransfer signs a transfer without broadcasting it, so its hash can be stored before it reaches the chain.
+func (c Client) SignTransfer(ctx context.Context, to string, amount types.FIL) (string, []byte, error) {
+	raw, tx, err := c.SignTransaction(ctx, transferTransaction(to, amount))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	hash, err := tx.Hash(crypto.Keccak256)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to hash transaction: %w", err)
+	}
+
+	return hash.String(), raw, nil
+}
+
+// SendRawTransfer broadcasts a signed transfer. Broadcasting a transfer that is already known to the node is not an error.
+func (c Client) SendRawTransfer(ctx context.Context, raw []byte) error {
+	_, err := c.SendRawTransaction(ctx, raw)
+
+	switch {
+	case err == nil:
+		return nil
+	case strings.Contains(err.Error(), "already known"):
+		return nil
+	case strings.Contains(err.Error(), "nonce too low"):
+		return fmt.Errorf("%w: %w", ErrNonceTooLow, err)
+	default:
+		return fmt.Errorf("failed to send raw transaction: %w", err)
+	}
+}
+
+func (c Client) TransferStatus(ctx context.Context, hash string) (TransactionStatus, error) {
+	var txHash ethtypes.Hash
+	if err := txHash.UnmarshalText([]byte(hash)); err != nil {
+		return TransactionUnknown, fmt.Errorf("failed to unmarshal hash: %w", err)
+	}
+
+	receipt, err := c.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return TransactionUnknown, fmt.Errorf("failed to get transaction receipt: %w", err)
+	}
+
+	return receiptStatus(receipt), nil
+}
+
+func receiptStatus(receipt *ethtypes.TransactionReceipt) TransactionStatus {
+	switch {
+	case receipt == nil || receipt.Status == nil:
+		return TransactionUnknown
+	case *receipt.Status == 1:
+		return TransactionSucceeded
+	default:
+		return TransactionFailed
+	}
+}
@@ -0,0 +1,116 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Scanner finds the transfers of the bank's wallet on chain.
+type Scanner interface {
+	LatestBlock(ctx context.Context) (uint64, error)
+	Transfers(ctx context.Context, from uint64, to uint64) (Scan, error)
+}
+
+// DepositSink credits the deposits a Watcher finds, and keeps the last block it processed. Credit must
+// ignore a transfer that was already credited, as blocks are processed again when the Watcher stops before
+// moving its cursor.
+type DepositSink interface {
+	Cursor(ctx context.Context) (uint64, bool, error)
+	SetCursor(ctx context.Context, block uint64) error
+	Credit(ctx context.Context, transfer Transfer) error
+}
+
+type WatcherConfig struct {
+	Interval      time.Duration
+	Confirmations uint64
+	MaxBlocks     uint64
+}
+
+// Watcher follows the chain for transfers into the bank's wallet, so that deposits are credited even when
+// their hash never reaches the bank. It only processes blocks with Confirmations blocks on top of them, at
+// most MaxBlocks at a time, and starts from the latest confirmed block the first time it runs.
+type Watcher struct {
+	Scanner Scanner
+	Sink    DepositSink
+	Config  WatcherConfig
+	Log     *zap.Logger
+}
+
+func NewWatcher(scanner Scanner, sink DepositSink, cfg WatcherConfig, log *zap.Logger) *Watcher {
+	return &Watcher{
+		Scanner: scanner,
+		Sink:    sink,
+		Config:  cfg,
+		Log:     log,
+	}
+}
+
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Poll(ctx); err != nil {
+				w.Log.Error("failed to watch for deposits", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Poll credits the deposits in the confirmed blocks after the cursor, and moves the cursor past them. It
+// stops at the first deposit that fails to be credited, to try it again on the next poll.
+func (w *Watcher) Poll(ctx context.Context) error {
+	latest, err := w.Scanner.LatestBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	if latest < w.Config.Confirmations {
+		return nil
+	}
+
+	head := latest - w.Config.Confirmations
+
+	cursor, ok, err := w.Sink.Cursor(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		w.Log.Info("watching for deposits", zap.Uint64("block", head))
+		return w.Sink.SetCursor(ctx, head)
+	}
+
+	if cursor >= head {
+		return nil
+	}
+
+	from, to := cursor+1, head
+	if w.Config.MaxBlocks > 0 && to-from+1 > w.Config.MaxBlocks {
+		to = from + w.Config.MaxBlocks - 1
+	}
+
+	scan, err := w.Scanner.Transfers(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range scan.Transfers {
+		if !transfer.Incoming || transfer.From == transfer.To || transfer.Status != TransactionSucceeded {
+			continue
+		}
+
+		if err := w.Sink.Credit(ctx, transfer); err != nil {
+			return fmt.Errorf("failed to credit deposit %s: %w", transfer.Hash, err)
+		}
+	}
+
+	return w.Sink.SetCursor(ctx, to)
+}
@@ -0,0 +1,139 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/subvisual/fidl/types"
+	"go.uber.org/zap"
+)
+
+const (
+	bankWallet = "0x1111111111111111111111111111111111111111"
+	sender     = "0x2222222222222222222222222222222222222222"
+)
+
+type stubScanner struct {
+	latest    uint64
+	transfers []Transfer
+	scanned   [][2]uint64
+}
+
+func (s *stubScanner) LatestBlock(_ context.Context) (uint64, error) {
+	return s.latest, nil
+}
+
+func (s *stubScanner) Transfers(_ context.Context, from uint64, to uint64) (Scan, error) {
+	s.scanned = append(s.scanned, [2]uint64{from, to})
+
+	scan := Scan{From: from, To: to}
+	for _, t := range s.transfers {
+		if t.Block >= from && t.Block <= to {
+			scan.Transfers = append(scan.Transfers, t)
+		}
+	}
+
+	return scan, nil
+}
+
+type stubSink struct {
+	cursor   uint64
+	started  bool
+	credited []string
+	fail     string
+}
+
+func (s *stubSink) Cursor(_ context.Context) (uint64, bool, error) {
+	return s.cursor, s.started, nil
+}
+
+func (s *stubSink) SetCursor(_ context.Context, block uint64) error {
+	s.cursor, s.started = block, true
+
+	return nil
+}
+
+func (s *stubSink) Credit(_ context.Context, transfer Transfer) error {
+	if transfer.Hash == s.fail {
+		return errors.New("database is down")
+	}
+
+	s.credited = append(s.credited, transfer.Hash)
+
+	return nil
+}
+
+func transfer(hash string, from string, to string, block uint64, status TransactionStatus) Transfer {
+	return Transfer{Hash: hash, From: from, To: to, Value: types.NewFIL(big.NewInt(100)), Incoming: to == bankWallet, Block: block, Status: status}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	scanner := &stubScanner{
+		latest: 100,
+		transfers: []Transfer{
+			transfer("0xa1", sender, bankWallet, 90, TransactionSucceeded),
+			transfer("0xa2", sender, bankWallet, 97, TransactionSucceeded),
+			transfer("0xa3", bankWallet, sender, 98, TransactionSucceeded),
+			transfer("0xa4", sender, bankWallet, 99, TransactionFailed),
+			transfer("0xa5", bankWallet, bankWallet, 100, TransactionSucceeded),
+			transfer("0xa6", sender, bankWallet, 101, TransactionSucceeded),
+			transfer("0xa7", sender, bankWallet, 104, TransactionSucceeded),
+		},
+	}
+	sink := &stubSink{}
+	watcher := NewWatcher(scanner, sink, WatcherConfig{Confirmations: 5, MaxBlocks: 3}, zap.NewNop())
+
+	if err := watcher.Poll(ctx); err != nil {
+		t.Fatalf("failed to poll: %v", err)
+	}
+
+	if sink.cursor != 95 || len(scanner.scanned) != 0 {
+		t.Fatalf("expected the first poll to start from the latest confirmed block, got cursor %d and scans %v", sink.cursor, scanner.scanned)
+	}
+
+	scanner.latest = 106
+	for range 3 {
+		if err := watcher.Poll(ctx); err != nil {
+			t.Fatalf("failed to poll: %v", err)
+		}
+	}
+
+	expected := [][2]uint64{{96, 98}, {99, 101}}
+	if len(scanner.scanned) != len(expected) || scanner.scanned[0] != expected[0] || scanner.scanned[1] != expected[1] {
+		t.Errorf("expected scans of at most 3 confirmed blocks %v, got %v", expected, scanner.scanned)
+	}
+
+	if len(sink.credited) != 2 || sink.credited[0] != "0xa2" || sink.credited[1] != "0xa6" {
+		t.Errorf("expected only successful deposits from others to be credited, got %v", sink.credited)
+	}
+
+	if sink.cursor != 101 {
+		t.Errorf("expected the cursor at the latest confirmed block, got %d", sink.cursor)
+	}
+
+	scanner.latest = 110
+	sink.fail = "0xa7"
+
+	if err := watcher.Poll(ctx); err == nil {
+		t.Fatalf("expected a failed credit to fail the poll")
+	}
+
+	if sink.cursor != 101 {
+		t.Errorf("expected the cursor to stay before a failed credit, got %d", sink.cursor)
+	}
+
+	sink.fail = ""
+
+	if err := watcher.Poll(ctx); err != nil {
+		t.Fatalf("failed to poll: %v", err)
+	}
+
+	if len(sink.credited) != 3 || sink.credited[2] != "0xa7" || sink.cursor != 104 {
+		t.Errorf("expected the failed deposit to be credited on the next poll, got %v at %d", sink.credited, sink.cursor)
+	}
+}
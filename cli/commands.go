@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/subvisual/fidl/types"
 )
@@ -8,9 +10,26 @@ import (
 type AuthorizeOptions struct {
 	BankAddress  string `validate:"url" json:"bankAddress"`
 	ProxyInput   string `validate:"is-filecoin-address" json:"proxy"`
+	Amount       string `json:"amount"`
+	MaxPrice     string `json:"maxPrice"`
+	Mode         string `validate:"omitempty,oneof=single multi" json:"mode"`
+	ExpiresIn    string `json:"expiresIn"`
 	ProxyAddress types.Address
 }
 
+type CancelAuthorizationOptions struct {
+	BankAddress   string `validate:"url" json:"bankAddress"`
+	Authorization string `validate:"uuid" json:"authorization"`
+}
+
+type AuthorizationsOptions struct {
+	BankAddress   string `validate:"url" json:"bankAddress"`
+	Authorization string `validate:"omitempty,uuid" json:"authorization"`
+	Status        string `validate:"omitempty,oneof=open locked redeemed expired refunded cancelled" json:"status"`
+	Cursor        string `validate:"omitempty,uuid" json:"cursor"`
+	Limit         int    `validate:"gte=0,lte=100" json:"limit"`
+}
+
 type WithdrawOptions struct {
 	Amount      string `json:"amount"`
 	Destination string `validate:"is-valid-address" json:"dst"`
@@ -29,6 +48,15 @@ type BalanceOptions struct {
 	BankAddress string `validate:"url" json:"bankAddress"`
 }
 
+type TransactionsOptions struct {
+	BankAddress string `validate:"url" json:"bankAddress"`
+	From        string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" json:"from"`
+	To          string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" json:"to"`
+	Type        string `validate:"omitempty,oneof=deposit withdraw authorize redeem refund" json:"type"`
+	Cursor      int64  `validate:"gte=0" json:"cursor"`
+	Limit       int    `validate:"gte=0,lte=100" json:"limit"`
+}
+
 type RefundOptions struct {
 	BankAddress string `validate:"url" json:"bankAddress"`
 }
@@ -36,7 +64,25 @@ type RefundOptions struct {
 type RetrievalOptions struct {
 	ProxyAddress  string `validate:"url" json:"proxyAddress"`
 	Piece         string `json:"piece"`
-	Authorization string `validate:"uuid" json:"authorization"`
+	Authorization string `validate:"required_without=Voucher,omitempty,uuid" json:"authorization"`
+	Voucher       string `validate:"required_without=Authorization" json:"voucher"`
+}
+
+type ChannelOpenOptions struct {
+	BankAddress  string `validate:"url" json:"bankAddress"`
+	ProxyInput   string `validate:"is-filecoin-address" json:"proxy"`
+	Amount       string `json:"amount"`
+	ProxyAddress types.Address
+}
+
+type ChannelCloseOptions struct {
+	BankAddress string `validate:"url" json:"bankAddress"`
+	Channel     string `validate:"uuid" json:"channel"`
+}
+
+type VoucherOptions struct {
+	Channel string `validate:"uuid" json:"channel"`
+	Amount  string `json:"amount"`
 }
 
 type BanksOptions struct {
@@ -53,8 +99,10 @@ type DepositResponse struct {
 }
 
 type WithdrawResponseData struct {
-	FIL  types.FIL `json:"fil"`
-	Hash string    `json:"hash"`
+	FIL    types.FIL `json:"fil"`
+	Fee    types.FIL `json:"fee"`
+	ID     string    `json:"id"`
+	Status string    `json:"status"`
 }
 
 type WithdrawResponse struct {
@@ -62,6 +110,25 @@ type WithdrawResponse struct {
 	Data   WithdrawResponseData `json:"data"`
 }
 
+type TransactionResponseData struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Counterpart string    `json:"counterpart"`
+	FIL         types.FIL `json:"fil"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TransactionsResponseData struct {
+	Transactions []TransactionResponseData `json:"transactions"`
+	Cursor       int64                     `json:"cursor"`
+}
+
+type TransactionsResponse struct {
+	Status string                   `json:"status"`
+	Data   TransactionsResponseData `json:"data"`
+}
+
 type BalanceResponseData struct {
 	FIL    types.FIL `json:"fil"`
 	Escrow types.FIL `json:"escrow"`
@@ -83,9 +150,11 @@ type BanksResponse struct {
 }
 
 type AuthorizeResponseData struct {
-	FIL    types.FIL `json:"fil"`
-	Escrow types.FIL `json:"escrow"`
-	ID     uuid.UUID `json:"id"`
+	FIL       types.FIL `json:"fil"`
+	Escrow    types.FIL `json:"escrow"`
+	ID        uuid.UUID `json:"id"`
+	Mode      string    `json:"mode"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type AuthorizeResponse struct {
@@ -93,6 +162,61 @@ type AuthorizeResponse struct {
 	Data   AuthorizeResponseData `json:"data"`
 }
 
+type ChannelResponseData struct {
+	ID       uuid.UUID  `json:"id"`
+	FIL      types.FIL  `json:"fil"`
+	Escrow   types.FIL  `json:"escrow"`
+	Redeemed types.FIL  `json:"redeemed"`
+	Status   string     `json:"status"`
+	ClosesAt *time.Time `json:"closes_at"`
+}
+
+type ChannelResponse struct {
+	Status string              `json:"status"`
+	Data   ChannelResponseData `json:"data"`
+}
+
+type AuthorizationResponseData struct {
+	ID        uuid.UUID  `json:"id"`
+	Client    string     `json:"client"`
+	Proxy     string     `json:"proxy"`
+	Mode      string     `json:"mode"`
+	Status    string     `json:"status"`
+	Amount    types.FIL  `json:"amount"`
+	Remaining types.FIL  `json:"remaining"`
+	Redeemed  types.FIL  `json:"redeemed"`
+	MaxPrice  *types.FIL `json:"max_price"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type AuthorizationResponse struct {
+	Status string                    `json:"status"`
+	Data   AuthorizationResponseData `json:"data"`
+}
+
+type AuthorizationsResponseData struct {
+	Authorizations []AuthorizationResponseData `json:"authorizations"`
+	Cursor         string                      `json:"cursor"`
+}
+
+type AuthorizationsResponse struct {
+	Status string                     `json:"status"`
+	Data   AuthorizationsResponseData `json:"data"`
+}
+
+type CancelAuthorizationResponseData struct {
+	FIL      types.FIL `json:"fil"`
+	Escrow   types.FIL `json:"escrow"`
+	Released types.FIL `json:"released"`
+}
+
+type CancelAuthorizationResponse struct {
+	Status string                          `json:"status"`
+	Data   CancelAuthorizationResponseData `json:"data"`
+}
+
 type RefundResponseData struct {
 	FIL     types.FIL `json:"fil"`
 	Escrow  types.FIL `json:"escrow"`
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/subvisual/fidl/cli"
+	"github.com/subvisual/fidl/types"
+)
+
+func newAuthorizationsCommand(cl cli.CLI) *cobra.Command {
+	opts := cli.AuthorizationsOptions{}
+	authorizationsCmd := &cobra.Command{
+		Use:   "authorizations",
+		Short: "To list your authorizations at a specified bank.",
+		Long:  `This command lists the authorizations you made, or were made to you as a storage provider, at a specified bank, newest first. Pass an authorization uuid to only show that one.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := cl.Validate.Struct(opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			cfgPath, _ := cmd.Flags().GetString("config")
+			cfg := cli.LoadConfiguration(cfgPath)
+
+			ki, err := types.ReadWallet(cfg.Wallet)
+			if err != nil {
+				return fmt.Errorf("failed to read wallet: %w", err)
+			}
+
+			if opts.Authorization != "" {
+				_, err = cli.Authorization(ki, cfg.Wallet.Address, cfg.Route.Authorizations, opts)
+			} else {
+				_, err = cli.Authorizations(ki, cfg.Wallet.Address, cfg.Route.Authorizations, opts)
+			}
+
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			return nil
+		},
+	}
+
+	authorizationsCmd.Flags().StringVarP(&opts.BankAddress, "bank", "b", "", "The bank address")
+	authorizationsCmd.Flags().StringVarP(&opts.Authorization, "authorization", "a", "", "Only show this authorization uuid")
+	authorizationsCmd.Flags().StringVarP(&opts.Status, "status", "s", "", "Only authorizations in this status (open, locked, redeemed, expired, refunded, cancelled)")
+	authorizationsCmd.Flags().StringVarP(&opts.Cursor, "cursor", "c", "", "The cursor returned by the previous page")
+	authorizationsCmd.Flags().IntVarP(&opts.Limit, "limit", "l", 0, "The maximum number of authorizations to list")
+	cobra.CheckErr(authorizationsCmd.MarkFlagRequired("bank"))
+
+	return authorizationsCmd
+}
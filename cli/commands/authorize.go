@@ -44,8 +44,51 @@ func newAuthorizeCommand(cl cli.CLI) *cobra.Command {
 
 	authorizeCmd.Flags().StringVarP(&opts.BankAddress, "bank", "b", "", "The bank address")
 	authorizeCmd.Flags().StringVarP(&opts.ProxyInput, "proxy", "p", "", "The proxy wallet address")
+	authorizeCmd.Flags().StringVarP(&opts.Amount, "amount", "a", "", "The amount to escrow, defaults to the proxy price")
+	authorizeCmd.Flags().StringVarP(&opts.MaxPrice, "max-price", "m", "", "The maximum price per retrieval you accept to pay")
+	authorizeCmd.Flags().StringVar(&opts.Mode, "mode", "", "single (default) to redeem once, multi to redeem until the amount is used up")
+	authorizeCmd.Flags().StringVarP(&opts.ExpiresIn, "expires-in", "e", "", "How long the authorization lasts, e.g. 12h, defaults to the bank escrow deadline")
 	cobra.CheckErr(authorizeCmd.MarkFlagRequired("bank"))
 	cobra.CheckErr(authorizeCmd.MarkFlagRequired("proxy"))
 
+	authorizeCmd.AddCommand(newAuthorizeCancelCommand(cl))
+
 	return authorizeCmd
 }
+
+func newAuthorizeCancelCommand(cl cli.CLI) *cobra.Command {
+	opts := cli.CancelAuthorizationOptions{}
+	cancelCmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "To cancel an authorization that a storage provider has not started to redeem.",
+		Long:  `This command releases the funds of an open authorization back to your bank balance.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := cl.Validate.Struct(opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			cfgPath, _ := cmd.Flags().GetString("config")
+			cfg := cli.LoadConfiguration(cfgPath)
+
+			ki, err := types.ReadWallet(cfg.Wallet)
+			if err != nil {
+				return fmt.Errorf("failed to read wallet: %w", err)
+			}
+
+			_, err = cli.CancelAuthorization(ki, cfg.Wallet.Address, cfg.Route.Authorizations, opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cancelCmd.Flags().StringVarP(&opts.BankAddress, "bank", "b", "", "The bank address")
+	cancelCmd.Flags().StringVarP(&opts.Authorization, "authorization", "a", "", "The authorization uuid")
+	cobra.CheckErr(cancelCmd.MarkFlagRequired("bank"))
+	cobra.CheckErr(cancelCmd.MarkFlagRequired("authorization"))
+
+	return cancelCmd
+}
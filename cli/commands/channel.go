@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/subvisual/fidl/cli"
+	"github.com/subvisual/fidl/types"
+)
+
+func newChannelCommand(cl cli.CLI) *cobra.Command {
+	channelCmd := &cobra.Command{
+		Use:   "channel",
+		Short: "To open and close payment channels with a storage provider.",
+		Long:  `This command manages payment channels. A channel escrows funds for a storage provider, which is then paid with vouchers signed by the client.`,
+	}
+
+	channelCmd.AddCommand(newChannelOpenCommand(cl))
+	channelCmd.AddCommand(newChannelCloseCommand(cl))
+
+	return channelCmd
+}
+
+func newChannelOpenCommand(cl cli.CLI) *cobra.Command {
+	opts := cli.ChannelOpenOptions{}
+	openCmd := &cobra.Command{
+		Use:   "open",
+		Short: "To open a payment channel with a storage provider.",
+		Long:  `This command moves funds from your bank balance into a payment channel with a storage provider.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := cl.Validate.Struct(opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			opts.ProxyAddress, err = types.NewAddressFromString(opts.ProxyInput)
+			if err != nil {
+				return fmt.Errorf("failed to parse proxy address: %w", err)
+			}
+
+			cfgPath, _ := cmd.Flags().GetString("config")
+			cfg := cli.LoadConfiguration(cfgPath)
+
+			ki, err := types.ReadWallet(cfg.Wallet)
+			if err != nil {
+				return fmt.Errorf("failed to read wallet: %w", err)
+			}
+
+			_, err = cli.OpenChannel(ki, cfg.Wallet.Address, cfg.Route.Channels, opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			return nil
+		},
+	}
+
+	openCmd.Flags().StringVarP(&opts.BankAddress, "bank", "b", "", "The bank address")
+	openCmd.Flags().StringVarP(&opts.ProxyInput, "proxy", "p", "", "The proxy wallet address")
+	openCmd.Flags().StringVarP(&opts.Amount, "amount", "a", "", "The amount to escrow in the channel")
+	cobra.CheckErr(openCmd.MarkFlagRequired("bank"))
+	cobra.CheckErr(openCmd.MarkFlagRequired("proxy"))
+	cobra.CheckErr(openCmd.MarkFlagRequired("amount"))
+
+	return openCmd
+}
+
+func newChannelCloseCommand(cl cli.CLI) *cobra.Command {
+	opts := cli.ChannelCloseOptions{}
+	closeCmd := &cobra.Command{
+		Use:   "close",
+		Short: "To close a payment channel.",
+		Long:  `This command starts closing a payment channel. The storage provider can still settle its latest voucher until the settle window ends, then the remaining funds return to your balance once you close it again.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := cl.Validate.Struct(opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			cfgPath, _ := cmd.Flags().GetString("config")
+			cfg := cli.LoadConfiguration(cfgPath)
+
+			ki, err := types.ReadWallet(cfg.Wallet)
+			if err != nil {
+				return fmt.Errorf("failed to read wallet: %w", err)
+			}
+
+			_, err = cli.CloseChannel(ki, cfg.Wallet.Address, cfg.Route.Channels, opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			return nil
+		},
+	}
+
+	closeCmd.Flags().StringVarP(&opts.BankAddress, "bank", "b", "", "The bank address")
+	closeCmd.Flags().StringVarP(&opts.Channel, "channel", "c", "", "The channel uuid")
+	cobra.CheckErr(closeCmd.MarkFlagRequired("bank"))
+	cobra.CheckErr(closeCmd.MarkFlagRequired("channel"))
+
+	return closeCmd
+}
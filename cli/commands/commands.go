@@ -21,8 +21,12 @@ func Parse(cl cli.CLI) *cobra.Command {
 	rootCmd.AddCommand(newBalanceCommand(cl))
 	rootCmd.AddCommand(newBanksCommand(cl))
 	rootCmd.AddCommand(newAuthorizeCommand(cl))
+	rootCmd.AddCommand(newAuthorizationsCommand(cl))
 	rootCmd.AddCommand(newRefundCommand(cl))
 	rootCmd.AddCommand(newRetrievalCommand(cl))
+	rootCmd.AddCommand(newTransactionsCommand(cl))
+	rootCmd.AddCommand(newChannelCommand(cl))
+	rootCmd.AddCommand(newVoucherCommand(cl))
 
 	return rootCmd
 }
@@ -33,9 +33,11 @@ func newRetrievalCommand(cl cli.CLI) *cobra.Command {
 	retrievalCmd.Flags().StringVarP(&opts.Piece, "id", "i", "", "The piece CID to be retrieved")
 	retrievalCmd.Flags().StringVarP(&opts.ProxyAddress, "proxy", "p", "", "The proxy address")
 	retrievalCmd.Flags().StringVarP(&opts.Authorization, "authorization", "a", "", "The authorization uuid")
+	retrievalCmd.Flags().StringVar(&opts.Voucher, "voucher", "", "A signed payment channel voucher, instead of an authorization")
 	cobra.CheckErr(retrievalCmd.MarkFlagRequired("id"))
 	cobra.CheckErr(retrievalCmd.MarkFlagRequired("proxy"))
-	cobra.CheckErr(retrievalCmd.MarkFlagRequired("authorization"))
+	retrievalCmd.MarkFlagsOneRequired("authorization", "voucher")
+	retrievalCmd.MarkFlagsMutuallyExclusive("authorization", "voucher")
 
 	return retrievalCmd
 }
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/subvisual/fidl/cli"
+	"github.com/subvisual/fidl/types"
+)
+
+func newTransactionsCommand(cl cli.CLI) *cobra.Command {
+	opts := cli.TransactionsOptions{}
+	transactionsCmd := &cobra.Command{
+		Use:   "transactions",
+		Short: "To list the client's transactions at a specified bank.",
+		Long:  `This command lists the client's transactions at a specified bank, newest first.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := cl.Validate.Struct(opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			cfgPath, _ := cmd.Flags().GetString("config")
+			cfg := cli.LoadConfiguration(cfgPath)
+
+			ki, err := types.ReadWallet(cfg.Wallet)
+			if err != nil {
+				return fmt.Errorf("failed to read wallet: %w", err)
+			}
+
+			_, err = cli.Transactions(ki, cfg.Wallet.Address, cfg.Route.Transactions, opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			return nil
+		},
+	}
+
+	transactionsCmd.Flags().StringVarP(&opts.BankAddress, "bank", "b", "", "The bank address")
+	transactionsCmd.Flags().StringVar(&opts.From, "from", "", "Only transactions at or after this RFC3339 time")
+	transactionsCmd.Flags().StringVar(&opts.To, "to", "", "Only transactions before this RFC3339 time")
+	transactionsCmd.Flags().StringVarP(&opts.Type, "type", "t", "", "Only transactions of this type (deposit, withdraw, authorize, redeem, refund)")
+	transactionsCmd.Flags().Int64VarP(&opts.Cursor, "cursor", "c", 0, "The cursor returned by the previous page")
+	transactionsCmd.Flags().IntVarP(&opts.Limit, "limit", "l", 0, "The maximum number of transactions to list")
+	cobra.CheckErr(transactionsCmd.MarkFlagRequired("bank"))
+
+	return transactionsCmd
+}
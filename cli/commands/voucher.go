@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/subvisual/fidl/cli"
+	"github.com/subvisual/fidl/types"
+)
+
+func newVoucherCommand(cl cli.CLI) *cobra.Command {
+	opts := cli.VoucherOptions{}
+	voucherCmd := &cobra.Command{
+		Use:   "voucher",
+		Short: "To sign a payment channel voucher.",
+		Long:  `This command signs a voucher for the total amount paid so far through a channel. Each voucher replaces the previous one, so its amount must grow by at least the proxy price on every retrieval.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := cl.Validate.Struct(opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			cfgPath, _ := cmd.Flags().GetString("config")
+			cfg := cli.LoadConfiguration(cfgPath)
+
+			ki, err := types.ReadWallet(cfg.Wallet)
+			if err != nil {
+				return fmt.Errorf("failed to read wallet: %w", err)
+			}
+
+			_, err = cli.Voucher(ki, cfg.Wallet.Address, opts)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			return nil
+		},
+	}
+
+	voucherCmd.Flags().StringVarP(&opts.Channel, "channel", "c", "", "The channel uuid")
+	voucherCmd.Flags().StringVarP(&opts.Amount, "amount", "a", "", "The cumulative amount paid through the channel")
+	cobra.CheckErr(voucherCmd.MarkFlagRequired("channel"))
+	cobra.CheckErr(voucherCmd.MarkFlagRequired("amount"))
+
+	return voucherCmd
+}
@@ -10,13 +10,16 @@ import (
 )
 
 type Route struct {
-	Balance   string `toml:"balance"`
-	Banks     string `toml:"banks"`
-	Deposit   string `toml:"deposit"`
-	Withdraw  string `toml:"withdraw"`
-	Authorize string `toml:"authorize"`
-	Refund    string `toml:"refund"`
-	Retrieval string `toml:"retrieval"`
+	Balance        string `toml:"balance"`
+	Banks          string `toml:"banks"`
+	Channels       string `toml:"channels"`
+	Deposit        string `toml:"deposit"`
+	Withdraw       string `toml:"withdraw"`
+	Authorize      string `toml:"authorize"`
+	Authorizations string `toml:"authorizations"`
+	Refund         string `toml:"refund"`
+	Retrieval      string `toml:"retrieval"`
+	Transactions   string `toml:"transactions"`
 }
 
 type Config struct {
@@ -7,17 +7,56 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/crypto"
 	"github.com/subvisual/fidl/types"
 )
 
 func Authorize(ki types.KeyInfo, addr types.Address, route string, options AuthorizeOptions) (*AuthorizeResponse, error) {
 	authorizeResponse := AuthorizeResponse{}
 
-	body, err := json.Marshal(map[string]any{
+	payload := map[string]any{
 		"proxy": options.ProxyAddress.String(),
-	})
+	}
+
+	if options.Amount != "" {
+		var amount types.FIL
+		if err := amount.UnmarshalJSON([]byte(options.Amount)); err != nil {
+			return nil, fmt.Errorf("error unmarshalling amount data: %w", err)
+		}
+
+		payload["amount"] = amount
+	}
+
+	if options.MaxPrice != "" {
+		var maxPrice types.FIL
+		if err := maxPrice.UnmarshalJSON([]byte(options.MaxPrice)); err != nil {
+			return nil, fmt.Errorf("error unmarshalling max price data: %w", err)
+		}
+
+		payload["max_price"] = maxPrice
+	}
+
+	if options.Mode != "" {
+		payload["mode"] = options.Mode
+	}
+
+	if options.ExpiresIn != "" {
+		expiresIn, err := time.ParseDuration(options.ExpiresIn)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing expiry duration: %w", err)
+		}
+
+		payload["expires_at"] = time.Now().UTC().Add(expiresIn)
+	}
+
+	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed payload marshaling: %w", err)
 	}
@@ -33,11 +72,15 @@ func Authorize(ki types.KeyInfo, addr types.Address, route string, options Autho
 		if err != nil {
 			return nil, fmt.Errorf("error decoding the response body: %w", err)
 		}
-		fmt.Printf("You successfully authorized to escrow: %s \nYour current bank balance is: %s\nAuth id: %s\n", authorizeResponse.Data.Escrow, authorizeResponse.Data.FIL, authorizeResponse.Data.ID) // nolint:forbidigo
+		fmt.Printf("You successfully authorized to escrow: %s \nYour current bank balance is: %s\nAuth id: %s\nAuth mode: %s\nExpires at: %s\n", authorizeResponse.Data.Escrow, authorizeResponse.Data.FIL, authorizeResponse.Data.ID, authorizeResponse.Data.Mode, authorizeResponse.Data.ExpiresAt.Format(time.RFC3339)) // nolint:forbidigo
 	case http.StatusNotFound:
 		return nil, fmt.Errorf("client or proxy wallet not found")
 	case http.StatusForbidden:
 		return nil, fmt.Errorf("not have enough funds")
+	case http.StatusConflict:
+		return nil, fmt.Errorf("the proxy price is above your maximum price")
+	case http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("invalid authorization: %s", resp.Body)
 	case http.StatusUnauthorized:
 		return nil, fmt.Errorf("the wallet address and signature do not match")
 	default:
@@ -47,6 +90,111 @@ func Authorize(ki types.KeyInfo, addr types.Address, route string, options Autho
 	return &authorizeResponse, nil
 }
 
+func Authorizations(ki types.KeyInfo, addr types.Address, route string, options AuthorizationsOptions) (*AuthorizationsResponse, error) {
+	authorizationsResponse := AuthorizationsResponse{}
+
+	query := url.Values{}
+	if options.Status != "" {
+		query.Set("status", options.Status)
+	}
+
+	if options.Cursor != "" {
+		query.Set("cursor", options.Cursor)
+	}
+
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	resp, err := GetRequest(ki, addr, options.BankAddress, route, query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case http.StatusOK:
+		err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&authorizationsResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the response body: %w", err)
+		}
+
+		for _, a := range authorizationsResponse.Data.Authorizations {
+			printAuthorization(a)
+		}
+
+		if authorizationsResponse.Data.Cursor != "" {
+			fmt.Println("More authorizations available with cursor:", authorizationsResponse.Data.Cursor) // nolint:forbidigo
+		}
+	case http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("invalid filters: %s", resp.Body)
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("the wallet address and signature do not match")
+	default:
+		return nil, fmt.Errorf("something went wrong: %s\nMessage: %s", http.StatusText(resp.Status), resp.Body)
+	}
+
+	return &authorizationsResponse, nil
+}
+
+func Authorization(ki types.KeyInfo, addr types.Address, route string, options AuthorizationsOptions) (*AuthorizationResponse, error) {
+	authorizationResponse := AuthorizationResponse{}
+
+	resp, err := GetRequest(ki, addr, options.BankAddress, path.Join(route, options.Authorization), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case http.StatusOK:
+		err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&authorizationResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the response body: %w", err)
+		}
+
+		printAuthorization(authorizationResponse.Data)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("authorization not found")
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("the wallet address and signature do not match")
+	default:
+		return nil, fmt.Errorf("something went wrong: %s\nMessage: %s", http.StatusText(resp.Status), resp.Body)
+	}
+
+	return &authorizationResponse, nil
+}
+
+func printAuthorization(a AuthorizationResponseData) {
+	fmt.Printf("%s %-9s %-6s amount: %s remaining: %s redeemed: %s expires: %s proxy: %s\n", a.ID, a.Status, a.Mode, a.Amount, a.Remaining, a.Redeemed, a.ExpiresAt.Format(time.RFC3339), a.Proxy) // nolint:forbidigo
+}
+
+func CancelAuthorization(ki types.KeyInfo, addr types.Address, route string, options CancelAuthorizationOptions) (*CancelAuthorizationResponse, error) {
+	cancelResponse := CancelAuthorizationResponse{}
+
+	resp, err := DeleteRequest(context.Background(), ki, addr, options.BankAddress, path.Join(route, options.Authorization))
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case http.StatusOK:
+		err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&cancelResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the response body: %w", err)
+		}
+		fmt.Printf("Funds released from the authorization: %s \nYour current bank balance is: %s \nYour current funds on escrow are: %s\n", cancelResponse.Data.Released, cancelResponse.Data.FIL, cancelResponse.Data.Escrow) // nolint:forbidigo
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("authorization not found")
+	case http.StatusConflict:
+		return nil, fmt.Errorf("the authorization is locked by a proxy and cannot be cancelled")
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("the wallet address and signature do not match")
+	default:
+		return nil, fmt.Errorf("something went wrong: %s\nMessage: %s", http.StatusText(resp.Status), resp.Body)
+	}
+
+	return &cancelResponse, nil
+}
+
 func Balance(ki types.KeyInfo, addr types.Address, route string, options BalanceOptions) (*BalanceResponse, error) {
 	balanceResponse := BalanceResponse{}
 
@@ -217,8 +365,9 @@ func Withdraw(ki types.KeyInfo, addr types.Address, route string, options Withdr
 		if err != nil {
 			return nil, fmt.Errorf("error decoding the response body: %w", err)
 		}
-		fmt.Println("Withdraw successful, your current bank balance is:", withdrawResponse.Data.FIL) // nolint:forbidigo
-		fmt.Println("Transaction hash is:", withdrawResponse.Data.Hash)                              // nolint:forbidigo
+		fmt.Println("Withdraw accepted, your current bank balance is:", withdrawResponse.Data.FIL)          // nolint:forbidigo
+		fmt.Println("Withdrawal id is:", withdrawResponse.Data.ID, "status:", withdrawResponse.Data.Status) // nolint:forbidigo
+		fmt.Println("Withdrawal fee:", withdrawResponse.Data.Fee)                                           // nolint:forbidigo
 	case http.StatusNotFound:
 		return nil, fmt.Errorf("wallet not found")
 	case http.StatusForbidden:
@@ -231,3 +380,164 @@ func Withdraw(ki types.KeyInfo, addr types.Address, route string, options Withdr
 
 	return &withdrawResponse, nil
 }
+
+func Transactions(ki types.KeyInfo, addr types.Address, route string, options TransactionsOptions) (*TransactionsResponse, error) {
+	transactionsResponse := TransactionsResponse{}
+
+	query := url.Values{}
+	if options.From != "" {
+		query.Set("from", options.From)
+	}
+
+	if options.To != "" {
+		query.Set("to", options.To)
+	}
+
+	if options.Type != "" {
+		query.Set("type", options.Type)
+	}
+
+	if options.Cursor > 0 {
+		query.Set("cursor", strconv.FormatInt(options.Cursor, 10))
+	}
+
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	resp, err := GetRequest(ki, addr, options.BankAddress, route, query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case http.StatusOK:
+		err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&transactionsResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the response body: %w", err)
+		}
+
+		for _, t := range transactionsResponse.Data.Transactions {
+			fmt.Printf("%s %-9s %-9s %s %s %s\n", t.CreatedAt.Format(time.RFC3339), t.Type, t.Status, t.FIL, t.Counterpart, t.ID) // nolint:forbidigo
+		}
+
+		if transactionsResponse.Data.Cursor > 0 {
+			fmt.Println("More transactions available with cursor:", transactionsResponse.Data.Cursor) // nolint:forbidigo
+		}
+	case http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("invalid filters: %s", resp.Body)
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("the wallet address and signature do not match")
+	default:
+		return nil, fmt.Errorf("something went wrong: %s\nMessage: %s", http.StatusText(resp.Status), resp.Body)
+	}
+
+	return &transactionsResponse, nil
+}
+
+func OpenChannel(ki types.KeyInfo, addr types.Address, route string, options ChannelOpenOptions) (*ChannelResponse, error) {
+	var amount types.FIL
+	channelResponse := ChannelResponse{}
+
+	if err := amount.UnmarshalJSON([]byte(options.Amount)); err != nil {
+		return nil, fmt.Errorf("error unmarshalling amount data: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"proxy":  options.ProxyAddress.String(),
+		"amount": amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed payload marshaling: %w", err)
+	}
+
+	resp, err := PostRequest(context.Background(), ki, addr, options.BankAddress, route, body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case http.StatusOK:
+		err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&channelResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the response body: %w", err)
+		}
+		fmt.Printf("You successfully opened a channel with: %s \nYour current bank balance is: %s\nChannel id: %s\n", channelResponse.Data.Escrow, channelResponse.Data.FIL, channelResponse.Data.ID) // nolint:forbidigo
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("client or proxy wallet not found")
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("not have enough funds")
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("the wallet address and signature do not match")
+	default:
+		return nil, fmt.Errorf("something went wrong: %s\nMessage: %s", http.StatusText(resp.Status), resp.Body)
+	}
+
+	return &channelResponse, nil
+}
+
+func CloseChannel(ki types.KeyInfo, addr types.Address, route string, options ChannelCloseOptions) (*ChannelResponse, error) {
+	channelResponse := ChannelResponse{}
+
+	resp, err := PostRequest(context.Background(), ki, addr, options.BankAddress, path.Join(route, options.Channel, "close"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status {
+	case http.StatusOK:
+		err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&channelResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the response body: %w", err)
+		}
+
+		if channelResponse.Data.ClosesAt != nil && channelResponse.Data.Status != "Closed" {
+			fmt.Printf("Channel %s is closing, the proxy can settle it until: %s\n", channelResponse.Data.ID, channelResponse.Data.ClosesAt.Format(time.RFC3339)) // nolint:forbidigo
+		} else {
+			fmt.Printf("Channel %s is closed, %s was redeemed from it\n", channelResponse.Data.ID, channelResponse.Data.Redeemed) // nolint:forbidigo
+		}
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("channel not found")
+	case http.StatusConflict:
+		return nil, fmt.Errorf("the channel is already closed")
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("the wallet address and signature do not match")
+	default:
+		return nil, fmt.Errorf("something went wrong: %s\nMessage: %s", http.StatusText(resp.Status), resp.Body)
+	}
+
+	return &channelResponse, nil
+}
+
+// Voucher signs a voucher for the cumulative amount paid through a channel, to be sent to its proxy on retrieval.
+func Voucher(ki types.KeyInfo, addr types.Address, options VoucherOptions) (string, error) {
+	var amount types.FIL
+
+	if err := amount.UnmarshalJSON([]byte(options.Amount)); err != nil {
+		return "", fmt.Errorf("error unmarshalling amount data: %w", err)
+	}
+
+	channel, err := uuid.Parse(options.Channel)
+	if err != nil {
+		return "", fmt.Errorf("error parsing channel string to uuid: %w", err)
+	}
+
+	_, sigType, err := types.ParseAddress(addr.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse wallet public address: %w", err)
+	}
+
+	voucher, err := crypto.SignVoucher(ki.PrivateKey, sigType, types.Voucher{Channel: channel, Amount: amount})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign voucher: %w", err)
+	}
+
+	token, err := voucher.Encode()
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	fmt.Println(token) // nolint:forbidigo
+
+	return token, nil
+}
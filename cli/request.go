@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,69 +16,72 @@ import (
 	"github.com/subvisual/fidl/types"
 )
 
-func PostRequest(ctx context.Context, ki types.KeyInfo, addr types.Address, bankAddress string, route string, body []byte) (*request.Response, error) {
-	msg := append([]byte(time.Now().UTC().String()), body...)
+const postAttempts = 3
 
-	_, sigType, err := types.ParseAddress(addr.String())
+// PostRequest sends a signed request, retrying it when no response comes back. Every attempt carries the
+// same idempotency key, so that the bank runs the request once.
+func PostRequest(ctx context.Context, ki types.KeyInfo, addr types.Address, bankAddress string, route string, body []byte) (*request.Response, error) {
+	dstURL, err := joinPath(bankAddress, route, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse wallet public address: %w", err)
+		return nil, fmt.Errorf("%w", err)
 	}
-	ki.Type = sigType
 
-	sig, err := sign(ki, msg)
+	key, err := request.NewNonce()
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign: %w", err)
+		return nil, fmt.Errorf("%w", err)
 	}
 
-	dstURL, err := joinPath(bankAddress, route, "")
-	if err != nil {
-		return nil, fmt.Errorf("%w", err)
+	for attempt := 1; ; attempt++ {
+		req, err := signedRequest(ki, addr, http.MethodPost, dstURL, body, key)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := req.Post(ctx)
+		if err == nil {
+			return resp, nil
+		}
+
+		if attempt == postAttempts || ctx.Err() != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
 	}
+}
 
-	buff := bytes.NewBuffer(body)
-	resp, err := request.New().
-		SetEndpoint(dstURL).
-		SetBody(buff).
-		AppendHeader("content-type", "application/json").
-		AppendHeader("sig", hex.EncodeToString(sig)).
-		AppendHeader("pub", addr.String()).
-		AppendHeader("msg", hex.EncodeToString(msg)).
-		Post(ctx)
+func GetRequest(ki types.KeyInfo, addr types.Address, bankAddress string, route string, query url.Values) (*request.Response, error) {
+	dstURL, err := joinPath(bankAddress, route, "")
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
 
-	return resp, nil
-}
-
-func GetRequest(ki types.KeyInfo, addr types.Address, bankAddress string, route string, body []byte) (*request.Response, error) {
-	msg := append([]byte(time.Now().UTC().String()), body...)
+	// The query string is part of the signed message, so it must be encoded before signing.
+	dstURL.RawQuery = query.Encode()
 
-	_, sigType, err := types.ParseAddress(addr.String())
+	req, err := signedRequest(ki, addr, http.MethodGet, dstURL, nil, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse wallet public address: %w", err)
+		return nil, err
 	}
-	ki.Type = sigType
 
-	sig, err := sign(ki, msg)
+	resp, err := req.Get(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign: %w", err)
+		return nil, fmt.Errorf("%w", err)
 	}
 
+	return resp, nil
+}
+
+func DeleteRequest(ctx context.Context, ki types.KeyInfo, addr types.Address, bankAddress string, route string) (*request.Response, error) {
 	dstURL, err := joinPath(bankAddress, route, "")
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
 
-	buff := bytes.NewBuffer(body)
-	resp, err := request.New().
-		SetEndpoint(dstURL).
-		SetBody(buff).
-		AppendHeader("content-type", "application/json").
-		AppendHeader("sig", hex.EncodeToString(sig)).
-		AppendHeader("pub", addr.String()).
-		AppendHeader("msg", hex.EncodeToString(msg)).
-		Get(context.Background())
+	req, err := signedRequest(ki, addr, http.MethodDelete, dstURL, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := req.Delete(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -85,20 +90,25 @@ func GetRequest(ki types.KeyInfo, addr types.Address, bankAddress string, route
 }
 
 func ProxyRetrieveRequest(proxyAddress string, options RetrievalOptions, route string) (*request.Response, error) {
-	uuid, err := uuid.Parse(options.Authorization)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing authorization string to uuid: %w", err)
-	}
-
 	dstURL, err := joinPath(proxyAddress, route, options.Piece)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
 
-	resp, err := request.New().
-		SetEndpoint(dstURL).
-		AppendURLQuery("authorization", uuid.String()).
-		Get(context.Background())
+	req := request.New().SetEndpoint(dstURL)
+
+	if options.Voucher != "" {
+		req = req.AppendURLQuery("voucher", options.Voucher)
+	} else {
+		uuid, err := uuid.Parse(options.Authorization)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing authorization string to uuid: %w", err)
+		}
+
+		req = req.AppendURLQuery("authorization", uuid.String())
+	}
+
+	resp, err := req.Get(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -122,6 +132,42 @@ func ProxyBanksRequest(proxyAddress string, route string) (*request.Response, er
 	return resp, nil
 }
 
+func signedRequest(ki types.KeyInfo, addr types.Address, method string, dstURL *url.URL, body []byte, idempotencyKey string) (*request.Request, error) {
+	_, sigType, err := types.ParseAddress(addr.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse wallet public address: %w", err)
+	}
+	ki.Type = sigType
+
+	nonce, err := request.NewNonce()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	timestamp := time.Now().UTC().Unix()
+	msg := request.CanonicalMessage(method, request.Target(dstURL), body, timestamp, nonce, idempotencyKey)
+
+	sig, err := sign(ki, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	req := request.New().
+		SetEndpoint(dstURL).
+		SetBody(bytes.NewBuffer(body)).
+		AppendHeader("content-type", "application/json").
+		AppendHeader(request.HeaderSignature, hex.EncodeToString(sig)).
+		AppendHeader(request.HeaderPublicKey, addr.String()).
+		AppendHeader(request.HeaderTimestamp, strconv.FormatInt(timestamp, 10)).
+		AppendHeader(request.HeaderNonce, nonce)
+
+	if idempotencyKey != "" {
+		req = req.AppendHeader(request.HeaderIdempotencyKey, idempotencyKey)
+	}
+
+	return req, nil
+}
+
 func sign(ki types.KeyInfo, body []byte) ([]byte, error) {
 	sig, err := crypto.Sign(ki.PrivateKey, ki.Type, body)
 	if err != nil {
@@ -12,7 +12,9 @@ import (
 
 	"github.com/subvisual/fidl"
 	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/bank/memory"
 	"github.com/subvisual/fidl/bank/postgres"
+	"github.com/subvisual/fidl/bank/sqlite"
 	"github.com/subvisual/fidl/blockchain"
 	"github.com/subvisual/fidl/http"
 	"github.com/subvisual/fidl/types"
@@ -68,12 +70,10 @@ func main() {
 
 	zap.ReplaceGlobals(logger)
 
-	db := postgres.Connect(postgres.Config{
-		Dsn:          cfg.Db.Dsn,
-		MaxOpenConns: cfg.Db.MaxOpenConns,
-		MaxIdleConns: cfg.Db.MaxIdleConns,
-		MaxIdleTime:  cfg.Db.MaxIdleTime,
-	})
+	trustedProxies, err := http.ParseTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		logger.Fatal("failed to parse trusted proxies", zap.Error(err))
+	}
 
 	httpServer := http.New(&http.Config{
 		Addr:            cfg.HTTP.Addr,
@@ -86,18 +86,131 @@ func main() {
 
 		TLS: cfg.HTTP.TLS,
 		Env: cfg.Env,
+
+		TrustedProxies: trustedProxies,
 	})
 
+	requestWindow, err := time.ParseDuration(cfg.Auth.Window)
+	if err != nil {
+		logger.Fatal("failed to parse auth window", zap.Error(err))
+	}
+
+	idempotencyTTL, err := time.ParseDuration(cfg.Idempotency.TTL)
+	if err != nil {
+		logger.Fatal("failed to parse idempotency ttl", zap.Error(err))
+	}
+
+	escrowDeadline, err := time.ParseDuration(cfg.Escrow.Deadline)
+	if err != nil {
+		logger.Fatal("failed to parse escrow deadline", zap.Error(err))
+	}
+
+	escrowMinDeadline, err := time.ParseDuration(cfg.Escrow.MinDeadline)
+	if err != nil {
+		logger.Fatal("failed to parse escrow min deadline", zap.Error(err))
+	}
+
+	escrowMaxDeadline, err := time.ParseDuration(cfg.Escrow.MaxDeadline)
+	if err != nil {
+		logger.Fatal("failed to parse escrow max deadline", zap.Error(err))
+	}
+
+	channelLifetime, err := time.ParseDuration(cfg.Channels.Lifetime)
+	if err != nil {
+		logger.Fatal("failed to parse channel lifetime", zap.Error(err))
+	}
+
+	fees, err := bank.ParseFeeSchedule(cfg.Fees)
+	if err != nil {
+		logger.Fatal("failed to parse fees", zap.Error(err))
+	}
+
+	rateLimits, err := bank.ParseRateLimits(cfg.RateLimits)
+	if err != nil {
+		logger.Fatal("failed to parse rate limits", zap.Error(err))
+	}
+
+	frozenAllowed, err := bank.ParseOperations(cfg.Accounts.FrozenAllowed)
+	if err != nil {
+		logger.Fatal("failed to parse operations allowed to frozen accounts", zap.Error(err))
+	}
+
 	bankCtx := bank.Server{
 		Server: httpServer,
 
 		CustomReadTimeout: time.Duration(cfg.HTTP.WriteTimeout) * time.Second,
+		RequestWindow:     requestWindow,
+		IdempotencyTTL:    idempotencyTTL,
+		Operators:         cfg.Admin.Operators,
+		Blocklist:         cfg.Auth.Blocklist,
+		RateLimits:        rateLimits,
+	}
+
+	switch cfg.Db.Driver {
+	case "memory":
+		logger.Warn("using the in-memory bank service, nothing is persisted")
+
+		bankCtx.BankService = memory.NewBankService(&memory.BankConfig{
+			WalletAddress:       cfg.Wallet.Address.String(),
+			EscrowAddress:       cfg.Escrow.Address.String(),
+			EscrowDeadline:      escrowDeadline,
+			EscrowMinDeadline:   escrowMinDeadline,
+			EscrowMaxDeadline:   escrowMaxDeadline,
+			ChannelSettleWindow: cfg.Channels.SettleWindow,
+			ChannelLifetime:     channelLifetime,
+			FrozenAllowed:       frozenAllowed,
+			Fees:                fees,
+		})
+	case "", "postgres":
+		db := postgres.Connect(postgres.Config{
+			Dsn:          cfg.Db.Dsn,
+			MaxOpenConns: cfg.Db.MaxOpenConns,
+			MaxIdleConns: cfg.Db.MaxIdleConns,
+			MaxIdleTime:  cfg.Db.MaxIdleTime,
+		})
+
+		bankCtx.BankService = postgres.NewBankService(db, &postgres.BankConfig{
+			WalletAddress:       cfg.Wallet.Address.String(),
+			EscrowAddress:       cfg.Escrow.Address.String(),
+			EscrowDeadline:      escrowDeadline,
+			EscrowMinDeadline:   escrowMinDeadline,
+			EscrowMaxDeadline:   escrowMaxDeadline,
+			ChannelSettleWindow: cfg.Channels.SettleWindow,
+			ChannelLifetime:     channelLifetime,
+			FrozenAllowed:       frozenAllowed,
+			Fees:                fees,
+		})
+	case "sqlite":
+		db := sqlite.Connect(sqlite.Config{
+			Dsn:          cfg.Db.Dsn,
+			MaxOpenConns: cfg.Db.MaxOpenConns,
+			MaxIdleConns: cfg.Db.MaxIdleConns,
+			MaxIdleTime:  cfg.Db.MaxIdleTime,
+		})
+
+		bankCtx.BankService = sqlite.NewBankService(db, &sqlite.BankConfig{
+			WalletAddress:       cfg.Wallet.Address.String(),
+			EscrowAddress:       cfg.Escrow.Address.String(),
+			EscrowDeadline:      escrowDeadline,
+			EscrowMinDeadline:   escrowMinDeadline,
+			EscrowMaxDeadline:   escrowMaxDeadline,
+			ChannelSettleWindow: cfg.Channels.SettleWindow,
+			ChannelLifetime:     channelLifetime,
+			FrozenAllowed:       frozenAllowed,
+			Fees:                fees,
+		})
+	default:
+		logger.Fatal("unknown database driver", zap.String("driver", cfg.Db.Driver))
+	}
+
+	switch cfg.RateLimits.Store {
+	case "", "memory":
+		bankCtx.RateLimiter = memory.NewRateLimiter()
+	case "database":
+		bankCtx.RateLimiter = bankCtx.BankService
+	default:
+		logger.Fatal("unknown rate limit store", zap.String("store", cfg.RateLimits.Store))
 	}
-	bankCtx.BankService = postgres.NewBankService(db, &postgres.BankConfig{
-		WalletAddress:  cfg.Wallet.Address.String(),
-		EscrowAddress:  cfg.Escrow.Address.String(),
-		EscrowDeadline: cfg.Escrow.Deadline,
-	})
 
 	ki, err := types.ReadWallet(cfg.Wallet)
 	if err != nil {
@@ -117,9 +230,71 @@ func main() {
 	bankCtx.BlockChainService = blockchainService
 	bankCtx.RegisterValidators()
 
+	withdrawInterval, err := time.ParseDuration(cfg.Withdraw.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse withdraw interval", zap.Error(err))
+	}
+
+	withdrawLease, err := time.ParseDuration(cfg.Withdraw.Lease)
+	if err != nil {
+		logger.Fatal("failed to parse withdraw lease", zap.Error(err))
+	}
+
+	bankCtx.WithdrawalWorker = bank.NewWithdrawalWorker(bankCtx.BankService, blockchainService, withdrawInterval, withdrawLease, logger)
+	go bankCtx.WithdrawalWorker.Run(ctx)
+
+	sweepInterval, err := time.ParseDuration(cfg.Escrow.SweepInterval)
+	if err != nil {
+		logger.Fatal("failed to parse escrow sweep interval", zap.Error(err))
+	}
+
+	go bank.NewEscrowSweeper(bankCtx.BankService, sweepInterval, logger).Run(ctx)
+
+	channelSweepInterval, err := time.ParseDuration(cfg.Channels.SweepInterval)
+	if err != nil {
+		logger.Fatal("failed to parse channel sweep interval", zap.Error(err))
+	}
+
+	go bank.NewChannelSweeper(bankCtx.BankService, channelSweepInterval, logger).Run(ctx)
+
+	pruneInterval, err := time.ParseDuration(cfg.Prune.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse prune interval", zap.Error(err))
+	}
+
+	go bank.NewPruner(bankCtx.BankService, bankCtx.RateLimiter, pruneInterval, logger).Run(ctx)
+
+	deregistrationInterval, err := time.ParseDuration(cfg.Deregistration.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse deregistration interval", zap.Error(err))
+	}
+
+	go bank.NewDeregistrationWorker(bankCtx.BankService, deregistrationInterval, logger).Run(ctx)
+
+	solvencyInterval, err := time.ParseDuration(cfg.Solvency.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse solvency interval", zap.Error(err))
+	}
+
+	if cfg.Deposits.Watch {
+		depositInterval, err := time.ParseDuration(cfg.Deposits.Interval)
+		if err != nil {
+			logger.Fatal("failed to parse deposits interval", zap.Error(err))
+		}
+
+		go blockchain.NewWatcher(blockchainService, bank.NewDepositCrediter(bankCtx.BankService, logger), blockchain.WatcherConfig{
+			Interval:      depositInterval,
+			Confirmations: cfg.Deposits.Confirmations,
+			MaxBlocks:     cfg.Deposits.MaxBlocks,
+		}, logger).Run(ctx)
+	}
+
+	bankCtx.Solvency = bank.NewSolvencyAuditor(bankCtx.BankService, blockchainService, cfg.Wallet.Address, ki, solvencyInterval, logger)
+	go bankCtx.Solvency.Run(ctx)
+
 	httpServer.Log = logger
 	httpServer.RegisterMiddleWare()
-	httpServer.RegisterRoutes(bankCtx.Routes)
+	httpServer.RegisterRoutes(bankCtx.Routes, bankCtx.AdminRoutes)
 
 	if err := httpServer.Run(); err != nil {
 		logger.Fatal("failed to start http server", zap.Error(err))
@@ -28,7 +28,9 @@ func main() {
 	fidl.Commit = commit
 
 	var cfgFilePath string
+	var deregisterDst string
 	flag.StringVar(&cfgFilePath, "config", "etc/proxy.ini", "path to configuration file")
+	flag.StringVar(&deregisterDst, "deregister", "", "deregister from the banks, paying the final balance to this address, and exit")
 	flag.Parse()
 
 	cfg := proxy.LoadConfiguration(cfgFilePath)
@@ -65,6 +67,19 @@ func main() {
 
 	zap.ReplaceGlobals(logger)
 
+	if deregisterDst != "" {
+		if err := proxy.Deregister(ctx, cfg, deregisterDst); err != nil {
+			logger.Fatal("Failed to deregister", zap.Error(err))
+		}
+
+		return
+	}
+
+	trustedProxies, err := http.ParseTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		logger.Fatal("Failed to parse trusted proxies", zap.Error(err))
+	}
+
 	httpServer := http.New(&http.Config{
 		Addr:            cfg.HTTP.Addr,
 		Fqdn:            cfg.HTTP.Fqdn,
@@ -76,10 +91,18 @@ func main() {
 
 		TLS: cfg.HTTP.TLS,
 		Env: cfg.Env,
+
+		TrustedProxies: trustedProxies,
 	})
 
+	channels, err := proxy.NewChannels(cfg.Bank, cfg.Route, cfg.Wallet, cfg.Channels.Store)
+	if err != nil {
+		logger.Fatal("Failed to restore channel vouchers", zap.Error(err))
+	}
+
 	proxyCtx := proxy.Server{
 		Bank:          cfg.Bank,
+		Channels:      channels,
 		ExternalRoute: cfg.Route,
 		Provider:      cfg.Provider,
 		Server:        httpServer,
@@ -109,8 +132,13 @@ func main() {
 		logger.Fatal("Failed to register", zap.Error(err))
 	}
 
+	go proxyCtx.Channels.Run(ctx, cfg.Channels)
+
 	<-ctx.Done()
 
+	// Settle the vouchers received since the last tick before shutting down.
+	proxyCtx.Channels.Settle(context.Background())
+
 	logger.Info("Terminating...")
 
 	if err := httpServer.Close(); err != nil {
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/subvisual/fidl"
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/bank/postgres"
+	"github.com/subvisual/fidl/bank/sqlite"
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/types"
+)
+
+// nolint
+var (
+	version string
+	commit  string
+)
+
+// reconcile matches the deposits and withdrawals of a bank with the transfers of its wallet in a block
+// range, prints the report as JSON and exits with 1 when anything does not match.
+func main() {
+	fidl.Version = version
+	fidl.Commit = commit
+
+	var cfgFilePath string
+	var from, to uint64
+	flag.StringVar(&cfgFilePath, "config", "etc/bank.ini", "path to configuration file")
+	flag.Uint64Var(&from, "from", 0, "first block of the range")
+	flag.Uint64Var(&to, "to", 0, "last block of the range, defaults to the latest block")
+	flag.Parse()
+
+	cfg := bank.LoadConfiguration(cfgFilePath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	go func() { <-c; cancel() }()
+
+	var bankService bank.Service
+
+	switch cfg.Db.Driver {
+	case "", "postgres":
+		db := postgres.Connect(postgres.Config{
+			Dsn:          cfg.Db.Dsn,
+			MaxOpenConns: cfg.Db.MaxOpenConns,
+			MaxIdleConns: cfg.Db.MaxIdleConns,
+			MaxIdleTime:  cfg.Db.MaxIdleTime,
+		})
+
+		bankService = postgres.NewBankService(db, &postgres.BankConfig{WalletAddress: cfg.Wallet.Address.String()})
+	case "sqlite":
+		db := sqlite.Connect(sqlite.Config{
+			Dsn:          cfg.Db.Dsn,
+			MaxOpenConns: cfg.Db.MaxOpenConns,
+			MaxIdleConns: cfg.Db.MaxIdleConns,
+			MaxIdleTime:  cfg.Db.MaxIdleTime,
+		})
+
+		bankService = sqlite.NewBankService(db, &sqlite.BankConfig{WalletAddress: cfg.Wallet.Address.String()})
+	default:
+		log.Fatalf("Cannot reconcile the %q database driver", cfg.Db.Driver)
+	}
+
+	ki, err := types.ReadWallet(cfg.Wallet)
+	if err != nil {
+		log.Fatalf("Failed to read wallet: %v", err)
+	}
+
+	client, err := blockchain.NewService(&blockchain.Config{
+		RPCURL:         cfg.Blockchain.RPCURL,
+		VerifyInterval: cfg.Blockchain.VerifyInterval,
+	}, ki.PrivateKey, time.Duration(cfg.HTTP.WriteTimeout)*time.Second)
+	if err != nil {
+		log.Fatalf("Failed to create blockchain service: %v", err)
+	}
+
+	if to == 0 {
+		if to, err = client.LatestBlock(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if from > to {
+		log.Fatalf("Invalid block range: %d to %d", from, to)
+	}
+
+	report, err := reconcile(ctx, bankService, client, from, to)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Failed to encode report: %v", err)
+	}
+
+	if len(report.Issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// reconcile fetches the transfers of the block range and the entries recorded while it was mined or with
+// the same hashes. The transfers of entries that are not in the range are looked up by hash, so that an
+// entry is only reported missing when the chain does not have its transfer at all.
+func reconcile(ctx context.Context, bankService bank.Service, client *blockchain.Client, from uint64, to uint64) (bank.ReconcileReport, error) {
+	scan, err := client.Transfers(ctx, from, to)
+	if err != nil {
+		return bank.ReconcileReport{}, err
+	}
+
+	found := make(map[string]bool, len(scan.Transfers))
+	hashes := make([]string, 0, len(scan.Transfers))
+	for _, t := range scan.Transfers {
+		found[t.Hash] = true
+		hashes = append(hashes, t.Hash)
+	}
+
+	entries, err := bankService.ChainEntries(ctx, bank.ChainEntriesParams{Hashes: hashes, From: scan.Start, To: scan.End})
+	if err != nil {
+		return bank.ReconcileReport{}, err
+	}
+
+	transfers := scan.Transfers
+	for _, e := range entries {
+		if found[strings.ToLower(e.Hash)] {
+			continue
+		}
+
+		transfer, err := client.TransferByHash(ctx, e.Hash)
+
+		switch {
+		case errors.Is(err, blockchain.ErrTransferNotFound):
+		case err != nil:
+			return bank.ReconcileReport{}, err
+		default:
+			found[transfer.Hash] = true
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	return bank.Reconcile(bank.ReconcileParams{From: from, To: to, Transfers: transfers, Entries: entries}), nil
+}
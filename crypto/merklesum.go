@@ -0,0 +1,188 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Sums are hashed as 32 bytes. Amounts are bounded well below that, far above the FIL supply in attoFIL, so
+// that no sum of them overflows it.
+const (
+	sumBytes      = 32
+	maxAmountBits = 128
+)
+
+// MerkleSumSaltBytes is the length of the salt of a leaf.
+const MerkleSumSaltBytes = 32
+
+var ErrInvalidMerkleSumProof = errors.New("invalid merkle-sum proof")
+
+// MerkleSumLeaf is an entry of a Merkle-sum tree: who it belongs to and its amount. Its random salt is
+// hashed with them, so that the hash of a leaf, which is in the proofs of its neighbours, cannot be matched
+// against guesses of who it belongs to and what it holds by anyone the salt is not given to.
+type MerkleSumLeaf struct {
+	ID     string
+	Amount *big.Int
+	Salt   []byte
+}
+
+// NewMerkleSumSalt returns a random salt for a leaf.
+func NewMerkleSumSalt() ([]byte, error) {
+	salt := make([]byte, MerkleSumSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return salt, nil
+}
+
+// MerkleSumNode commits to the hashes and sums of its children, and to the sum of all the amounts below it.
+type MerkleSumNode struct {
+	Hash []byte
+	Sum  *big.Int
+}
+
+// MerkleSumStep is a sibling on the path from a leaf up to the root, on the left or on the right.
+type MerkleSumStep struct {
+	Node MerkleSumNode
+	Left bool
+}
+
+// MerkleSumTree lets a bank publish the total it owes in a single root, while each account can check that
+// its own amount is part of it. A node left without a sibling is moved up a level as is.
+type MerkleSumTree struct {
+	levels [][]MerkleSumNode
+}
+
+func NewMerkleSumTree(leaves []MerkleSumLeaf) (*MerkleSumTree, error) {
+	level := make([]MerkleSumNode, 0, len(leaves))
+	for _, leaf := range leaves {
+		if !validAmount(leaf.Amount) {
+			return nil, fmt.Errorf("invalid amount for leaf %s", leaf.ID)
+		}
+
+		if len(leaf.Salt) != MerkleSumSaltBytes {
+			return nil, fmt.Errorf("invalid salt for leaf %s", leaf.ID)
+		}
+
+		level = append(level, MerkleSumLeafNode(leaf))
+	}
+
+	tree := &MerkleSumTree{levels: [][]MerkleSumNode{level}}
+
+	for len(level) > 1 {
+		next := make([]MerkleSumNode, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+
+			next = append(next, merkleSumParent(level[i], level[i+1]))
+		}
+
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+
+	return tree, nil
+}
+
+// Root returns the root of the tree. The root of an empty tree sums to zero.
+func (t *MerkleSumTree) Root() MerkleSumNode {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		hash := sha256.Sum256(nil)
+		return MerkleSumNode{Hash: hash[:], Sum: new(big.Int)}
+	}
+
+	return top[0]
+}
+
+// Proof returns the siblings on the path from the leaf at index up to the root.
+func (t *MerkleSumTree) Proof(index int) ([]MerkleSumStep, error) {
+	if index < 0 || index >= len(t.levels[0]) {
+		return nil, fmt.Errorf("leaf index out of range: %d", index)
+	}
+
+	var proof []MerkleSumStep
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, MerkleSumStep{Node: level[sibling], Left: sibling < index})
+		}
+
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// VerifyMerkleSumProof checks that a leaf is part of the tree with the given root, so that its amount is
+// counted in the root's sum.
+func VerifyMerkleSumProof(root MerkleSumNode, leaf MerkleSumLeaf, proof []MerkleSumStep) error {
+	if !validAmount(leaf.Amount) {
+		return fmt.Errorf("%w: invalid leaf amount", ErrInvalidMerkleSumProof)
+	}
+
+	if len(leaf.Salt) != MerkleSumSaltBytes {
+		return fmt.Errorf("%w: invalid leaf salt", ErrInvalidMerkleSumProof)
+	}
+
+	// Every step at most doubles the sum, so a longer proof could overflow it.
+	if len(proof) > sumBytes*8-maxAmountBits {
+		return fmt.Errorf("%w: proof is too long", ErrInvalidMerkleSumProof)
+	}
+
+	node := MerkleSumLeafNode(leaf)
+	for _, step := range proof {
+		if !validAmount(step.Node.Sum) {
+			return fmt.Errorf("%w: invalid sibling sum", ErrInvalidMerkleSumProof)
+		}
+
+		if step.Left {
+			node = merkleSumParent(step.Node, node)
+		} else {
+			node = merkleSumParent(node, step.Node)
+		}
+	}
+
+	if !bytes.Equal(node.Hash, root.Hash) || root.Sum == nil || node.Sum.Cmp(root.Sum) != 0 {
+		return ErrInvalidMerkleSumProof
+	}
+
+	return nil
+}
+
+// MerkleSumLeafNode returns the node of a leaf, hashing its salt, ID and amount.
+func MerkleSumLeafNode(leaf MerkleSumLeaf) MerkleSumNode {
+	digest := sha256.New()
+	digest.Write([]byte{0})
+	digest.Write(leaf.Salt)
+	digest.Write([]byte(leaf.ID))
+	digest.Write([]byte{0})
+	digest.Write(leaf.Amount.FillBytes(make([]byte, sumBytes)))
+
+	return MerkleSumNode{Hash: digest.Sum(nil), Sum: new(big.Int).Set(leaf.Amount)}
+}
+
+func validAmount(amount *big.Int) bool {
+	return amount != nil && amount.Sign() != -1 && amount.BitLen() <= maxAmountBits
+}
+
+func merkleSumParent(left MerkleSumNode, right MerkleSumNode) MerkleSumNode {
+	sum := new(big.Int).Add(left.Sum, right.Sum)
+
+	digest := sha256.New()
+	digest.Write([]byte{1})
+	digest.Write(left.Hash)
+	digest.Write(left.Sum.FillBytes(make([]byte, sumBytes)))
+	digest.Write(right.Hash)
+	digest.Write(right.Sum.FillBytes(make([]byte, sumBytes)))
+
+	return MerkleSumNode{Hash: digest.Sum(nil), Sum: sum}
+}
@@ -0,0 +1,110 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestMerkleSumTree(t *testing.T) {
+	t.Parallel()
+
+	for size := range 8 {
+		leaves := make([]MerkleSumLeaf, 0, size)
+		total := new(big.Int)
+		for i := range size {
+			amount := big.NewInt(int64(i+1) * 1000)
+			leaves = append(leaves, MerkleSumLeaf{ID: fmt.Sprintf("f1account%d", i), Amount: amount, Salt: salt(t)})
+			total.Add(total, amount)
+		}
+
+		tree, err := NewMerkleSumTree(leaves)
+		if err != nil {
+			t.Fatalf("failed to build tree of %d leaves: %v", size, err)
+		}
+
+		root := tree.Root()
+		if root.Sum.Cmp(total) != 0 {
+			t.Errorf("tree of %d leaves: expected a sum of %s, got %s", size, total, root.Sum)
+		}
+
+		for i, leaf := range leaves {
+			proof, err := tree.Proof(i)
+			if err != nil {
+				t.Fatalf("failed to build proof of leaf %d: %v", i, err)
+			}
+
+			if err := VerifyMerkleSumProof(root, leaf, proof); err != nil {
+				t.Errorf("tree of %d leaves: proof of leaf %d rejected: %v", size, i, err)
+			}
+		}
+	}
+}
+
+func TestMerkleSumProofTampering(t *testing.T) {
+	t.Parallel()
+
+	leaves := []MerkleSumLeaf{
+		{ID: "f1alice", Amount: big.NewInt(100), Salt: salt(t)},
+		{ID: "f1bob", Amount: big.NewInt(250), Salt: salt(t)},
+		{ID: "f1carol", Amount: big.NewInt(50), Salt: salt(t)},
+	}
+
+	tree, err := NewMerkleSumTree(leaves)
+	if err != nil {
+		t.Fatalf("failed to build tree: %v", err)
+	}
+
+	root := tree.Root()
+
+	proof, err := tree.Proof(1)
+	if err != nil {
+		t.Fatalf("failed to build proof: %v", err)
+	}
+
+	if err := VerifyMerkleSumProof(root, MerkleSumLeaf{ID: "f1bob", Amount: big.NewInt(200), Salt: leaves[1].Salt}, proof); !errors.Is(err, ErrInvalidMerkleSumProof) {
+		t.Errorf("expected a changed amount to be rejected, got %v", err)
+	}
+
+	if err := VerifyMerkleSumProof(root, MerkleSumLeaf{ID: "f1mallory", Amount: big.NewInt(250), Salt: leaves[1].Salt}, proof); !errors.Is(err, ErrInvalidMerkleSumProof) {
+		t.Errorf("expected a changed ID to be rejected, got %v", err)
+	}
+
+	// Moving funds between siblings keeps the total, but not the hashes.
+	tampered := make([]MerkleSumStep, len(proof))
+	copy(tampered, proof)
+	tampered[0].Node.Sum = big.NewInt(0)
+	if err := VerifyMerkleSumProof(root, leaves[1], tampered); !errors.Is(err, ErrInvalidMerkleSumProof) {
+		t.Errorf("expected a changed sibling sum to be rejected, got %v", err)
+	}
+
+	tampered[0].Node.Sum = big.NewInt(-100)
+	if err := VerifyMerkleSumProof(root, leaves[1], tampered); !errors.Is(err, ErrInvalidMerkleSumProof) {
+		t.Errorf("expected a negative sibling sum to be rejected, got %v", err)
+	}
+
+	if _, err := NewMerkleSumTree([]MerkleSumLeaf{{ID: "f1alice", Amount: big.NewInt(-1), Salt: salt(t)}}); err == nil {
+		t.Errorf("expected a negative amount to be refused")
+	}
+
+	if _, err := NewMerkleSumTree([]MerkleSumLeaf{{ID: "f1alice", Amount: big.NewInt(100)}}); err == nil {
+		t.Errorf("expected a leaf without salt to be refused")
+	}
+
+	// Without its salt, a leaf cannot be recognised from its ID and amount.
+	if err := VerifyMerkleSumProof(root, MerkleSumLeaf{ID: "f1bob", Amount: big.NewInt(250), Salt: leaves[0].Salt}, proof); !errors.Is(err, ErrInvalidMerkleSumProof) {
+		t.Errorf("expected a wrong salt to be rejected, got %v", err)
+	}
+}
+
+func salt(t *testing.T) []byte {
+	t.Helper()
+
+	salt, err := NewMerkleSumSalt()
+	if err != nil {
+		t.Fatalf("failed to generate salt: %v", err)
+	}
+
+	return salt
+}
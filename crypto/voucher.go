@@ -0,0 +1,33 @@
+package crypto
+
+import (
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/pkg/crypto"
+	"github.com/subvisual/fidl/types"
+)
+
+func SignVoucher(privkey []byte, sigType crypto.SigType, voucher types.Voucher) (types.SignedVoucher, error) {
+	sig, err := Sign(privkey, sigType, voucher.Message())
+	if err != nil {
+		return types.SignedVoucher{}, err
+	}
+
+	out, err := sig.MarshalBinary()
+	if err != nil {
+		return types.SignedVoucher{}, fmt.Errorf("failed to marshal signature: %w", err)
+	}
+
+	return types.SignedVoucher{Voucher: voucher, Signature: out}, nil
+}
+
+// VerifyVoucher checks that the voucher was signed by the given address.
+func VerifyVoucher(voucher types.SignedVoucher, addr address.Address) error {
+	var sig crypto.Signature
+	if err := sig.UnmarshalBinary(voucher.Signature); err != nil {
+		return fmt.Errorf("failed to unmarshal voucher signature: %w", err)
+	}
+
+	return Verify(&sig, addr, voucher.Message())
+}
@@ -0,0 +1,46 @@
+package crypto
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/pkg/crypto"
+	_ "github.com/filecoin-project/venus/pkg/crypto/secp" // to run init()
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/types"
+)
+
+func TestVoucher(t *testing.T) {
+	t.Parallel()
+
+	privkey, _ := crypto.Generate(crypto.SigTypeSecp256k1)
+	pubkey, _ := crypto.ToPublic(crypto.SigTypeSecp256k1, privkey)
+	addr, _ := address.NewSecp256k1Address(pubkey)
+
+	voucher := types.Voucher{Channel: uuid.New(), Amount: types.NewFIL(big.NewInt(1000))}
+
+	signed, err := SignVoucher(privkey, crypto.SigTypeSecp256k1, voucher)
+	if err != nil {
+		t.Fatalf("failed to sign voucher: %v", err)
+	}
+
+	token, err := signed.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode voucher: %v", err)
+	}
+
+	decoded, err := types.DecodeVoucher(token)
+	if err != nil {
+		t.Fatalf("failed to decode voucher: %v", err)
+	}
+
+	if err := VerifyVoucher(decoded, addr); err != nil {
+		t.Errorf("error in VerifyVoucher function: %v", err)
+	}
+
+	decoded.Amount = types.NewFIL(big.NewInt(2000))
+	if VerifyVoucher(decoded, addr) == nil {
+		t.Errorf("VerifyVoucher accepted a voucher with a changed amount")
+	}
+}
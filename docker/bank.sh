@@ -17,5 +17,10 @@ if [ "$cmd" = "/bin/bash" ]; then
 fi
 
 DSN=$(sed -nr 's/dsn="(.*)"/\1/p' /etc/bank.ini | tr -d [:space:])
-/app/migrate -path=/app/postgres/migrations -database=$DSN up
+DRIVER=$(sed -nr 's/driver="(.*)"/\1/p' /etc/bank.ini | tr -d [:space:])
+case "$DRIVER" in
+  memory) ;;
+  sqlite) /app/migrate -path=/app/sqlite/migrations -database="sqlite3://$DSN" up ;;
+  *) /app/migrate -path=/app/postgres/migrations -database=$DSN up ;;
+esac
 exec gosu runner "$@"
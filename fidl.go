@@ -1,6 +1,7 @@
 package fidl
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/jmoiron/sqlx"
@@ -14,11 +15,10 @@ var (
 )
 
 type Queryable interface {
-	Get(dest interface{}, query string, args ...interface{}) error
-	QueryRow(query string, args ...any) *sql.Row
-	Select(dest interface{}, query string, args ...interface{}) error
-	NamedQuery(query string, arg interface{}) (*sqlx.Rows, error)
-	NamedExec(query string, arg interface{}) (sql.Result, error)
-	Exec(query string, args ...any) (sql.Result, error)
-	PrepareNamed(query string) (*sqlx.NamedStmt, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error)
 }
@@ -19,6 +19,7 @@ require (
 	github.com/gorilla/schema v1.4.1
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/ory/dockertest/v3 v3.11.0
 	github.com/spf13/cobra v1.8.1
 	github.com/stretchr/testify v1.9.0
@@ -1,5 +1,10 @@
 package http
 
+import (
+	"fmt"
+	"net"
+)
+
 type Logger struct {
 	Level string `toml:"level"`
 	Path  string `toml:"path"`
@@ -14,4 +19,22 @@ type HTTP struct {
 	WriteTimeout    int    `toml:"write-timeout"`
 	ShutdownTimeout int    `toml:"shutdown-timeout"`
 	TLS             bool   `toml:"tls"`
+
+	TrustedProxies []string `toml:"trusted-proxies"`
+}
+
+// ParseTrustedProxies parses the CIDRs of the proxies trusted to forward the IP of their clients.
+func ParseTrustedProxies(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %w", err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
 }
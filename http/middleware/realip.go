@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+var (
+	headerXForwardedFor = http.CanonicalHeaderKey("X-Forwarded-For")
+	headerXRealIP       = http.CanonicalHeaderKey("X-Real-IP")
+)
+
+// RealIP sets the remote address of a request to the client IP that a trusted proxy forwarded it for, from
+// its X-Forwarded-For or X-Real-IP header. Requests that do not come from a trusted proxy keep the address
+// of their connection, as anyone could set those headers.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if ip := forwardedIP(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// forwardedIP returns the client IP forwarded by a trusted proxy, or nothing when the request does not come
+// from one. X-Forwarded-For is read from the right, past the trusted proxies the request went through, as
+// the addresses left of them were set by the client.
+func forwardedIP(r *http.Request, trusted []*net.IPNet) string {
+	if !trustedIP(peerIP(r), trusted) {
+		return ""
+	}
+
+	if xff := r.Header.Get(headerXForwardedFor); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				return ""
+			}
+
+			if i == 0 || !trustedIP(ip, trusted) {
+				return ip.String()
+			}
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get(headerXRealIP))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+func peerIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(host)
+}
+
+func trustedIP(ip net.IP, trusted []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	t.Parallel()
+
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("failed to parse cidr: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		peer     string
+		xff      string
+		xrealip  string
+		expected string
+	}{
+		{"direct client", "192.0.2.1:1234", "", "", "192.0.2.1:1234"},
+		{"spoofed headers from a client", "192.0.2.1:1234", "198.51.100.7", "198.51.100.8", "192.0.2.1:1234"},
+		{"forwarded by a trusted proxy", "10.0.0.1:1234", "198.51.100.7", "", "198.51.100.7"},
+		{"real ip from a trusted proxy", "10.0.0.1:1234", "", "198.51.100.8", "198.51.100.8"},
+		{"client prepending to the chain", "10.0.0.1:1234", "203.0.113.9, 198.51.100.7", "", "198.51.100.7"},
+		{"chain of trusted proxies", "10.0.0.1:1234", "198.51.100.7, 10.0.0.2", "", "198.51.100.7"},
+		{"only trusted proxies", "10.0.0.1:1234", "10.0.0.3, 10.0.0.2", "", "10.0.0.3"},
+		{"garbage from a trusted proxy", "10.0.0.1:1234", "not-an-ip", "", "10.0.0.1:1234"},
+	}
+
+	for _, test := range tests {
+		var remoteAddr string
+		handler := RealIP([]*net.IPNet{proxies})(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			remoteAddr = r.RemoteAddr
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = test.peer
+		if test.xff != "" {
+			req.Header.Set("X-Forwarded-For", test.xff)
+		}
+		if test.xrealip != "" {
+			req.Header.Set("X-Real-IP", test.xrealip)
+		}
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if remoteAddr != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, remoteAddr)
+		}
+	}
+}
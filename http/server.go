@@ -28,6 +28,9 @@ type Config struct {
 
 	Env string
 	TLS bool
+
+	// TrustedProxies are the proxies whose forwarding headers give the IP of a request.
+	TrustedProxies []*net.IPNet
 }
 
 type Server struct {
@@ -37,20 +40,27 @@ type Server struct {
 	cfg      *Config
 	decoder  *schema.Decoder
 
+	// cancel cancels the context of the requests still running once the server is shut down.
+	cancel context.CancelFunc
+
 	Validate *validator.Validate
 	Log      *zap.Logger
 }
 
 func New(cfg *Config) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	srv := &Server{
 		server: &http.Server{
 			ReadTimeout:       time.Duration(cfg.ReadTimeout) * time.Second,
 			WriteTimeout:      time.Duration(cfg.WriteTimeout) * time.Second,
 			ReadHeaderTimeout: time.Duration(cfg.ReadTimeout) * time.Second,
+			BaseContext:       func(net.Listener) context.Context { return ctx },
 		},
 		router:   chi.NewRouter(),
 		cfg:      cfg,
 		decoder:  schema.NewDecoder(),
+		cancel:   cancel,
 		Validate: validator.New(),
 	}
 
@@ -84,9 +94,12 @@ func (s *Server) URI() string {
 	return fmt.Sprintf("%s://%s", s.Schema(), s.Host())
 }
 
+// Close waits for running requests to finish, for up to the shutdown timeout, and then cancels the ones left
+// along with the work they started.
 func (s *Server) Close() error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.ShutdownTimeout))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.cfg.ShutdownTimeout)*time.Second)
 	defer cancel()
+	defer s.cancel()
 
 	//nolint
 	return s.server.Shutdown(ctx)
@@ -95,7 +108,7 @@ func (s *Server) Close() error {
 func (s *Server) RegisterMiddleWare(middlewares ...func(http.Handler) http.Handler) {
 	// Register middleware
 	s.router.Use(middleware.RequestID)
-	s.router.Use(middleware.RealIP)
+	s.router.Use(mw.RealIP(s.cfg.TrustedProxies))
 	s.router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
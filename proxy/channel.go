@@ -0,0 +1,425 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/crypto"
+	"github.com/subvisual/fidl/request"
+	"github.com/subvisual/fidl/types"
+	"go.uber.org/zap"
+)
+
+const (
+	channelOpen    = "Open"
+	channelClosing = "Closing"
+	channelClosed  = "Closed"
+)
+
+type channelState struct {
+	mu       sync.Mutex
+	bank     Bank
+	client   types.Address
+	balance  *big.Int
+	settled  *big.Int
+	status   string
+	closesAt time.Time
+	latest   *types.SignedVoucher
+}
+
+// resolved reports whether the channel was read from its bank. The channels of the vouchers restored at
+// startup are not until the next refresh.
+func (s *channelState) resolved() bool {
+	return s.status != ""
+}
+
+// settleable reports whether the bank still accepts vouchers of the channel.
+func (s *channelState) settleable(now time.Time) bool {
+	switch s.status {
+	case channelOpen:
+		return true
+	case channelClosing:
+		return now.Before(s.closesAt)
+	default:
+		return false
+	}
+}
+
+// unsettled reports whether the latest voucher pays more than what was settled.
+func (s *channelState) unsettled() bool {
+	return s.latest != nil && s.latest.Amount.Cmp(s.settled) == 1
+}
+
+// apply replaces what the proxy knows of the channel with what its bank returned.
+func (s *channelState) apply(data channelData) {
+	s.balance = data.Escrow.Int
+	if s.settled == nil || data.Redeemed.Cmp(s.settled) == 1 {
+		s.settled = data.Redeemed.Int
+	}
+	s.status = data.Status
+	s.closesAt = data.ClosesAt
+}
+
+// Channels keeps the latest voucher of every payment channel the proxy was paid with, and settles them
+// with their banks in the background. Vouchers are stored before the retrieval they pay for is served, and
+// the state of every channel is read again from its bank periodically, so that a channel the client started
+// closing is no longer paid with.
+type Channels struct {
+	banks  map[string]Bank
+	route  Route
+	wallet types.Wallet
+	store  *voucherStore
+
+	mu       sync.Mutex
+	channels map[uuid.UUID]*channelState
+}
+
+// NewChannels restores the vouchers kept in the store directory. Their channels are read from the banks on
+// the first refresh, and settled if they still can be.
+func NewChannels(banks map[string]Bank, route Route, wallet types.Wallet, store string) (*Channels, error) {
+	vouchers, err := newVoucherStore(store)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := vouchers.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make(map[uuid.UUID]*channelState, len(restored))
+	for _, voucher := range restored {
+		channels[voucher.Channel] = &channelState{settled: new(big.Int), latest: &voucher}
+	}
+
+	return &Channels{
+		banks:    banks,
+		route:    route,
+		wallet:   wallet,
+		store:    vouchers,
+		channels: channels,
+	}, nil
+}
+
+// Accept checks a voucher before a retrieval. It must be signed by the client of an open channel with this
+// proxy, and pay at least the price on top of the previous voucher, without going over the channel balance.
+// An accepted voucher is stored before it is returned.
+func (c *Channels) Accept(ctx context.Context, voucher types.SignedVoucher, price types.FIL) error {
+	state, err := c.channel(ctx, voucher.Channel)
+	if err != nil {
+		return err
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.status != channelOpen {
+		return &request.Error{Message: "channel is not open", Status: http.StatusForbidden}
+	}
+
+	if err := crypto.VerifyVoucher(voucher, *state.client.Address); err != nil {
+		return &request.Error{Message: "invalid voucher signature", Status: http.StatusUnauthorized}
+	}
+
+	previous := state.settled
+	if state.latest != nil {
+		previous = state.latest.Amount.Int
+	}
+
+	if new(big.Int).Sub(voucher.Amount.Int, previous).Cmp(price.Int) == -1 {
+		return &request.Error{Message: "voucher does not cover the retrieval price", Status: http.StatusPaymentRequired}
+	}
+
+	if voucher.Amount.Cmp(state.balance) == 1 {
+		return &request.Error{Message: "voucher exceeds the channel balance", Status: http.StatusPaymentRequired}
+	}
+
+	if err := c.store.Save(voucher); err != nil {
+		return err
+	}
+
+	state.latest = &voucher
+
+	return nil
+}
+
+// Run refreshes the channels right away, to settle the restored vouchers, and then refreshes and settles
+// them on their own intervals.
+func (c *Channels) Run(ctx context.Context, cfg ChannelsConfig) {
+	c.Refresh(ctx)
+
+	settle := time.NewTicker(cfg.SettleInterval)
+	defer settle.Stop()
+
+	refresh := time.NewTicker(cfg.RefreshInterval)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-settle.C:
+			c.Settle(ctx)
+		case <-refresh.C:
+			c.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh reads every channel again from its bank. Channels that can no longer be settled are forgotten,
+// and the latest voucher of a closing channel is settled right away, before its settle window is over.
+func (c *Channels) Refresh(ctx context.Context) {
+	c.mu.Lock()
+	known := make(map[uuid.UUID]*channelState, len(c.channels))
+	for id, state := range c.channels {
+		known[id] = state
+	}
+	c.mu.Unlock()
+
+	var closing bool
+	now := time.Now()
+
+	for id, state := range known {
+		state.mu.Lock()
+		banks := c.banks
+		if state.resolved() {
+			banks = map[string]Bank{state.bank.URL: state.bank}
+		}
+		state.mu.Unlock()
+
+		bank, data, err := c.lookup(ctx, id, banks)
+		if err != nil {
+			zap.L().Error("failed to refresh channel", zap.String("channel", id.String()), zap.Error(err))
+
+			continue
+		}
+
+		client, err := types.NewAddressFromString(data.Client)
+		if err != nil {
+			zap.L().Error("failed to parse channel client", zap.String("channel", id.String()), zap.Error(err))
+
+			continue
+		}
+
+		state.mu.Lock()
+		state.bank, state.client = bank, client
+		state.apply(data)
+		settleable := state.settleable(now)
+		closing = closing || (state.status == channelClosing && state.unsettled())
+		state.mu.Unlock()
+
+		if !settleable {
+			c.forget(id, state)
+		}
+	}
+
+	if closing {
+		c.Settle(ctx)
+	}
+}
+
+// Settle redeems the latest voucher of every channel that was paid since its last settlement.
+func (c *Channels) Settle(ctx context.Context) {
+	type pending struct {
+		id      uuid.UUID
+		state   *channelState
+		bank    Bank
+		voucher types.SignedVoucher
+	}
+
+	c.mu.Lock()
+	known := make(map[uuid.UUID]*channelState, len(c.channels))
+	for id, state := range c.channels {
+		known[id] = state
+	}
+	c.mu.Unlock()
+
+	var batch []pending
+
+	now := time.Now()
+	for id, state := range known {
+		state.mu.Lock()
+		if state.resolved() && state.settleable(now) && state.unsettled() {
+			batch = append(batch, pending{id: id, state: state, bank: state.bank, voucher: *state.latest})
+		}
+		state.mu.Unlock()
+	}
+
+	for _, p := range batch {
+		data, err := c.settle(ctx, p.bank, p.voucher)
+		if err != nil {
+			zap.L().Error("failed to settle channel", zap.String("channel", p.id.String()), zap.Error(err))
+
+			continue
+		}
+
+		p.state.mu.Lock()
+		p.state.apply(data.Data)
+		if p.voucher.Amount.Cmp(p.state.settled) == 1 {
+			p.state.settled = p.voucher.Amount.Int
+		}
+
+		closed := data.Data.Status == channelClosed
+		if !closed && !p.state.unsettled() {
+			if err := c.store.Remove(p.id); err != nil {
+				zap.L().Error("failed to remove settled voucher", zap.String("channel", p.id.String()), zap.Error(err))
+			}
+		}
+		p.state.mu.Unlock()
+
+		if closed {
+			c.forget(p.id, p.state)
+		}
+
+		zap.L().Info("settled channel", zap.String("channel", p.id.String()), zap.String("amount", p.voucher.Amount.String()))
+	}
+}
+
+// forget drops a channel that can no longer be settled, with its stored voucher.
+func (c *Channels) forget(id uuid.UUID, state *channelState) {
+	state.mu.Lock()
+	if state.unsettled() {
+		zap.L().Warn("channel closed with an unsettled voucher", zap.String("channel", id.String()), zap.String("amount", state.latest.Amount.String()))
+	}
+	state.status = channelClosed
+	state.mu.Unlock()
+
+	c.mu.Lock()
+	delete(c.channels, id)
+	c.mu.Unlock()
+
+	if err := c.store.Remove(id); err != nil {
+		zap.L().Error("failed to remove voucher", zap.String("channel", id.String()), zap.Error(err))
+	}
+}
+
+type channelData struct {
+	Client   string    `json:"client"`
+	Proxy    string    `json:"proxy"`
+	Escrow   types.FIL `json:"escrow"`
+	Redeemed types.FIL `json:"redeemed"`
+	Status   string    `json:"status"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type channelResponse struct {
+	Status string      `json:"status"`
+	Data   channelData `json:"data"`
+}
+
+// channel returns the known state of a channel, looking it up at the banks the first time it is seen.
+func (c *Channels) channel(ctx context.Context, id uuid.UUID) (*channelState, error) {
+	c.mu.Lock()
+	state, ok := c.channels[id]
+	c.mu.Unlock()
+
+	if ok {
+		state.mu.Lock()
+		resolved := state.resolved()
+		state.mu.Unlock()
+
+		if resolved {
+			return state, nil
+		}
+	}
+
+	bank, data, err := c.lookup(ctx, id, c.banks)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Proxy != c.wallet.Address.String() || (!ok && data.Status != channelOpen) {
+		return nil, &request.Error{Message: "channel is not open with this proxy", Status: http.StatusForbidden}
+	}
+
+	client, err := types.NewAddressFromString(data.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse channel client: %w", err)
+	}
+
+	c.mu.Lock()
+	if state, ok = c.channels[id]; !ok {
+		state = &channelState{}
+		c.channels[id] = state
+	}
+	c.mu.Unlock()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.bank, state.client = bank, client
+	state.apply(data)
+
+	return state, nil
+}
+
+// lookup reads a channel from the first of the banks that has it.
+func (c *Channels) lookup(ctx context.Context, id uuid.UUID, banks map[string]Bank) (Bank, channelData, error) {
+	for key, bank := range banks {
+		endpoint, _ := url.Parse(bank.URL)
+
+		req, err := signedRequest(c.wallet, http.MethodGet, endpoint.JoinPath(c.route.BankChannels, id.String()), nil, "")
+		if err != nil {
+			return Bank{}, channelData{}, err
+		}
+
+		resp, err := req.Get(ctx)
+		if err != nil || resp.Status != http.StatusOK {
+			zap.L().Debug("no channel found at", zap.String("bank", key), zap.String("channel", id.String()))
+
+			continue
+		}
+
+		var res channelResponse
+		if err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&res); err != nil {
+			return Bank{}, channelData{}, fmt.Errorf("error decoding the channel: %w", err)
+		}
+
+		return bank, res.Data, nil
+	}
+
+	return Bank{}, channelData{}, &request.Error{Message: "channel not found", Status: http.StatusNotFound}
+}
+
+func (c *Channels) settle(ctx context.Context, bank Bank, voucher types.SignedVoucher) (*channelResponse, error) {
+	token, err := voucher.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"voucher": token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed payload marshaling: %w", err)
+	}
+
+	endpoint, _ := url.Parse(bank.URL)
+
+	resp, err := signedPost(ctx, c.wallet, endpoint.JoinPath(c.route.BankChannels, voucher.Channel.String(), "settle"), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to settle: %w", err)
+	}
+
+	if resp.Status != http.StatusOK {
+		return nil, &request.Error{
+			Message: resp.Body,
+			Status:  resp.Status,
+		}
+	}
+
+	var res channelResponse
+	if err := json.NewDecoder(bytes.NewReader(resp.Body)).Decode(&res); err != nil {
+		return nil, fmt.Errorf("error decoding the channel: %w", err)
+	}
+
+	return &res, nil
+}
@@ -28,13 +28,22 @@ type ForwarderConfig struct {
 }
 
 type Route struct {
-	BankRedeem   string `toml:"bank-redeem"`
-	BankRegister string `toml:"bank-register"`
-	BankVerify   string `toml:"bank-verify"`
+	BankChannels   string `toml:"bank-channels"`
+	BankDeregister string `toml:"bank-deregister"`
+	BankRedeem     string `toml:"bank-redeem"`
+	BankRegister   string `toml:"bank-register"`
+	BankVerify     string `toml:"bank-verify"`
+}
+
+type ChannelsConfig struct {
+	SettleInterval  time.Duration `toml:"settle-interval"`
+	RefreshInterval time.Duration `toml:"refresh-interval"`
+	Store           string        `toml:"store"`
 }
 
 type Config struct {
 	Bank      map[string]Bank `toml:"bank"`
+	Channels  ChannelsConfig  `toml:"channels"`
 	Env       string          `toml:"env"`
 	Forwarder ForwarderConfig `toml:"forwarder"`
 	HTTP      http.HTTP       `toml:"http"`
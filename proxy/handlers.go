@@ -41,8 +41,13 @@ func (s *Server) handleRetrieval(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if params.Voucher != "" {
+		s.handleVoucherRetrieval(w, r, params.Voucher)
+		return
+	}
+
 	ctx := r.Context()
-	bank, err := Verify(ctx, s.Bank, s.ExternalRoute, s.Wallet, params.Authorization, s.Provider.Cost)
+	bank, price, err := Verify(ctx, s.Bank, s.ExternalRoute, s.Wallet, params.Authorization)
 	if err != nil {
 		if errors.As(err, &requestError) {
 			s.JSON(w, r, requestError.Status, requestError.Message)
@@ -68,7 +73,7 @@ func (s *Server) handleRetrieval(w http.ResponseWriter, r *http.Request) {
 	fil.Int = new(big.Int).Div(
 		new(big.Int).Mul(
 			big.NewInt(bytesSent),
-			s.Provider.Cost.Int,
+			price.Int,
 		),
 		big.NewInt(s.Provider.SectorSize),
 	)
@@ -90,6 +95,41 @@ func (s *Server) handleRetrieval(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// handleVoucherRetrieval serves a retrieval paid with a payment channel voucher. The voucher is only checked
+// against the channel here, and the latest one is settled with the bank in the background.
+func (s *Server) handleVoucherRetrieval(w http.ResponseWriter, r *http.Request, token string) {
+	var requestError *request.Error
+
+	voucher, err := types.DecodeVoucher(token)
+	if err != nil {
+		s.JSON(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.Channels.Accept(r.Context(), voucher, s.Provider.Cost); err != nil {
+		if errors.As(err, &requestError) {
+			s.JSON(w, r, requestError.Status, requestError.Message)
+		} else {
+			s.JSON(w, r, http.StatusInternalServerError, err)
+		}
+
+		return
+	}
+
+	piece := chi.URLParam(r, "piece")
+	_, r, cleanup := s.Forwarder.tracker.Start(r)
+	defer cleanup()
+
+	s.Forwarder.Forward(piece, w, r)
+
+	zap.L().Debug(
+		"finished retrieval",
+		zap.String("channel", voucher.Channel.String()),
+		zap.Any("amount", voucher.Amount),
+		zap.String("piece", piece),
+	)
+}
+
 func (s *Server) handleBankList(w http.ResponseWriter, r *http.Request) {
 	payload := make([]BankListResponse, 0, len(s.Bank))
 
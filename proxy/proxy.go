@@ -9,6 +9,7 @@ import (
 type Server struct {
 	*http.Server
 	Bank          map[string]Bank
+	Channels      *Channels
 	ExternalRoute Route
 	Forwarder     *Forwarder
 	Provider      Provider
@@ -16,7 +17,8 @@ type Server struct {
 }
 
 type RetrievalParams struct {
-	Authorization uuid.UUID `validate:"required"`
+	Authorization uuid.UUID `validate:"required_without=Voucher"`
+	Voucher       string    `validate:"required_without=Authorization"`
 }
 
 type BankListResponse struct {
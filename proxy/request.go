@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/subvisual/fidl/crypto"
@@ -26,15 +28,10 @@ func Register(cfg Config) error {
 		return fmt.Errorf("failed payload marshaling: %w", err)
 	}
 
-	sig, err := sign(cfg.Wallet, body)
-	if err != nil {
-		return err
-	}
-
 	for key, val := range cfg.Bank {
 		go func() {
 			endpoint, _ := url.Parse(val.URL)
-			if err := register(endpoint.JoinPath(cfg.Route.BankRegister), cfg.Wallet.Address.String(), body, sig); err != nil {
+			if err := register(endpoint.JoinPath(cfg.Route.BankRegister), cfg.Wallet, body); err != nil {
 				zap.L().Error("failed to register bank", zap.String("bank", key), zap.Error(err))
 			} else {
 				zap.L().Info("registered with bank", zap.String("bank", key))
@@ -45,18 +42,35 @@ func Register(cfg Config) error {
 	return nil
 }
 
-func register(endpoint *url.URL, wallet string, payload []byte, sig []byte) error {
-	buff := bytes.NewBuffer(payload)
-	resp, err := request.New().
-		SetEndpoint(endpoint).
-		SetBody(buff).
-		AppendHeader("content-type", "application/json").
-		AppendHeader("sig", hex.EncodeToString(sig)).
-		AppendHeader("pub", wallet).
-		AppendHeader("msg", hex.EncodeToString(payload)).
-		Post(context.Background())
+// Deregister asks every bank to stop new authorizations against the proxy, and to pay its balance to the
+// destination once the outstanding ones are redeemed or expire.
+func Deregister(ctx context.Context, cfg Config, destination string) error {
+	body, err := json.Marshal(map[string]any{
+		"dst": destination,
+	})
 	if err != nil {
-		return fmt.Errorf("register bank: %w", err)
+		return fmt.Errorf("failed payload marshaling: %w", err)
+	}
+
+	var errs []error
+	for key, val := range cfg.Bank {
+		endpoint, _ := url.Parse(val.URL)
+		if err := deregister(ctx, endpoint.JoinPath(cfg.Route.BankDeregister), cfg.Wallet, body); err != nil {
+			errs = append(errs, fmt.Errorf("bank %s: %w", key, err))
+
+			continue
+		}
+
+		zap.L().Info("deregistering from bank", zap.String("bank", key))
+	}
+
+	return errors.Join(errs...)
+}
+
+func deregister(ctx context.Context, endpoint *url.URL, wallet types.Wallet, payload []byte) error {
+	resp, err := signedPost(ctx, wallet, endpoint, payload)
+	if err != nil {
+		return fmt.Errorf("deregister bank: %w", err)
 	}
 
 	if resp.Status != http.StatusOK {
@@ -69,25 +83,37 @@ func register(endpoint *url.URL, wallet string, payload []byte, sig []byte) erro
 	return nil
 }
 
-func Verify(ctx context.Context, banks map[string]Bank, route Route, wallet types.Wallet, id uuid.UUID, amount types.FIL) (*Bank, error) {
-	body, err := json.Marshal(map[string]any{
-		"id":     id,
-		"amount": amount,
-	})
+func register(endpoint *url.URL, wallet types.Wallet, payload []byte) error {
+	resp, err := signedPost(context.Background(), wallet, endpoint, payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed payload marshaling: %w", err)
+		return fmt.Errorf("register bank: %w", err)
 	}
 
-	sig, err := sign(wallet, body)
+	if resp.Status != http.StatusOK {
+		return &request.Error{
+			Message: resp.Body,
+			Status:  resp.Status,
+		}
+	}
+
+	return nil
+}
+
+// Verify locks an authorization at the first bank that holds it, and returns that bank along with the
+// price the authorization was made at, which the retrieval is charged at.
+func Verify(ctx context.Context, banks map[string]Bank, route Route, wallet types.Wallet, id uuid.UUID) (*Bank, types.FIL, error) {
+	body, err := json.Marshal(map[string]any{
+		"id": id,
+	})
 	if err != nil {
-		return nil, err
+		return nil, types.FIL{}, fmt.Errorf("failed payload marshaling: %w", err)
 	}
 
 	errors := make(map[string]any, len(banks))
 	for key, val := range banks {
 		zap.L().Debug("looking up authorization at", zap.String("bank", key))
 		endpoint, _ := url.Parse(val.URL)
-		err := verify(ctx, endpoint.JoinPath(route.BankVerify), wallet, sig, body)
+		price, err := verify(ctx, endpoint.JoinPath(route.BankVerify), wallet, body)
 		if err != nil {
 			zap.L().Debug("no authorization found at", zap.String("bank", key))
 			errors[val.URL] = parseVerifyError(err)
@@ -97,37 +123,39 @@ func Verify(ctx context.Context, banks map[string]Bank, route Route, wallet type
 
 		zap.L().Debug("authorization found at", zap.String("bank", key))
 
-		return &val, nil
+		return &val, price, nil
 	}
 
-	return nil, &request.Error{
+	return nil, types.FIL{}, &request.Error{
 		Message: errors,
 		Status:  http.StatusNotFound,
 	}
 }
 
-func verify(ctx context.Context, endpoint *url.URL, wallet types.Wallet, sig []byte, body []byte) error {
-	buff := bytes.NewBuffer(body)
-	resp, err := request.New().
-		SetEndpoint(endpoint).
-		SetBody(buff).
-		AppendHeader("content-type", "application/json").
-		AppendHeader("sig", hex.EncodeToString(sig)).
-		AppendHeader("pub", wallet.Address.String()).
-		AppendHeader("msg", hex.EncodeToString(body)).
-		Post(ctx)
+func verify(ctx context.Context, endpoint *url.URL, wallet types.Wallet, body []byte) (types.FIL, error) {
+	var res struct {
+		Data struct {
+			Price types.FIL `json:"price"`
+		} `json:"data"`
+	}
+
+	resp, err := signedPost(ctx, wallet, endpoint, body)
 	if err != nil {
-		return fmt.Errorf("failed to verify: %w", err)
+		return types.FIL{}, fmt.Errorf("failed to verify: %w", err)
 	}
 
 	if resp.Status != http.StatusOK {
-		return &request.Error{
+		return types.FIL{}, &request.Error{
 			Message: resp.Body,
 			Status:  resp.Status,
 		}
 	}
 
-	return nil
+	if err := json.Unmarshal(resp.Body, &res); err != nil {
+		return types.FIL{}, fmt.Errorf("error decoding the verify response: %w", err)
+	}
+
+	return res.Data.Price, nil
 }
 
 func Redeem(ctx context.Context, endpoint *url.URL, wallet types.Wallet, id uuid.UUID, amount types.FIL) error {
@@ -139,20 +167,7 @@ func Redeem(ctx context.Context, endpoint *url.URL, wallet types.Wallet, id uuid
 		return fmt.Errorf("failed payload marshaling: %w", err)
 	}
 
-	sig, err := sign(wallet, body)
-	if err != nil {
-		return err
-	}
-
-	buff := bytes.NewBuffer(body)
-	resp, err := request.New().
-		SetEndpoint(endpoint).
-		SetBody(buff).
-		AppendHeader("content-type", "application/json").
-		AppendHeader("sig", hex.EncodeToString(sig)).
-		AppendHeader("pub", wallet.Address.String()).
-		AppendHeader("msg", hex.EncodeToString(body)).
-		Post(ctx)
+	resp, err := signedPost(ctx, wallet, endpoint, body)
 	if err != nil {
 		return fmt.Errorf("failed to redeem %w", err)
 	}
@@ -167,7 +182,64 @@ func Redeem(ctx context.Context, endpoint *url.URL, wallet types.Wallet, id uuid
 	return nil
 }
 
-func sign(wallet types.Wallet, body []byte) ([]byte, error) {
+const postAttempts = 3
+
+// signedPost sends a signed request, retrying it when no response comes back. Every attempt carries the
+// same idempotency key, so that the bank runs the request once.
+func signedPost(ctx context.Context, wallet types.Wallet, endpoint *url.URL, body []byte) (*request.Response, error) {
+	key, err := request.NewNonce()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := signedRequest(wallet, http.MethodPost, endpoint, body, key)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := req.Post(ctx)
+		if err == nil {
+			return resp, nil
+		}
+
+		if attempt == postAttempts || ctx.Err() != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+}
+
+func signedRequest(wallet types.Wallet, method string, endpoint *url.URL, body []byte, idempotencyKey string) (*request.Request, error) {
+	nonce, err := request.NewNonce()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	timestamp := time.Now().UTC().Unix()
+	msg := request.CanonicalMessage(method, request.Target(endpoint), body, timestamp, nonce, idempotencyKey)
+
+	sig, err := sign(wallet, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.New().
+		SetEndpoint(endpoint).
+		SetBody(bytes.NewBuffer(body)).
+		AppendHeader("content-type", "application/json").
+		AppendHeader(request.HeaderSignature, hex.EncodeToString(sig)).
+		AppendHeader(request.HeaderPublicKey, wallet.Address.String()).
+		AppendHeader(request.HeaderTimestamp, strconv.FormatInt(timestamp, 10)).
+		AppendHeader(request.HeaderNonce, nonce)
+
+	if idempotencyKey != "" {
+		req = req.AppendHeader(request.HeaderIdempotencyKey, idempotencyKey)
+	}
+
+	return req, nil
+}
+
+func sign(wallet types.Wallet, msg []byte) ([]byte, error) {
 	ki, err := types.ReadWallet(wallet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read wallet: %w", err)
@@ -179,7 +251,7 @@ func sign(wallet types.Wallet, body []byte) ([]byte, error) {
 	}
 	ki.Type = sigType
 
-	sig, err := crypto.Sign(ki.PrivateKey, ki.Type, body)
+	sig, err := crypto.Sign(ki.PrivateKey, ki.Type, msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign: %w", err)
 	}
@@ -191,6 +263,7 @@ func sign(wallet types.Wallet, body []byte) ([]byte, error) {
 
 	return out, nil
 }
+
 func parseVerifyError(value error) string {
 	type ErrorResponse struct {
 		Status string `json:"status"`
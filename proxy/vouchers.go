@@ -0,0 +1,122 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/subvisual/fidl/types"
+)
+
+// voucherStore keeps the latest voucher of every channel on disk, one file per channel named after it, so
+// that what the storage provider was paid and did not settle yet survives a restart.
+type voucherStore struct {
+	dir string
+}
+
+func newVoucherStore(dir string) (*voucherStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create voucher store: %w", err)
+	}
+
+	return &voucherStore{dir: dir}, nil
+}
+
+// Save replaces the voucher of its channel. The voucher is written to a temporary file that is renamed over
+// the previous one, so a crash leaves either of them in place.
+func (s *voucherStore) Save(voucher types.SignedVoucher) error {
+	token, err := voucher.Encode()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".voucher-*")
+	if err != nil {
+		return fmt.Errorf("failed to create voucher file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(token); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write voucher: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync voucher: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close voucher file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(voucher.Channel)); err != nil {
+		return fmt.Errorf("failed to store voucher: %w", err)
+	}
+
+	return s.sync()
+}
+
+// Remove forgets the voucher of a channel.
+func (s *voucherStore) Remove(id uuid.UUID) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove voucher: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns every stored voucher.
+func (s *voucherStore) Load() ([]types.SignedVoucher, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read voucher store: %w", err)
+	}
+
+	var vouchers []types.SignedVoucher
+	for _, entry := range entries {
+		id, err := uuid.Parse(entry.Name())
+		if entry.IsDir() || err != nil {
+			continue
+		}
+
+		token, err := os.ReadFile(s.path(id))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read voucher: %w", err)
+		}
+
+		voucher, err := types.DecodeVoucher(string(token))
+		if err != nil {
+			return nil, fmt.Errorf("voucher %s: %w", id, err)
+		}
+
+		if voucher.Channel != id {
+			return nil, fmt.Errorf("voucher %s is stored as %s", voucher.Channel, id)
+		}
+
+		vouchers = append(vouchers, voucher)
+	}
+
+	return vouchers, nil
+}
+
+func (s *voucherStore) path(id uuid.UUID) string {
+	return filepath.Join(s.dir, id.String())
+}
+
+// sync flushes the directory, so that a rename survives a crash.
+func (s *voucherStore) sync() error {
+	dir, err := os.Open(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to open voucher store: %w", err)
+	}
+	defer dir.Close()
+
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("failed to sync voucher store: %w", err)
+	}
+
+	return nil
+}
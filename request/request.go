@@ -136,3 +136,38 @@ func (r *Request) Get(ctx context.Context) (*Response, error) {
 
 	return &Response{Body: body, Status: resp.StatusCode}, nil
 }
+
+func (r *Request) Delete(ctx context.Context) (*Response, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, r.endpoint.String(), r.body)
+	if err != nil {
+		// nolint:wrapcheck
+		return nil, err
+	}
+
+	for k, v := range r.headers {
+		req.Header.Add(k, v)
+	}
+
+	client := http.DefaultClient
+	resp, err := client.Do(req)
+	if err != nil {
+		// nolint:wrapcheck
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		// nolint:wrapcheck
+		return nil, err
+	}
+
+	return &Response{Body: body, Status: resp.StatusCode}, nil
+}
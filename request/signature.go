@@ -0,0 +1,59 @@
+package request
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderSignature = "sig"
+	HeaderPublicKey = "pub"
+	HeaderTimestamp = "ts"
+	HeaderNonce     = "nonce"
+
+	HeaderIdempotencyKey = "Idempotency-Key"
+)
+
+// CanonicalMessage returns the bytes a wallet signs to authenticate a request.
+// It binds the signature to the method, target, body and a unique, timestamped nonce,
+// and to the idempotency key of the request when it has one.
+func CanonicalMessage(method string, target string, body []byte, timestamp int64, nonce string, idempotencyKey string) []byte {
+	digest := sha256.Sum256(body)
+
+	lines := []string{
+		strings.ToUpper(method),
+		target,
+		hex.EncodeToString(digest[:]),
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+	}
+
+	if idempotencyKey != "" {
+		lines = append(lines, idempotencyKey)
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// Target returns the escaped path and query of an URL, as seen by the server.
+func Target(endpoint *url.URL) string {
+	if endpoint.RawQuery == "" {
+		return endpoint.EscapedPath()
+	}
+
+	return endpoint.EscapedPath() + "?" + endpoint.RawQuery
+}
+
+func NewNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
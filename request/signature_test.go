@@ -0,0 +1,65 @@
+package request
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+)
+
+func TestCanonicalMessage(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"amount":"1"}`)
+	msg := CanonicalMessage("post", "/api/v1/withdraw", body, 1700000000, "abc", "")
+
+	if !bytes.Equal(msg, CanonicalMessage("POST", "/api/v1/withdraw", body, 1700000000, "abc", "")) {
+		t.Errorf("canonical message should not depend on method case")
+	}
+
+	variants := [][]byte{
+		CanonicalMessage("GET", "/api/v1/withdraw", body, 1700000000, "abc", ""),
+		CanonicalMessage("POST", "/api/v1/deposit", body, 1700000000, "abc", ""),
+		CanonicalMessage("POST", "/api/v1/withdraw", []byte(`{"amount":"2"}`), 1700000000, "abc", ""),
+		CanonicalMessage("POST", "/api/v1/withdraw", body, 1700000001, "abc", ""),
+		CanonicalMessage("POST", "/api/v1/withdraw", body, 1700000000, "abd", ""),
+		CanonicalMessage("POST", "/api/v1/withdraw", body, 1700000000, "abc", "key"),
+	}
+
+	for i, variant := range variants {
+		if bytes.Equal(msg, variant) {
+			t.Errorf("variant %d should produce a different canonical message", i)
+		}
+	}
+}
+
+func TestTarget(t *testing.T) {
+	t.Parallel()
+
+	endpoint, _ := url.Parse("http://localhost:8091/api/v1/fetch/baga6ea4seaq?authorization=0191f2a4-7f1b-7c3e-9a50-4d2b6f0e8a11")
+	if got := Target(endpoint); got != "/api/v1/fetch/baga6ea4seaq?authorization=0191f2a4-7f1b-7c3e-9a50-4d2b6f0e8a11" {
+		t.Errorf("unexpected target: %s", got)
+	}
+
+	endpoint, _ = url.Parse("http://localhost:8090/api/v1/balance")
+	if got := Target(endpoint); got != "/api/v1/balance" {
+		t.Errorf("unexpected target: %s", got)
+	}
+}
+
+func TestNewNonce(t *testing.T) {
+	t.Parallel()
+
+	first, err := NewNonce()
+	if err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+
+	second, err := NewNonce()
+	if err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+
+	if first == second {
+		t.Errorf("nonces should be unique")
+	}
+}
@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/cli"
+	"github.com/subvisual/fidl/proxy"
+	"github.com/subvisual/fidl/tests/setup"
+	"github.com/subvisual/fidl/types"
+)
+
+func TestAuthorizations(t *testing.T) { // nolint:paralleltest
+	if err := setup.RunMigrations("UP", migr); err != nil {
+		t.Fatalf("could not run up migrations: %v", err)
+	}
+
+	proxyCfg, err := setup.Proxy(proxyPrice)
+	if err != nil {
+		t.Fatalf("could not setup proxy info: %v", err)
+	}
+
+	if err := proxy.Register(proxyCfg); err != nil {
+		t.Log("failed to register proxy", err)
+		t.Fail()
+	}
+
+	cfg, cl, ki, err := setup.CLI()
+	if err != nil {
+		t.Fatalf("could not setup CLI info: %v", err)
+	}
+
+	bankEndpoint := url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s:%d", bankFqdn, bankPort),
+	}
+
+	// nolint:goconst
+	amount := "5 FIL"
+
+	var fil types.FIL
+	err = fil.UnmarshalJSON([]byte(amount))
+	if err != nil {
+		t.Fatalf("error unmarshalling amount data: %v", err)
+	}
+
+	bankEthAddr, _, err := types.ParseAddress(bankWalletAddress)
+	if err != nil {
+		t.Fatalf("failed to parse bank wallet public address: %v", err)
+	}
+
+	blockchainService, err := blockchain.NewService(&blockchain.Config{
+		RPCURL:                      cfg.Blockchain.RPCURL,
+		GasLimitMultiplier:          cfg.Blockchain.GasLimitMultiplier,
+		GasPriceMultiplier:          cfg.Blockchain.GasPriceMultiplier,
+		PriorityFeePerGasMultiplier: cfg.Blockchain.PriorityFeePerGasMultiplier,
+	}, ki.PrivateKey, 0)
+	if err != nil {
+		t.Fatalf("failed to create blockchain service: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	defer cancel()
+
+	hash, err := blockchainService.Transfer(ctx, bankEthAddr, fil)
+	if err != nil {
+		t.Fatalf("failed to transfer funds: %v", err)
+	}
+
+	t.Logf("Transferring funds, transaction hash: %s", hash)
+
+	depositOpts := cli.DepositOptions{
+		Amount:            amount,
+		BankAddress:       bankEndpoint.String(),
+		BankWalletAddress: bankWalletAddress,
+		FIL:               fil,
+		TransactionHash:   hash,
+	}
+
+	if err := cl.Validate.Struct(depositOpts); err != nil {
+		t.Errorf("failed to validate: %v", err)
+	}
+
+	res, err := cli.Deposit(ctx, ki, cfg.Wallet.Address, cfg.Route.Deposit, depositOpts)
+	if err != nil {
+		t.Fatalf("failed to deposit: %v", err)
+	}
+
+	assert.Equal(t, res.Status, "success")
+	assert.Equal(t, res.Data.FIL.String(), "5 FIL")
+
+	authorizeOpts := cli.AuthorizeOptions{
+		BankAddress:  bankEndpoint.String(),
+		ProxyInput:   proxyCfg.Wallet.Address.String(),
+		ProxyAddress: proxyCfg.Wallet.Address,
+	}
+
+	if err := cl.Validate.Struct(authorizeOpts); err != nil {
+		t.Errorf("failed to validate: %v", err)
+	}
+
+	var ids []string
+	for range 2 {
+		res, err := cli.Authorize(ki, cfg.Wallet.Address, cfg.Route.Authorize, authorizeOpts)
+		if err != nil {
+			t.Fatalf("failed to authorize: %v", err)
+		}
+
+		ids = append(ids, res.Data.ID.String())
+	}
+
+	_, _, err = proxy.Verify(context.Background(), proxyCfg.Bank, proxyCfg.Route, proxyCfg.Wallet, uuid.MustParse(ids[0]))
+	if err != nil {
+		t.Errorf("failed to verify: %v", err)
+	}
+
+	cancelOpts := cli.CancelAuthorizationOptions{
+		BankAddress:   bankEndpoint.String(),
+		Authorization: ids[1],
+	}
+
+	if _, err := cli.CancelAuthorization(ki, cfg.Wallet.Address, cfg.Route.Authorizations, cancelOpts); err != nil {
+		t.Errorf("failed to cancel: %v", err)
+	}
+
+	var tests = []struct {
+		status   string
+		expected []string
+	}{
+		{"", []string{"Cancelled", "Locked"}},
+		{"locked", []string{"Locked"}},
+		{"cancelled", []string{"Cancelled"}},
+		{"redeemed", []string{}},
+	}
+
+	for _, test := range tests {
+		listOpts := cli.AuthorizationsOptions{
+			BankAddress: bankEndpoint.String(),
+			Status:      test.status,
+		}
+
+		if err := cl.Validate.Struct(listOpts); err != nil {
+			t.Errorf("failed to validate: %v", err)
+		}
+
+		res, err := cli.Authorizations(ki, cfg.Wallet.Address, cfg.Route.Authorizations, listOpts)
+		if err != nil {
+			t.Errorf("failed to list authorizations: %v", err)
+			continue
+		}
+
+		statuses := []string{}
+		for _, a := range res.Data.Authorizations {
+			statuses = append(statuses, a.Status)
+		}
+
+		assert.Equal(t, test.expected, statuses)
+	}
+
+	showOpts := cli.AuthorizationsOptions{
+		BankAddress:   bankEndpoint.String(),
+		Authorization: ids[1],
+	}
+
+	auth, err := cli.Authorization(ki, cfg.Wallet.Address, cfg.Route.Authorizations, showOpts)
+	if err != nil {
+		t.Fatalf("failed to show authorization: %v", err)
+	}
+
+	assert.Equal(t, auth.Data.Status, "Cancelled")
+	assert.Equal(t, auth.Data.Remaining.String(), "0 FIL")
+	assert.Equal(t, auth.Data.Proxy, proxyCfg.Wallet.Address.String())
+
+	if err := setup.RunMigrations("DOWN", migr); err != nil {
+		t.Fatalf("could not run down migrations: %v", err)
+	}
+}
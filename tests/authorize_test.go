@@ -95,19 +95,28 @@ func TestAuthorize(t *testing.T) { // nolint:paralleltest
 	assert.Equal(t, res.Status, "success")
 	assert.Equal(t, res.Data.FIL.String(), "5 FIL")
 
+	proxyInput := proxyCfg.Wallet.Address.String()
+	proxyAddress := proxyCfg.Wallet.Address
+
 	var tests = []struct {
 		bankaddress  string
 		proxyinput   string
 		proxyaddress types.Address
+		amount       string
+		maxprice     string
+		expiresin    string
 		expected     string
 		authorized   string
 	}{
-		{bankEndpoint.String(), proxyCfg.Wallet.Address.String(), proxyCfg.Wallet.Address, "4 FIL", proxyPrice},
-		{bankEndpoint.String(), proxyCfg.Wallet.Address.String(), proxyCfg.Wallet.Address, "3 FIL", proxyPrice},
-		{bankEndpoint.String(), proxyCfg.Wallet.Address.String(), proxyCfg.Wallet.Address, "2 FIL", proxyPrice},
-		{bankEndpoint.String(), proxyCfg.Wallet.Address.String(), proxyCfg.Wallet.Address, "1 FIL", proxyPrice},
-		{bankEndpoint.String(), proxyCfg.Wallet.Address.String(), proxyCfg.Wallet.Address, "0 FIL", proxyPrice},
-		{bankEndpoint.String(), proxyCfg.Wallet.Address.String(), proxyCfg.Wallet.Address, "not have enough funds", ""},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "", "", "", "4 FIL", proxyPrice},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "2 FIL", "", "", "2 FIL", "2 FIL"},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "", "0.5 FIL", "", "the proxy price is above your maximum price", ""},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "0.5 FIL", "", "", "invalid authorization", ""},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "", "", "1000h", "invalid authorization", ""},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "", "", "30m", "invalid authorization", ""},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "", "1 FIL", "2h", "1 FIL", proxyPrice},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "", "", "", "0 FIL", proxyPrice},
+		{bankEndpoint.String(), proxyInput, proxyAddress, "", "", "", "not have enough funds", ""},
 	}
 
 	for _, test := range tests {
@@ -115,6 +124,9 @@ func TestAuthorize(t *testing.T) { // nolint:paralleltest
 			BankAddress:  test.bankaddress,
 			ProxyInput:   test.proxyinput,
 			ProxyAddress: test.proxyaddress,
+			Amount:       test.amount,
+			MaxPrice:     test.maxprice,
+			ExpiresIn:    test.expiresin,
 		}
 
 		if err := cl.Validate.Struct(authorizeOpts); err != nil {
@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/subvisual/fidl/bank"
+	"github.com/subvisual/fidl/bank/banktest"
+	"github.com/subvisual/fidl/bank/postgres"
+	"github.com/subvisual/fidl/tests/setup"
+)
+
+func TestConformance(t *testing.T) { // nolint:paralleltest
+	banktest.Run(t, func(t *testing.T, cfg banktest.Config) bank.Service {
+		t.Helper()
+
+		if err := setup.RunMigrations("UP", migr); err != nil {
+			t.Fatalf("could not run up migrations: %v", err)
+		}
+
+		t.Cleanup(func() {
+			if err := setup.RunMigrations("DOWN", migr); err != nil {
+				t.Fatalf("could not run down migrations: %v", err)
+			}
+		})
+
+		return postgres.NewBankService(db, &postgres.BankConfig{
+			WalletAddress:       cfg.WalletAddress,
+			EscrowAddress:       cfg.EscrowAddress,
+			EscrowDeadline:      cfg.EscrowDeadline,
+			EscrowMinDeadline:   cfg.EscrowMinDeadline,
+			EscrowMaxDeadline:   cfg.EscrowMaxDeadline,
+			ChannelSettleWindow: cfg.ChannelSettleWindow,
+			ChannelLifetime:     cfg.ChannelLifetime,
+			FrozenAllowed:       cfg.FrozenAllowed,
+			Fees:                cfg.Fees,
+		})
+	})
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/subvisual/fidl/bank/postgres"
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/cli"
+	"github.com/subvisual/fidl/proxy"
+	"github.com/subvisual/fidl/tests/setup"
+	"github.com/subvisual/fidl/types"
+)
+
+func TestDeregister(t *testing.T) { // nolint:paralleltest
+	if err := setup.RunMigrations("UP", migr); err != nil {
+		t.Fatalf("could not run up migrations: %v", err)
+	}
+
+	proxyCfg, err := setup.Proxy(proxyPrice)
+	if err != nil {
+		t.Fatalf("could not setup proxy info: %v", err)
+	}
+
+	if err := proxy.Register(proxyCfg); err != nil {
+		t.Log("failed to register proxy", err)
+		t.Fail()
+	}
+
+	cfg, cl, ki, err := setup.CLI()
+	if err != nil {
+		t.Fatalf("could not setup CLI info: %v", err)
+	}
+
+	bankEndpoint := url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s:%d", bankFqdn, bankPort),
+	}
+
+	// nolint:goconst
+	amount := "5 FIL"
+
+	var fil types.FIL
+	err = fil.UnmarshalJSON([]byte(amount))
+	if err != nil {
+		t.Fatalf("error unmarshalling amount data: %v", err)
+	}
+
+	bankEthAddr, _, err := types.ParseAddress(bankWalletAddress)
+	if err != nil {
+		t.Fatalf("failed to parse bank wallet public address: %v", err)
+	}
+
+	blockchainService, err := blockchain.NewService(&blockchain.Config{
+		RPCURL:                      cfg.Blockchain.RPCURL,
+		GasLimitMultiplier:          cfg.Blockchain.GasLimitMultiplier,
+		GasPriceMultiplier:          cfg.Blockchain.GasPriceMultiplier,
+		PriorityFeePerGasMultiplier: cfg.Blockchain.PriorityFeePerGasMultiplier,
+	}, ki.PrivateKey, 0)
+	if err != nil {
+		t.Fatalf("failed to create blockchain service: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	defer cancel()
+
+	hash, err := blockchainService.Transfer(ctx, bankEthAddr, fil)
+	if err != nil {
+		t.Fatalf("failed to transfer funds: %v", err)
+	}
+
+	t.Logf("Transferring funds, transaction hash: %s", hash)
+
+	depositOpts := cli.DepositOptions{
+		Amount:            amount,
+		BankAddress:       bankEndpoint.String(),
+		BankWalletAddress: bankWalletAddress,
+		FIL:               fil,
+		TransactionHash:   hash,
+	}
+
+	if err := cl.Validate.Struct(depositOpts); err != nil {
+		t.Errorf("failed to validate: %v", err)
+	}
+
+	res, err := cli.Deposit(ctx, ki, cfg.Wallet.Address, cfg.Route.Deposit, depositOpts)
+	if err != nil {
+		t.Fatalf("failed to deposit: %v", err)
+	}
+
+	assert.Equal(t, res.Status, "success")
+	assert.Equal(t, res.Data.FIL.String(), "5 FIL")
+
+	authorizeOpts := cli.AuthorizeOptions{
+		BankAddress:  bankEndpoint.String(),
+		ProxyInput:   proxyCfg.Wallet.Address.String(),
+		ProxyAddress: proxyCfg.Wallet.Address,
+	}
+
+	if err := cl.Validate.Struct(authorizeOpts); err != nil {
+		t.Errorf("failed to validate: %v", err)
+	}
+
+	auth, err := cli.Authorize(ki, cfg.Wallet.Address, cfg.Route.Authorize, authorizeOpts)
+	if err != nil {
+		t.Fatalf("failed to authorize: %v", err)
+	}
+
+	_, price, err := proxy.Verify(ctx, proxyCfg.Bank, proxyCfg.Route, proxyCfg.Wallet, auth.Data.ID)
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+
+	// Only deregister from the bank under test.
+	deregisterCfg := proxyCfg
+	deregisterCfg.Bank = map[string]proxy.Bank{"one": {URL: bankEndpoint.String()}}
+	destination := proxyCfg.Wallet.Address.String()
+
+	if err := proxy.Deregister(ctx, deregisterCfg, destination); err != nil {
+		t.Fatalf("failed to deregister: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		action   func() error
+		expected string
+	}{
+		{"authorize", func() error {
+			_, err := cli.Authorize(ki, cfg.Wallet.Address, cfg.Route.Authorize, authorizeOpts)
+			return err // nolint:wrapcheck
+		}, "storage provider is not active"},
+		{"deregister twice", func() error {
+			return proxy.Deregister(ctx, deregisterCfg, destination)
+		}, "storage provider is not active"},
+		{"redeem outstanding", func() error {
+			return proxy.Redeem(ctx, bankEndpoint.JoinPath(proxyCfg.Route.BankRedeem), proxyCfg.Wallet, auth.Data.ID, price)
+		}, ""},
+	}
+
+	for _, test := range tests {
+		err := test.action()
+		if test.expected == "" {
+			assert.NoError(t, err, test.name)
+		} else if assert.Error(t, err, test.name) {
+			assert.Contains(t, err.Error(), test.expected, test.name)
+		}
+	}
+
+	bankService := postgres.NewBankService(db, &postgres.BankConfig{
+		WalletAddress:       bankWalletAddress,
+		ChannelSettleWindow: "1h",
+	})
+
+	// The background worker may have finalized it already, so only the outcome is checked.
+	if _, err := bankService.FinalizeDeregistrations(context.Background(), 10); err != nil {
+		t.Fatalf("failed to finalize deregistrations: %v", err)
+	}
+
+	var sp struct {
+		Status        int64  `db:"status_id"`
+		PayoutAddress string `db:"payout_address"`
+	}
+
+	query :=
+		`
+		SELECT sp.status_id, sp.payout_address
+		FROM storage_providers sp
+		JOIN accounts a ON a.id = sp.id
+		WHERE a.wallet_address = $1
+		`
+
+	if err := db.Get(&sp, query, destination); err != nil {
+		t.Fatalf("failed to fetch storage provider: %v", err)
+	}
+
+	assert.Equal(t, int64(postgres.StorageProviderInactive), sp.Status)
+	assert.Equal(t, destination, sp.PayoutAddress)
+
+	var payout string
+	if err := db.Get(&payout, "SELECT value::text FROM withdrawals WHERE wallet_address = $1", destination); err != nil {
+		t.Fatalf("failed to fetch final payout: %v", err)
+	}
+
+	assert.Equal(t, price.Int.String(), payout)
+
+	// The redeemed authorization is kept as history.
+	var history int
+	if err := db.Get(&history, "SELECT count(*) FROM escrow WHERE uuid = $1", auth.Data.ID); err != nil {
+		t.Fatalf("failed to fetch authorization: %v", err)
+	}
+
+	assert.Equal(t, 1, history)
+
+	if err := setup.RunMigrations("DOWN", migr); err != nil {
+		t.Fatalf("could not run down migrations: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/subvisual/fidl/blockchain"
+	"github.com/subvisual/fidl/cli"
+	"github.com/subvisual/fidl/crypto"
+	"github.com/subvisual/fidl/proxy"
+	"github.com/subvisual/fidl/request"
+	"github.com/subvisual/fidl/tests/setup"
+	"github.com/subvisual/fidl/types"
+)
+
+func TestIdempotency(t *testing.T) { // nolint:paralleltest
+	if err := setup.RunMigrations("UP", migr); err != nil {
+		t.Fatalf("could not run up migrations: %v", err)
+	}
+
+	proxyCfg, err := setup.Proxy(proxyPrice)
+	if err != nil {
+		t.Fatalf("could not setup proxy info: %v", err)
+	}
+
+	if err := proxy.Register(proxyCfg); err != nil {
+		t.Log("failed to register proxy", err)
+		t.Fail()
+	}
+
+	cfg, cl, ki, err := setup.CLI()
+	if err != nil {
+		t.Fatalf("could not setup CLI info: %v", err)
+	}
+
+	bankEndpoint := url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s:%d", bankFqdn, bankPort),
+	}
+
+	// nolint:goconst
+	amount := "5 FIL"
+
+	var fil types.FIL
+	err = fil.UnmarshalJSON([]byte(amount))
+	if err != nil {
+		t.Fatalf("error unmarshalling amount data: %v", err)
+	}
+
+	bankEthAddr, _, err := types.ParseAddress(bankWalletAddress)
+	if err != nil {
+		t.Fatalf("failed to parse bank wallet public address: %v", err)
+	}
+
+	blockchainService, err := blockchain.NewService(&blockchain.Config{
+		RPCURL:                      cfg.Blockchain.RPCURL,
+		GasLimitMultiplier:          cfg.Blockchain.GasLimitMultiplier,
+		GasPriceMultiplier:          cfg.Blockchain.GasPriceMultiplier,
+		PriorityFeePerGasMultiplier: cfg.Blockchain.PriorityFeePerGasMultiplier,
+	}, ki.PrivateKey, 0)
+	if err != nil {
+		t.Fatalf("failed to create blockchain service: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	defer cancel()
+
+	hash, err := blockchainService.Transfer(ctx, bankEthAddr, fil)
+	if err != nil {
+		t.Fatalf("failed to transfer funds: %v", err)
+	}
+
+	t.Logf("Transferring funds, transaction hash: %s", hash)
+
+	depositOpts := cli.DepositOptions{
+		Amount:            amount,
+		BankAddress:       bankEndpoint.String(),
+		BankWalletAddress: bankWalletAddress,
+		FIL:               fil,
+		TransactionHash:   hash,
+	}
+
+	if err := cl.Validate.Struct(depositOpts); err != nil {
+		t.Errorf("failed to validate: %v", err)
+	}
+
+	res, err := cli.Deposit(ctx, ki, cfg.Wallet.Address, cfg.Route.Deposit, depositOpts)
+	if err != nil {
+		t.Fatalf("failed to deposit: %v", err)
+	}
+
+	assert.Equal(t, res.Status, "success")
+	assert.Equal(t, res.Data.FIL.String(), "5 FIL")
+
+	authorizeURL := bankEndpoint.JoinPath(cfg.Route.Authorize)
+	body := []byte(fmt.Sprintf(`{"proxy":%q}`, proxyCfg.Wallet.Address.String()))
+	other := []byte(fmt.Sprintf(`{"proxy":%q,"amount":"2 FIL"}`, proxyCfg.Wallet.Address.String()))
+
+	var tests = []struct {
+		name     string
+		key      string
+		body     []byte
+		status   int
+		replayed bool
+	}{
+		{"first request", "authorize-1", body, http.StatusOK, false},
+		{"retry", "authorize-1", body, http.StatusOK, true},
+		{"retry again", "authorize-1", body, http.StatusOK, true},
+		{"same key, other body", "authorize-1", other, http.StatusUnprocessableEntity, false},
+		{"new key", "authorize-2", body, http.StatusOK, false},
+	}
+
+	var first []byte
+	for _, test := range tests {
+		resp, payload := signedPost(t, ki, cfg.Wallet.Address, authorizeURL, test.body, test.key)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.name)
+		assert.Equal(t, test.replayed, resp.Header.Get("Idempotent-Replayed") == "true", test.name)
+
+		switch {
+		case first == nil:
+			first = payload
+		case test.replayed:
+			assert.Equal(t, first, payload, test.name)
+		case test.status == http.StatusOK:
+			assert.NotEqual(t, first, payload, test.name)
+		}
+	}
+
+	// Only the first request and the one with a new key were authorized.
+	balance, err := cli.Balance(ki, cfg.Wallet.Address, cfg.Route.Balance, cli.BalanceOptions{BankAddress: bankEndpoint.String()})
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+
+	assert.Equal(t, "3 FIL", balance.Data.FIL.String())
+	assert.Equal(t, "2 FIL", balance.Data.Escrow.String())
+
+	if err := setup.RunMigrations("DOWN", migr); err != nil {
+		t.Fatalf("could not run down migrations: %v", err)
+	}
+}
+
+func signedPost(t *testing.T, ki types.KeyInfo, addr types.Address, endpoint *url.URL, body []byte, key string) (*http.Response, []byte) {
+	t.Helper()
+
+	_, sigType, err := types.ParseAddress(addr.String())
+	if err != nil {
+		t.Fatalf("failed to parse wallet public address: %v", err)
+	}
+	ki.Type = sigType
+
+	nonce, err := request.NewNonce()
+	if err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+
+	timestamp := time.Now().UTC().Unix()
+	msg := request.CanonicalMessage(http.MethodPost, request.Target(endpoint), body, timestamp, nonce, key)
+
+	sig, err := crypto.Sign(ki.PrivateKey, ki.Type, msg)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	raw, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal signature: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set(request.HeaderSignature, hex.EncodeToString(raw))
+	req.Header.Set(request.HeaderPublicKey, addr.String())
+	req.Header.Set(request.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(request.HeaderNonce, nonce)
+	req.Header.Set(request.HeaderIdempotencyKey, key)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	return resp, payload
+}
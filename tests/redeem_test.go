@@ -148,7 +148,7 @@ func TestRedeem(t *testing.T) { // nolint:paralleltest
 
 		ctx := context.Background()
 
-		_, err = proxy.Verify(ctx, proxyCfg.Bank, proxyCfg.Route, proxyCfg.Wallet, res.Data.ID, cost)
+		_, _, err = proxy.Verify(ctx, proxyCfg.Bank, proxyCfg.Route, proxyCfg.Wallet, res.Data.ID)
 		if err != nil {
 			t.Errorf("failed to verify: %v", err)
 		}
@@ -135,12 +135,12 @@ func TestRefund(t *testing.T) { // nolint:paralleltest
 			query :=
 				`
 				UPDATE escrow
-  					SET created_at = $2
+  					SET expires_at = $2
   					WHERE uuid = $1
 				`
-			args := []any{res.Data.ID, time.Now().UTC().Add(-25 * time.Hour)}
+			args := []any{res.Data.ID, time.Now().UTC().Add(-time.Hour)}
 			if _, err := db.Exec(query, args...); err != nil {
-				t.Log("failed to updated created_at: ", err)
+				t.Log("failed to updated expires_at: ", err)
 			}
 		}
 	}
@@ -3,7 +3,9 @@ package tests
 import (
 	"log"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/subvisual/fidl/proxy"
 	"github.com/subvisual/fidl/tests/setup"
 )
@@ -23,6 +25,50 @@ func TestRegister(t *testing.T) { // nolint:paralleltest
 		t.Fail()
 	}
 
+	query :=
+		`
+		SELECT p.price::text
+		FROM storage_provider_prices p
+		JOIN accounts a ON a.id = p.storage_provider_id
+		WHERE a.wallet_address = $1
+		ORDER BY p.id
+		`
+
+	// Registration happens in the background, so wait for the price history to reach the expected length.
+	history := func(length int) []string {
+		var prices []string
+		for range 50 {
+			if err := db.Select(&prices, query, cfg.Wallet.Address.String()); err != nil {
+				t.Fatalf("failed to fetch price history: %v", err)
+			}
+
+			if len(prices) >= length {
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		return prices
+	}
+
+	assert.Equal(t, []string{"1000000000000000000"}, history(1))
+
+	// Registering again, as a restarted proxy does, updates the price and records the new one only.
+	for _, price := range []string{"2 FIL", "2 FIL"} {
+		repriced, err := setup.Proxy(price)
+		if err != nil {
+			t.Fatalf("could not setup proxy info: %v", err)
+		}
+
+		if err := proxy.Register(repriced); err != nil {
+			t.Log("failed to register proxy", err)
+			t.Fail()
+		}
+
+		assert.Equal(t, []string{"1000000000000000000", "2000000000000000000"}, history(2))
+	}
+
 	if err := setup.RunMigrations("DOWN", migr); err != nil {
 		log.Fatalf("could not run down migrations: %v", err)
 	}
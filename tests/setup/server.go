@@ -52,6 +52,11 @@ func Server(cfg bank.Config, db *postgres.DB) {
 
 	zap.ReplaceGlobals(logger)
 
+	trustedProxies, err := http.ParseTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		logger.Fatal("failed to parse trusted proxies", zap.Error(err))
+	}
+
 	httpServer := http.New(&http.Config{
 		Addr:            cfg.HTTP.Addr,
 		Fqdn:            cfg.HTTP.Fqdn,
@@ -63,18 +68,77 @@ func Server(cfg bank.Config, db *postgres.DB) {
 
 		TLS: cfg.HTTP.TLS,
 		Env: cfg.Env,
+
+		TrustedProxies: trustedProxies,
 	})
 
+	requestWindow, err := time.ParseDuration(cfg.Auth.Window)
+	if err != nil {
+		logger.Fatal("failed to parse auth window", zap.Error(err))
+	}
+
+	idempotencyTTL, err := time.ParseDuration(cfg.Idempotency.TTL)
+	if err != nil {
+		logger.Fatal("failed to parse idempotency ttl", zap.Error(err))
+	}
+
+	escrowDeadline, err := time.ParseDuration(cfg.Escrow.Deadline)
+	if err != nil {
+		logger.Fatal("failed to parse escrow deadline", zap.Error(err))
+	}
+
+	escrowMinDeadline, err := time.ParseDuration(cfg.Escrow.MinDeadline)
+	if err != nil {
+		logger.Fatal("failed to parse escrow min deadline", zap.Error(err))
+	}
+
+	escrowMaxDeadline, err := time.ParseDuration(cfg.Escrow.MaxDeadline)
+	if err != nil {
+		logger.Fatal("failed to parse escrow max deadline", zap.Error(err))
+	}
+
+	channelLifetime, err := time.ParseDuration(cfg.Channels.Lifetime)
+	if err != nil {
+		logger.Fatal("failed to parse channel lifetime", zap.Error(err))
+	}
+
+	fees, err := bank.ParseFeeSchedule(cfg.Fees)
+	if err != nil {
+		logger.Fatal("failed to parse fees", zap.Error(err))
+	}
+
+	rateLimits, err := bank.ParseRateLimits(cfg.RateLimits)
+	if err != nil {
+		logger.Fatal("failed to parse rate limits", zap.Error(err))
+	}
+
+	frozenAllowed, err := bank.ParseOperations(cfg.Accounts.FrozenAllowed)
+	if err != nil {
+		logger.Fatal("failed to parse operations allowed to frozen accounts", zap.Error(err))
+	}
+
 	bankCtx := bank.Server{
 		Server: httpServer,
 
 		CustomReadTimeout: time.Duration(cfg.HTTP.WriteTimeout) * time.Second,
+		RequestWindow:     requestWindow,
+		IdempotencyTTL:    idempotencyTTL,
+		Operators:         cfg.Admin.Operators,
+		Blocklist:         cfg.Auth.Blocklist,
+		RateLimits:        rateLimits,
 	}
 	bankCtx.BankService = postgres.NewBankService(db, &postgres.BankConfig{
-		WalletAddress:  cfg.Wallet.Address.String(),
-		EscrowAddress:  cfg.Escrow.Address.String(),
-		EscrowDeadline: cfg.Escrow.Deadline,
+		WalletAddress:       cfg.Wallet.Address.String(),
+		EscrowAddress:       cfg.Escrow.Address.String(),
+		EscrowDeadline:      escrowDeadline,
+		EscrowMinDeadline:   escrowMinDeadline,
+		EscrowMaxDeadline:   escrowMaxDeadline,
+		ChannelSettleWindow: cfg.Channels.SettleWindow,
+		ChannelLifetime:     channelLifetime,
+		FrozenAllowed:       frozenAllowed,
+		Fees:                fees,
 	})
+	bankCtx.RateLimiter = bankCtx.BankService
 
 	cfg.Wallet.Path = "../" + cfg.Wallet.Path
 
@@ -96,9 +160,58 @@ func Server(cfg bank.Config, db *postgres.DB) {
 	bankCtx.BlockChainService = blockchainService
 	bankCtx.RegisterValidators()
 
+	withdrawInterval, err := time.ParseDuration(cfg.Withdraw.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse withdraw interval", zap.Error(err))
+	}
+
+	withdrawLease, err := time.ParseDuration(cfg.Withdraw.Lease)
+	if err != nil {
+		logger.Fatal("failed to parse withdraw lease", zap.Error(err))
+	}
+
+	bankCtx.WithdrawalWorker = bank.NewWithdrawalWorker(bankCtx.BankService, blockchainService, withdrawInterval, withdrawLease, logger)
+	go bankCtx.WithdrawalWorker.Run(ctx)
+
+	sweepInterval, err := time.ParseDuration(cfg.Escrow.SweepInterval)
+	if err != nil {
+		logger.Fatal("failed to parse escrow sweep interval", zap.Error(err))
+	}
+
+	go bank.NewEscrowSweeper(bankCtx.BankService, sweepInterval, logger).Run(ctx)
+
+	channelSweepInterval, err := time.ParseDuration(cfg.Channels.SweepInterval)
+	if err != nil {
+		logger.Fatal("failed to parse channel sweep interval", zap.Error(err))
+	}
+
+	go bank.NewChannelSweeper(bankCtx.BankService, channelSweepInterval, logger).Run(ctx)
+
+	pruneInterval, err := time.ParseDuration(cfg.Prune.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse prune interval", zap.Error(err))
+	}
+
+	go bank.NewPruner(bankCtx.BankService, bankCtx.RateLimiter, pruneInterval, logger).Run(ctx)
+
+	deregistrationInterval, err := time.ParseDuration(cfg.Deregistration.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse deregistration interval", zap.Error(err))
+	}
+
+	go bank.NewDeregistrationWorker(bankCtx.BankService, deregistrationInterval, logger).Run(ctx)
+
+	solvencyInterval, err := time.ParseDuration(cfg.Solvency.Interval)
+	if err != nil {
+		logger.Fatal("failed to parse solvency interval", zap.Error(err))
+	}
+
+	bankCtx.Solvency = bank.NewSolvencyAuditor(bankCtx.BankService, blockchainService, cfg.Wallet.Address, ki, solvencyInterval, logger)
+	go bankCtx.Solvency.Run(ctx)
+
 	httpServer.Log = logger
 	httpServer.RegisterMiddleWare()
-	httpServer.RegisterRoutes(bankCtx.Routes)
+	httpServer.RegisterRoutes(bankCtx.Routes, bankCtx.AdminRoutes)
 
 	if err := httpServer.Run(); err != nil {
 		logger.Fatal("failed to start http server", zap.Error(err))
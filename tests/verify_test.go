@@ -133,7 +133,7 @@ func TestVerify(t *testing.T) { // nolint:paralleltest
 			assert.Equal(t, res.Data.Escrow.String(), test.authorized)
 
 			ctx := context.Background()
-			_, err := proxy.Verify(ctx, proxyCfg.Bank, proxyCfg.Route, proxyCfg.Wallet, res.Data.ID, proxyCfg.Provider.Cost)
+			_, _, err := proxy.Verify(ctx, proxyCfg.Bank, proxyCfg.Route, proxyCfg.Wallet, res.Data.ID)
 			if err != nil {
 				t.Errorf("failed to verify: %v", err)
 			}
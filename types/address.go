@@ -53,6 +53,22 @@ func ParseAddress(addr string) (string, crypto.SigType, error) {
 	return ethAddr.String(), AddressProtocolToSigType(filAddr.Protocol()), nil
 }
 
+// AddressFromEth returns the Filecoin address of an Ethereum address, an f410 address or the f0 address
+// of a masked ID address.
+func AddressFromEth(addr string) (Address, error) {
+	ethAddr, err := types.ParseEthAddress(addr)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid address format: %w", err)
+	}
+
+	filAddr, err := ethAddr.ToFilecoinAddress()
+	if err != nil {
+		return Address{}, fmt.Errorf("failed to convert to filecoin address: %w", err)
+	}
+
+	return Address{Address: &filAddr}, nil
+}
+
 func (a *Address) UnmarshalText(value []byte) error {
 	addr, err := address.NewFromString(string(value))
 	a.Address = &addr
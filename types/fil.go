@@ -16,15 +16,25 @@ func (b *FIL) Scan(value any) error {
 		b = nil
 	}
 
-	v, ok := value.([]byte)
-	if !ok {
+	var v string
+	switch value := value.(type) {
+	case []byte:
+		v = string(value)
+	case string:
+		v = value
+	default:
 		return fmt.Errorf("could not scan type %T into FIL", value)
 	}
 
-	b.Int, ok = new(big.Int).SetString(string(v), 10)
+	var ok bool
+	b.Int, ok = new(big.Int).SetString(v, 10)
 	if !ok {
 		return fmt.Errorf("failed to load value to []uint8: %v", value)
 	}
 
 	return nil
 }
+
+func NewFIL(value *big.Int) FIL {
+	return FIL{FIL: types.FIL{Int: new(big.Int).Set(value)}}
+}
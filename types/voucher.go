@@ -0,0 +1,55 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Voucher is a client's promise to pay a storage provider up to a cumulative amount from a payment channel.
+// Every new voucher of a channel must carry a larger amount than the previous one.
+type Voucher struct {
+	Channel uuid.UUID `json:"channel"`
+	Amount  FIL       `json:"amount"`
+}
+
+// Message returns the bytes the client signs for the voucher.
+func (v Voucher) Message() []byte {
+	return []byte(fmt.Sprintf("fidl-voucher\n%s\n%s", v.Channel, v.Amount.Int))
+}
+
+type SignedVoucher struct {
+	Voucher
+	Signature []byte `json:"sig"`
+}
+
+// Encode returns the voucher as an URL safe token.
+func (v SignedVoucher) Encode() (string, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal voucher: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func DecodeVoucher(token string) (SignedVoucher, error) {
+	var v SignedVoucher
+
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return SignedVoucher{}, fmt.Errorf("failed to decode voucher: %w", err)
+	}
+
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return SignedVoucher{}, fmt.Errorf("failed to unmarshal voucher: %w", err)
+	}
+
+	if v.Amount.Int == nil || v.Amount.Sign() != 1 {
+		return SignedVoucher{}, fmt.Errorf("invalid voucher amount")
+	}
+
+	return v, nil
+}